@@ -0,0 +1,94 @@
+package audit_log
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedact(t *testing.T) {
+	input := json.RawMessage(`{"action":"transfer","params":{"source":"5MaiiCavjCmn9Hs1o3eznqDEhRwxo7pXiAYez7keQUviUkauRiTMD8DrESdrNjN8zd9mTmVhRvBJeg5vhyvgrAhG","destination":"abc","amount":1}}`)
+
+	redactedInput := string(Redact(input))
+	if strings.Contains(redactedInput, "5Maii") {
+		t.Fatalf("private key was not redacted: %s", redactedInput)
+	}
+	if !strings.Contains(redactedInput, `"destination":"abc"`) {
+		t.Errorf("non-secret field was changed: %s", redactedInput)
+	}
+
+	if got := string(Redact(json.RawMessage(`{not json`))); got != `"[REDACTED]"` {
+		t.Errorf("invalid input = %s, want redacted string", got)
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	records := []Record{
+		{ID: "1", Time: base, Action: "buy", Signer: "alice", Mint: "mintA", Status: StatusConfirmed},
+		{ID: "2", Time: base.Add(time.Hour), Action: "sell", Signer: "alice", Mint: "mintB", Status: StatusFailed},
+		{ID: "3", Time: base.Add(2 * time.Hour), Action: "buy", Signer: "bob", Mint: "mintA", Status: StatusConfirmed,
+			Fill: []Fill{{Protocol: "Jupiter", InputMint: "sol", InAmount: 1, OutputMint: "mintA", OutAmount: 2}}},
+	}
+	for _, record := range records {
+		if err := store.Append(ctx, record); err != nil {
+			t.Fatalf("failed to append record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all newest first", Query{}, []string{"3", "2", "1"}},
+		{"by wallet", Query{Wallet: "alice"}, []string{"2", "1"}},
+		{"by mint", Query{Mint: "mintA"}, []string{"3", "1"}},
+		{"by time range", Query{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)}, []string{"2"}},
+		{"with limit", Query{Limit: 1}, []string{"3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(got), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got[i].ID != id {
+					t.Errorf("record %d = %s, want %s", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+
+	got, err := store.Query(ctx, Query{Wallet: "bob"})
+	if err != nil || len(got) != 1 || len(got[0].Fill) != 1 || got[0].Fill[0].OutAmount != 2 {
+		t.Errorf("fill did not round trip: %+v, %v", got, err)
+	}
+}
+
+func TestJSONLStore(t *testing.T) {
+	store := NewJSONLStore(filepath.Join(t.TempDir(), "audit.jsonl"))
+	defer store.Close()
+
+	testStore(t, store)
+}
+
+func TestSQLiteStore(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	testStore(t, store)
+}
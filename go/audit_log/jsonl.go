@@ -0,0 +1,89 @@
+package audit_log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// JSONLStore appends records to a file, one JSON object per line. Queries
+// scan the whole file.
+type JSONLStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewJSONLStore(path string) *JSONLStore {
+	return &JSONLStore{path: path}
+}
+
+func (s *JSONLStore) Append(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+func (s *JSONLStore) Query(ctx context.Context, query Query) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit record: %w", err)
+		}
+		if query.Match(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.After(records[j].Time)
+	})
+	if query.Limit > 0 && len(records) > query.Limit {
+		records = records[:query.Limit]
+	}
+	return records, nil
+}
+
+func (s *JSONLStore) Close() error {
+	return nil
+}
@@ -0,0 +1,50 @@
+package audit_log
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretKeys are input fields that hold private keys
+var secretKeys = map[string]bool{
+	"source":      true,
+	"fee_payer":   true,
+	"private_key": true,
+	"secret_key":  true,
+	"signer":      true,
+}
+
+// Redact returns the input JSON with every secret field replaced. Input that
+// is not valid JSON is dropped entirely.
+func Redact(input json.RawMessage) json.RawMessage {
+	var value interface{}
+	if err := json.Unmarshal(input, &value); err != nil {
+		return json.RawMessage(`"` + redacted + `"`)
+	}
+
+	out, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return json.RawMessage(`"` + redacted + `"`)
+	}
+	return out
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if secretKeys[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return value
+}
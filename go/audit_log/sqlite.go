@@ -0,0 +1,127 @@
+package audit_log
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS audit_records (
+    id        TEXT PRIMARY KEY,
+    time      INTEGER NOT NULL,
+    action    TEXT NOT NULL,
+    signer    TEXT NOT NULL,
+    mint      TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    status    TEXT NOT NULL,
+    record    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_records_signer_time ON audit_records (signer, time);
+CREATE INDEX IF NOT EXISTS audit_records_mint_time ON audit_records (mint, time);
+CREATE INDEX IF NOT EXISTS audit_records_time ON audit_records (time);
+`
+
+// SQLiteStore keeps records in a SQLite database, indexed by wallet, mint and time
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens or creates the database at path
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit database: %w", err)
+	}
+	// SQLite allows a single writer
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create audit schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Append(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO audit_records (id, time, action, signer, mint, signature, status, record) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.ID, record.Time.UnixNano(), record.Action, record.Signer, record.Mint, record.Signature, string(record.Status), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit record: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Query(ctx context.Context, query Query) ([]Record, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if query.Wallet != "" {
+		conditions = append(conditions, "signer = ?")
+		args = append(args, query.Wallet)
+	}
+	if query.Mint != "" {
+		conditions = append(conditions, "mint = ?")
+		args = append(args, query.Mint)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, "time >= ?")
+		args = append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "time < ?")
+		args = append(args, query.To.UnixNano())
+	}
+
+	statement := "SELECT record FROM audit_records"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += " ORDER BY time DESC"
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read audit record: %w", err)
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
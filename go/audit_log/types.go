@@ -0,0 +1,109 @@
+package audit_log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusNotSent means the action failed before a transaction was sent
+	StatusNotSent Status = "not_sent"
+	// StatusUnconfirmed means the transaction was sent but not seen confirmed
+	StatusUnconfirmed Status = "unconfirmed"
+	StatusConfirmed   Status = "confirmed"
+	StatusFailed      Status = "failed"
+)
+
+// Record is one executed onchain action
+type Record struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+
+	// Input is the tool input with secrets redacted
+	Input  json.RawMessage `json:"input"`
+	Signer string          `json:"signer,omitempty"`
+	Mint   string          `json:"mint,omitempty"`
+
+	// Transaction is the signed transaction, base64 encoded
+	Transaction string      `json:"transaction,omitempty"`
+	Simulation  *Simulation `json:"simulation,omitempty"`
+	Signature   string      `json:"signature,omitempty"`
+	Status      Status      `json:"status"`
+	Fill        []Fill      `json:"fill,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// Simulation is the result of simulating the transaction before it was sent
+type Simulation struct {
+	Err           string   `json:"err,omitempty"`
+	Logs          []string `json:"logs,omitempty"`
+	UnitsConsumed *uint64  `json:"units_consumed,omitempty"`
+}
+
+// Fill is one swap parsed from the confirmed transaction
+type Fill struct {
+	Protocol   string `json:"protocol"`
+	InputMint  string `json:"input_mint"`
+	InAmount   uint64 `json:"in_amount"`
+	OutputMint string `json:"output_mint"`
+	OutAmount  uint64 `json:"out_amount"`
+}
+
+// Query selects records. Empty fields match everything, From is inclusive
+// and To is exclusive.
+type Query struct {
+	Wallet string
+	Mint   string
+	From   time.Time
+	To     time.Time
+	// Limit caps the number of records returned, newest first. Zero means no limit.
+	Limit int
+}
+
+// Match reports whether a record is selected by the query
+func (q Query) Match(record Record) bool {
+	if q.Wallet != "" && record.Signer != q.Wallet {
+		return false
+	}
+	if q.Mint != "" && record.Mint != q.Mint {
+		return false
+	}
+	if !q.From.IsZero() && record.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !record.Time.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+// Store persists audit records
+type Store interface {
+	Append(ctx context.Context, record Record) error
+	// Query returns matching records, newest first
+	Query(ctx context.Context, query Query) ([]Record, error)
+	Close() error
+}
+
+// NewRecord starts a record for an action with its input redacted
+func NewRecord(action string, input json.RawMessage) (Record, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Record{}, fmt.Errorf("failed to generate record id: %w", err)
+	}
+
+	return Record{
+		ID:     hex.EncodeToString(id),
+		Time:   time.Now().UTC(),
+		Action: action,
+		Input:  Redact(input),
+		Status: StatusNotSent,
+	}, nil
+}
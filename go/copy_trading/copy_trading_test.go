@@ -0,0 +1,215 @@
+package copy_trading
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/ilkamo/jupiter-go/jupiter"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+type fakeSwapper struct {
+	requests []jupiter.GetQuoteParams
+}
+
+func (s *fakeSwapper) Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error) {
+	s.requests = append(s.requests, quoteRequest)
+	return &solana.Signature{1}, nil
+}
+
+func newTestEngine(t *testing.T, config Config, swapper *fakeSwapper) *Engine {
+	e, err := New(nil, swapper, solana.NewWallet().PrivateKey, config)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	e.tokenBalance = func(ctx context.Context, wallet, mint solana.PublicKey) (uint64, error) {
+		return 1_000, nil
+	}
+	return e
+}
+
+func TestSizing(t *testing.T) {
+	tests := []struct {
+		name   string
+		sizing Sizing
+		leader uint64
+		want   uint64
+	}{
+		{"fixed", Sizing{Mode: SizingFixed, FixedSol: 0.1}, 5_000_000_000, 100_000_000},
+		{"proportional", Sizing{Mode: SizingProportional, Ratio: 0.5}, 2_000_000_000, 1_000_000_000},
+		{"capped under", Sizing{Mode: SizingCapped, Ratio: 0.5, MaxSol: 1}, 1_000_000_000, 500_000_000},
+		{"capped over", Sizing{Mode: SizingCapped, Ratio: 0.5, MaxSol: 1}, 10_000_000_000, 1_000_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sizing.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+			if got := tt.sizing.BuyLamports(tt.leader); got != tt.want {
+				t.Errorf("BuyLamports() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if err := (Sizing{Mode: SizingCapped, Ratio: 1}).Validate(); err == nil {
+		t.Error("expected capped sizing without MaxSol to fail validation")
+	}
+}
+
+func TestSellAmount(t *testing.T) {
+	if got := SellAmount(25, 100, 1_000); got != 250 {
+		t.Errorf("partial sell = %d, want 250", got)
+	}
+	if got := SellAmount(100, 100, 1_000); got != 1_000 {
+		t.Errorf("full sell = %d, want 1000", got)
+	}
+	if got := SellAmount(10, 0, 1_000); got != 0 {
+		t.Errorf("unknown leader balance = %d, want 0", got)
+	}
+}
+
+func TestPlanAndExecute(t *testing.T) {
+	leader := solana.NewWallet().PublicKey()
+	other := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	denied := solana.NewWallet().PublicKey()
+
+	swapper := &fakeSwapper{}
+	e := newTestEngine(t, Config{
+		Leaders:   []solana.PublicKey{leader},
+		Sizing:    Sizing{Mode: SizingProportional, Ratio: 0.1},
+		DenyMints: []solana.PublicKey{denied},
+		MaxDelay:  30 * time.Second,
+	}, swapper)
+
+	swaps := []*tx_parser.SwapInfo{
+		{
+			Signers:  []solana.PublicKey{leader},
+			TokenIn:  tx_parser.TokenInfo{Mint: solana.SolMint, Amount: 1_000_000_000},
+			TokenOut: tx_parser.TokenInfo{Mint: mint, Amount: 500},
+		},
+		{
+			Signers:  []solana.PublicKey{leader},
+			TokenIn:  tx_parser.TokenInfo{Mint: mint, Amount: 50},
+			TokenOut: tx_parser.TokenInfo{Mint: solana.SolMint, Amount: 100_000_000},
+		},
+		// Not signed by the leader
+		{
+			Signers:  []solana.PublicKey{other},
+			TokenIn:  tx_parser.TokenInfo{Mint: solana.SolMint, Amount: 1},
+			TokenOut: tx_parser.TokenInfo{Mint: mint, Amount: 1},
+		},
+		// Token to token swaps are not mirrored
+		{
+			Signers:  []solana.PublicKey{leader},
+			TokenIn:  tx_parser.TokenInfo{Mint: denied, Amount: 1},
+			TokenOut: tx_parser.TokenInfo{Mint: mint, Amount: 1},
+		},
+	}
+
+	meta := &rpc.TransactionMeta{
+		PreTokenBalances: []rpc.TokenBalance{
+			{Mint: mint, Owner: &leader, UiTokenAmount: &rpc.UiTokenAmount{Amount: "200"}},
+			{Mint: mint, Owner: &other, UiTokenAmount: &rpc.UiTokenAmount{Amount: "900"}},
+		},
+	}
+
+	trades := e.plan(leader, swaps, meta)
+	if len(trades) != 2 {
+		t.Fatalf("expected 2 trades, got %d", len(trades))
+	}
+	if trades[0].side != SideBuy || trades[0].leaderAmount != 1_000_000_000 {
+		t.Errorf("unexpected buy trade: %+v", trades[0])
+	}
+	if trades[1].side != SideSell || trades[1].leaderBalance != 200 {
+		t.Errorf("unexpected sell trade: %+v", trades[1])
+	}
+
+	var mirrors []Mirror
+	for _, trade := range trades {
+		mirror := Mirror{Side: trade.side, Mint: trade.mint}
+		e.execute(context.Background(), trade, &mirror)
+		mirrors = append(mirrors, mirror)
+	}
+
+	if mirrors[0].Amount != 100_000_000 || swapper.requests[0].InputMint != solana.SolMint.String() {
+		t.Errorf("unexpected buy mirror: %+v", mirrors[0])
+	}
+	// The leader sold a quarter of their position
+	if mirrors[1].Amount != 250 || swapper.requests[1].OutputMint != solana.SolMint.String() {
+		t.Errorf("unexpected sell mirror: %+v", mirrors[1])
+	}
+
+	// Without an owner in the meta the leader's balance is unknown, which
+	// must not be taken for a full exit
+	ownerless := &rpc.TransactionMeta{
+		PreTokenBalances: []rpc.TokenBalance{
+			{Mint: mint, UiTokenAmount: &rpc.UiTokenAmount{Amount: "200"}},
+		},
+	}
+	sell := e.plan(leader, swaps[1:2], ownerless)[0]
+	mirror := Mirror{Side: sell.side, Mint: sell.mint}
+	e.execute(context.Background(), sell, &mirror)
+	if mirror.Skipped == "" || mirror.Amount != 0 || len(swapper.requests) != 2 {
+		t.Errorf("expected sell with unknown leader balance to be skipped: %+v", mirror)
+	}
+}
+
+func TestSkipReason(t *testing.T) {
+	allowed := solana.NewWallet().PublicKey()
+	denied := solana.NewWallet().PublicKey()
+
+	e := newTestEngine(t, Config{
+		Leaders:    []solana.PublicKey{solana.NewWallet().PublicKey()},
+		Sizing:     Sizing{Mode: SizingFixed, FixedSol: 0.1},
+		AllowMints: []solana.PublicKey{allowed, denied},
+		DenyMints:  []solana.PublicKey{denied},
+		MaxDelay:   10 * time.Second,
+	}, &fakeSwapper{})
+
+	if reason := e.skipReason(allowed, time.Second); reason != "" {
+		t.Errorf("expected allowed mint to pass, got %q", reason)
+	}
+	if reason := e.skipReason(denied, time.Second); reason == "" {
+		t.Error("expected denied mint to be skipped")
+	}
+	if reason := e.skipReason(solana.NewWallet().PublicKey(), time.Second); reason == "" {
+		t.Error("expected mint outside the allow list to be skipped")
+	}
+	if reason := e.skipReason(allowed, time.Minute); reason == "" {
+		t.Error("expected stale trade to be skipped")
+	}
+}
+
+func TestSeenIsBounded(t *testing.T) {
+	e := newTestEngine(t, Config{
+		Leaders: []solana.PublicKey{solana.NewWallet().PublicKey()},
+		Sizing:  Sizing{Mode: SizingFixed, FixedSol: 0.1},
+	}, &fakeSwapper{})
+
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	for i := 0; i < maxSeenEntries; i++ {
+		var sig solana.Signature
+		binary.BigEndian.PutUint32(sig[60:], uint32(i))
+		e.markSeen(sig)
+	}
+
+	// A full set evicts the oldest entry, then everything past the TTL
+	now = now.Add(time.Second)
+	e.markSeen(solana.Signature{1})
+	if len(e.seen) != maxSeenEntries {
+		t.Fatalf("expected %d seen signatures, got %d", maxSeenEntries, len(e.seen))
+	}
+
+	now = now.Add(seenTTL)
+	e.markSeen(solana.Signature{2})
+	if len(e.seen) != 2 {
+		t.Errorf("expected expired signatures to be pruned, got %d", len(e.seen))
+	}
+}
@@ -0,0 +1,450 @@
+package copy_trading
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+	"github.com/ilkamo/jupiter-go/jupiter"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+const (
+	// seenTTL is how long a handled signature is remembered. Polling only
+	// asks for signatures after the newest one, so duplicates come from the
+	// stream and polling overlapping within seconds.
+	seenTTL        = time.Hour
+	maxSeenEntries = 10_000
+)
+
+// Swapper executes mirrored trades. OnchainActionsTool satisfies it.
+type Swapper interface {
+	Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error)
+}
+
+// Engine follows a set of leader wallets and mirrors their SOL<>token swaps
+// from the follower wallet
+type Engine struct {
+	mu sync.Mutex
+
+	rpcClient *rpc.Client
+	swapper   Swapper
+	follower  solana.PrivateKey
+	config    Config
+
+	allow map[solana.PublicKey]bool
+	deny  map[solana.PublicKey]bool
+
+	// seen holds leader signatures that were already handled and when
+	seen map[solana.Signature]time.Time
+	// last is the newest polled signature per leader
+	last map[solana.PublicKey]solana.Signature
+
+	onMirror func(Mirror)
+	now      func() time.Time
+
+	// tokenBalance returns the raw balance of a wallet across its token accounts for a mint
+	tokenBalance func(ctx context.Context, wallet, mint solana.PublicKey) (uint64, error)
+}
+
+type Option func(*Engine)
+
+// WithOnMirror sets a callback that receives every mirrored or skipped leader trade
+func WithOnMirror(fn func(Mirror)) Option {
+	return func(e *Engine) {
+		e.onMirror = fn
+	}
+}
+
+// New creates an engine that trades from the follower wallet
+func New(rpcClient *rpc.Client, swapper Swapper, follower solana.PrivateKey, config Config, opts ...Option) (*Engine, error) {
+	if len(config.Leaders) == 0 {
+		return nil, fmt.Errorf("at least one leader is required")
+	}
+	if err := config.Sizing.Validate(); err != nil {
+		return nil, err
+	}
+	if config.SlippageBps <= 0 {
+		config.SlippageBps = 100
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Second
+	}
+
+	e := &Engine{
+		rpcClient: rpcClient,
+		swapper:   swapper,
+		follower:  follower,
+		config:    config,
+		allow:     toSet(config.AllowMints),
+		deny:      toSet(config.DenyMints),
+		seen:      make(map[solana.Signature]time.Time),
+		last:      make(map[solana.PublicKey]solana.Signature),
+		onMirror:  func(Mirror) {},
+		now:       time.Now,
+	}
+	e.tokenBalance = e.getTokenBalance
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e, nil
+}
+
+// Run polls leader signatures until the context is cancelled. Trades made
+// before the first poll are not mirrored.
+func (e *Engine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, leader := range e.config.Leaders {
+			e.poll(ctx, leader)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll handles the leader's signatures newer than the last one seen, oldest first
+func (e *Engine) poll(ctx context.Context, leader solana.PublicKey) {
+	e.mu.Lock()
+	last, started := e.last[leader]
+	e.mu.Unlock()
+
+	opts := &rpc.GetSignaturesForAddressOpts{
+		Commitment: rpc.CommitmentConfirmed,
+	}
+	if started {
+		opts.Until = last
+	} else {
+		limit := 1
+		opts.Limit = &limit
+	}
+
+	signatures, err := e.rpcClient.GetSignaturesForAddressWithOpts(ctx, leader, opts)
+	if err != nil || len(signatures) == 0 {
+		if err == nil && !started {
+			e.mu.Lock()
+			e.last[leader] = solana.Signature{}
+			e.mu.Unlock()
+		}
+		return
+	}
+
+	e.mu.Lock()
+	e.last[leader] = signatures[0].Signature
+	e.mu.Unlock()
+
+	if !started {
+		return
+	}
+
+	for i := len(signatures) - 1; i >= 0; i-- {
+		if signatures[i].Err != nil {
+			continue
+		}
+		e.HandleSignature(ctx, leader, signatures[i].Signature)
+	}
+}
+
+// RunWebsocket subscribes to the logs of every leader and handles each
+// successful transaction as it lands, until the context is cancelled
+func (e *Engine) RunWebsocket(ctx context.Context, wsURL string) error {
+	client, err := ws.Connect(ctx, wsURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect websocket: %w", err)
+	}
+	defer client.Close()
+
+	errs := make(chan error, len(e.config.Leaders))
+	for _, leader := range e.config.Leaders {
+		sub, err := client.LogsSubscribeMentions(leader, rpc.CommitmentConfirmed)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", leader, err)
+		}
+		defer sub.Unsubscribe()
+
+		go func(leader solana.PublicKey, sub *ws.LogSubscription) {
+			for {
+				result, err := sub.Recv(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if result.Value.Err != nil {
+					continue
+				}
+				e.HandleSignature(ctx, leader, result.Value.Signature)
+			}
+		}(leader, sub)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errs:
+		return fmt.Errorf("log subscription failed: %w", err)
+	}
+}
+
+// HandleSignature fetches a leader transaction and mirrors its swaps. Each
+// signature is handled at most once.
+func (e *Engine) HandleSignature(ctx context.Context, leader solana.PublicKey, signature solana.Signature) {
+	e.mu.Lock()
+	if _, ok := e.seen[signature]; ok {
+		e.mu.Unlock()
+		return
+	}
+	e.markSeen(signature)
+	e.mu.Unlock()
+
+	maxSupportedTxVersion := uint64(0)
+	tx, err := e.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxSupportedTxVersion,
+	})
+	if err != nil {
+		e.onMirror(Mirror{
+			Leader:          leader,
+			LeaderSignature: signature.String(),
+			Error:           fmt.Sprintf("failed to get transaction: %v", err),
+		})
+		return
+	}
+
+	e.HandleTransaction(ctx, leader, signature, tx)
+}
+
+// HandleTransaction decodes a leader transaction with tx_parser and mirrors
+// every SOL<>token swap the leader signed
+func (e *Engine) HandleTransaction(ctx context.Context, leader solana.PublicKey, signature solana.Signature, tx *rpc.GetTransactionResult) {
+	if tx == nil || tx.Meta == nil || tx.Meta.Err != nil {
+		return
+	}
+
+	parser, err := tx_parser.New(tx)
+	if err != nil {
+		return
+	}
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		return
+	}
+
+	var delay time.Duration
+	if tx.BlockTime != nil {
+		delay = e.now().Sub(tx.BlockTime.Time())
+	}
+
+	for _, trade := range e.plan(leader, swaps, tx.Meta) {
+		mirror := Mirror{
+			Leader:          leader,
+			LeaderSignature: signature.String(),
+			Mint:            trade.mint,
+			Side:            trade.side,
+			LeaderAmount:    trade.leaderAmount,
+			Delay:           delay,
+		}
+
+		if reason := e.skipReason(trade.mint, delay); reason != "" {
+			mirror.Skipped = reason
+			e.onMirror(mirror)
+			continue
+		}
+
+		e.execute(ctx, trade, &mirror)
+		e.onMirror(mirror)
+	}
+}
+
+// trade is a leader swap reduced to a buy or sell of one mint against SOL
+type trade struct {
+	side         Side
+	mint         solana.PublicKey
+	leaderAmount uint64
+	// leaderBalance is the leader's token balance before a sell, zero when
+	// the transaction doesn't say
+	leaderBalance uint64
+}
+
+// plan keeps the swaps signed by the leader with SOL on one side
+func (e *Engine) plan(leader solana.PublicKey, swaps []*tx_parser.SwapInfo, meta *rpc.TransactionMeta) []trade {
+	var trades []trade
+	for _, swap := range swaps {
+		if !hasSigner(swap.Signers, leader) {
+			continue
+		}
+
+		switch {
+		case swap.TokenIn.Mint.Equals(solana.SolMint) && !swap.TokenOut.Mint.Equals(solana.SolMint):
+			trades = append(trades, trade{
+				side:         SideBuy,
+				mint:         swap.TokenOut.Mint,
+				leaderAmount: swap.TokenIn.Amount,
+			})
+		case swap.TokenOut.Mint.Equals(solana.SolMint) && !swap.TokenIn.Mint.Equals(solana.SolMint):
+			trades = append(trades, trade{
+				side:          SideSell,
+				mint:          swap.TokenIn.Mint,
+				leaderAmount:  swap.TokenIn.Amount,
+				leaderBalance: preTokenBalance(meta, leader, swap.TokenIn.Mint),
+			})
+		}
+	}
+	return trades
+}
+
+// skipReason returns why a trade should not be mirrored, or an empty string
+func (e *Engine) skipReason(mint solana.PublicKey, delay time.Duration) string {
+	if e.deny[mint] {
+		return "mint is denied"
+	}
+	if len(e.allow) > 0 && !e.allow[mint] {
+		return "mint is not allowed"
+	}
+	if e.config.MaxDelay > 0 && delay > e.config.MaxDelay {
+		return fmt.Sprintf("leader trade is %s old", delay.Round(time.Second))
+	}
+	return ""
+}
+
+// execute sizes and sends the follower's trade, recording the result on mirror
+func (e *Engine) execute(ctx context.Context, t trade, mirror *Mirror) {
+	params := jupiter.GetQuoteParams{
+		SlippageBps: &e.config.SlippageBps,
+	}
+
+	switch t.side {
+	case SideBuy:
+		mirror.Amount = e.config.Sizing.BuyLamports(t.leaderAmount)
+		params.InputMint = solana.SolMint.String()
+		params.OutputMint = t.mint.String()
+	case SideSell:
+		if t.leaderBalance == 0 {
+			mirror.Skipped = "leader balance before the sell is unknown"
+			return
+		}
+		balance, err := e.tokenBalance(ctx, e.follower.PublicKey(), t.mint)
+		if err != nil {
+			mirror.Error = fmt.Sprintf("failed to get token balance: %v", err)
+			return
+		}
+		mirror.Amount = SellAmount(t.leaderAmount, t.leaderBalance, balance)
+		params.InputMint = t.mint.String()
+		params.OutputMint = solana.SolMint.String()
+	}
+
+	if mirror.Amount == 0 {
+		mirror.Skipped = "nothing to trade"
+		return
+	}
+	params.Amount = jupiter.AmountParameter(mirror.Amount)
+
+	sig, err := e.swapper.Swap(ctx, params, e.follower)
+	if sig != nil {
+		mirror.Signature = sig.String()
+	}
+	if err != nil {
+		mirror.Error = err.Error()
+	}
+}
+
+// getTokenBalance sums the wallet's token accounts for mint, including Token-2022 accounts
+func (e *Engine) getTokenBalance(ctx context.Context, wallet, mint solana.PublicKey) (uint64, error) {
+	accounts, err := e.rpcClient.GetTokenAccountsByOwner(ctx, wallet, &rpc.GetTokenAccountsConfig{
+		Mint: &mint,
+	}, &rpc.GetTokenAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, account := range accounts.Value {
+		data := account.Account.Data.GetBinary()
+		if len(data) < 72 {
+			continue
+		}
+		total += binary.LittleEndian.Uint64(data[64:72])
+	}
+	return total, nil
+}
+
+// preTokenBalance returns the owner's raw balance of mint before the
+// transaction, or zero when the meta has no balance owned by owner
+func preTokenBalance(meta *rpc.TransactionMeta, owner, mint solana.PublicKey) uint64 {
+	if meta == nil {
+		return 0
+	}
+
+	var total uint64
+	for _, balance := range meta.PreTokenBalances {
+		if balance.Owner == nil || !balance.Owner.Equals(owner) || !balance.Mint.Equals(mint) || balance.UiTokenAmount == nil {
+			continue
+		}
+		amount, err := strconv.ParseUint(balance.UiTokenAmount.Amount, 10, 64)
+		if err != nil {
+			continue
+		}
+		total += amount
+	}
+	return total
+}
+
+func hasSigner(signers []solana.PublicKey, key solana.PublicKey) bool {
+	for _, signer := range signers {
+		if signer.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(keys []solana.PublicKey) map[solana.PublicKey]bool {
+	set := make(map[solana.PublicKey]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// markSeen records a handled signature, pruning ones older than seenTTL and
+// then the oldest once the set is full. Callers must hold e.mu.
+func (e *Engine) markSeen(signature solana.Signature) {
+	now := e.now()
+	if len(e.seen) >= maxSeenEntries {
+		for sig, at := range e.seen {
+			if now.Sub(at) > seenTTL {
+				delete(e.seen, sig)
+			}
+		}
+	}
+	if len(e.seen) >= maxSeenEntries {
+		var (
+			oldest   solana.Signature
+			oldestAt time.Time
+		)
+		for sig, at := range e.seen {
+			if oldestAt.IsZero() || at.Before(oldestAt) {
+				oldest, oldestAt = sig, at
+			}
+		}
+		delete(e.seen, oldest)
+	}
+	e.seen[signature] = now
+}
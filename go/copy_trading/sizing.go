@@ -0,0 +1,60 @@
+package copy_trading
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Validate checks that the sizing has the parameters its mode needs
+func (s Sizing) Validate() error {
+	switch s.Mode {
+	case SizingFixed:
+		if s.FixedSol <= 0 {
+			return fmt.Errorf("fixed sizing requires a positive FixedSol")
+		}
+	case SizingProportional:
+		if s.Ratio <= 0 {
+			return fmt.Errorf("proportional sizing requires a positive Ratio")
+		}
+	case SizingCapped:
+		if s.Ratio <= 0 || s.MaxSol <= 0 {
+			return fmt.Errorf("capped sizing requires a positive Ratio and MaxSol")
+		}
+	default:
+		return fmt.Errorf("unsupported sizing mode: %s", s.Mode)
+	}
+	return nil
+}
+
+// BuyLamports returns the lamports to spend mirroring a leader buy of leaderLamports
+func (s Sizing) BuyLamports(leaderLamports uint64) uint64 {
+	switch s.Mode {
+	case SizingFixed:
+		return uint64(s.FixedSol * 1e9)
+	case SizingProportional:
+		return uint64(float64(leaderLamports) * s.Ratio)
+	case SizingCapped:
+		lamports := uint64(float64(leaderLamports) * s.Ratio)
+		if max := uint64(s.MaxSol * 1e9); lamports > max {
+			return max
+		}
+		return lamports
+	}
+	return 0
+}
+
+// SellAmount returns how many tokens the follower sells when the leader sold
+// leaderSold out of leaderBalance, scaled to the follower's balance. Only a
+// known balance that the leader sold entirely is a full exit; an unknown
+// (zero) leader balance sells nothing.
+func SellAmount(leaderSold, leaderBalance, followerBalance uint64) uint64 {
+	if leaderBalance == 0 {
+		return 0
+	}
+	if leaderSold >= leaderBalance {
+		return followerBalance
+	}
+
+	amount := new(big.Int).Mul(new(big.Int).SetUint64(followerBalance), new(big.Int).SetUint64(leaderSold))
+	return amount.Div(amount, new(big.Int).SetUint64(leaderBalance)).Uint64()
+}
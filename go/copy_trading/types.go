@@ -0,0 +1,69 @@
+package copy_trading
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+type SizingMode string
+
+const (
+	// SizingFixed buys a fixed amount of SOL on every leader buy
+	SizingFixed SizingMode = "fixed"
+	// SizingProportional buys Ratio times the SOL the leader spent
+	SizingProportional SizingMode = "proportional"
+	// SizingCapped is proportional sizing limited to MaxSol per trade
+	SizingCapped SizingMode = "capped"
+)
+
+// Sizing decides how much SOL a mirrored buy spends. Sells always sell the
+// same fraction of the follower's position that the leader sold of theirs.
+type Sizing struct {
+	Mode     SizingMode
+	FixedSol float64
+	Ratio    float64
+	MaxSol   float64
+}
+
+type Config struct {
+	Leaders []solana.PublicKey
+	Sizing  Sizing
+
+	// AllowMints, when not empty, restricts mirroring to these mints
+	AllowMints []solana.PublicKey
+	// DenyMints are never mirrored
+	DenyMints []solana.PublicKey
+
+	// MaxDelay skips leader trades older than this when they are seen, zero disables the check
+	MaxDelay    time.Duration
+	SlippageBps int
+
+	// PollInterval is how often leader signatures are polled when not using a websocket
+	PollInterval time.Duration
+}
+
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// Mirror reports the outcome of copying one leader swap
+type Mirror struct {
+	Leader          solana.PublicKey `json:"leader"`
+	LeaderSignature string           `json:"leader_signature"`
+	Mint            solana.PublicKey `json:"mint"`
+	Side            Side             `json:"side"`
+
+	// LeaderAmount is SOL spent for buys and tokens sold for sells, in base units
+	LeaderAmount uint64 `json:"leader_amount"`
+	// Amount is what the follower traded, in the same units
+	Amount uint64 `json:"amount"`
+
+	Delay     time.Duration `json:"delay"`
+	Signature string        `json:"signature,omitempty"`
+	Skipped   string        `json:"skipped,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
+// GetPairInformation returns the Solana pairs that trade the token, deepest
+// first, as TokenPairs filters them
 func GetPairInformation(ctx context.Context, tokenAddress string) ([]PairInformation, error) {
 	client := resty.New()
 
@@ -27,5 +29,5 @@ func GetPairInformation(ctx context.Context, tokenAddress string) ([]PairInforma
 		return nil, err
 	}
 
-	return pairInformationResponse.Pairs, nil
+	return TokenPairs(pairInformationResponse.Pairs, tokenAddress), nil
 }
@@ -0,0 +1,50 @@
+package dexscreener
+
+import (
+	"sort"
+	"strconv"
+)
+
+// TokenPairs keeps the Solana pairs that trade the token, on either side,
+// deepest first. The search endpoint also matches pairs on other chains and
+// pairs that only mention the address.
+func TokenPairs(pairs []PairInformation, tokenAddress string) []PairInformation {
+	out := make([]PairInformation, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair.ChainID != "solana" {
+			continue
+		}
+		if pair.BaseToken.Address != tokenAddress && pair.QuoteToken.Address != tokenAddress {
+			continue
+		}
+		out = append(out, pair)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Liquidity.Usd > out[j].Liquidity.Usd
+	})
+	return out
+}
+
+// TokenPrice returns the price of the token in USD and in the token it is
+// paired with. Prices are of the base token, so pairs where the token is the
+// quote are inverted.
+func (p PairInformation) TokenPrice(tokenAddress string) (usd, native float64) {
+	priceUsd, _ := strconv.ParseFloat(p.PriceUsd, 64)
+	priceNative, _ := strconv.ParseFloat(p.PriceNative, 64)
+
+	if p.BaseToken.Address == tokenAddress {
+		return priceUsd, priceNative
+	}
+	if priceNative == 0 {
+		return 0, 0
+	}
+	return priceUsd / priceNative, 1 / priceNative
+}
+
+// PairedToken returns the address and symbol of the other side of the pair
+func (p PairInformation) PairedToken(tokenAddress string) (address, symbol string) {
+	if p.QuoteToken.Address == tokenAddress {
+		return p.BaseToken.Address, p.BaseToken.Symbol
+	}
+	return p.QuoteToken.Address, p.QuoteToken.Symbol
+}
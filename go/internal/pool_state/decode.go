@@ -0,0 +1,229 @@
+package pool_state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+// Offsets of the fields read from each account, after the discriminator
+const (
+	clmmMint0Offset       = 73
+	clmmMint1Offset       = 105
+	clmmVault0Offset      = 137
+	clmmVault1Offset      = 169
+	clmmDecimals0Offset   = 233
+	clmmDecimals1Offset   = 234
+	clmmTickSpacingOffset = 235
+	clmmLiquidityOffset   = 237
+	clmmSqrtPriceOffset   = 253
+	clmmTickOffset        = 269
+
+	whirlpoolTickSpacingOffset = 41
+	whirlpoolLiquidityOffset   = 49
+	whirlpoolSqrtPriceOffset   = 65
+	whirlpoolTickOffset        = 81
+	whirlpoolMintAOffset       = 101
+	whirlpoolVaultAOffset      = 133
+	whirlpoolMintBOffset       = 181
+	whirlpoolVaultBOffset      = 213
+
+	lbPairActiveIDOffset = 76
+	lbPairBinStepOffset  = 80
+	lbPairMintXOffset    = 88
+	lbPairMintYOffset    = 120
+	lbPairReserveXOffset = 152
+	lbPairReserveYOffset = 184
+
+	binArrayIndexOffset = 8
+	binArrayBinsOffset  = 56
+	binSize             = 144
+)
+
+// Decode decodes a pool account of any supported protocol, told apart by
+// the owning program. AMM v4 and CPMM reserves and the decimals of
+// Whirlpool and DLMM mints are not in the pool account and stay zero, Load
+// fills them in.
+func Decode(address solana.PublicKey, owner solana.PublicKey, data []byte) (*State, error) {
+	var (
+		state *State
+		err   error
+	)
+	switch {
+	case owner.Equals(raydium.AmmV4ProgramID):
+		state, err = decodeAmmV4(data)
+	case owner.Equals(raydium.CpmmProgramID):
+		state, err = decodeCpmm(data)
+	case owner.Equals(RaydiumClmmProgramID):
+		state, err = DecodeClmmPool(data)
+	case owner.Equals(WhirlpoolProgramID):
+		state, err = DecodeWhirlpool(data)
+	case owner.Equals(MeteoraDlmmProgramID):
+		state, err = DecodeLbPair(data)
+	default:
+		return nil, fmt.Errorf("account %s is not a supported pool, owner %s", address, owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state.Address = address
+	return state, nil
+}
+
+func decodeAmmV4(data []byte) (*State, error) {
+	var pool raydium.AmmV4Pool
+	if err := pool.FromBuffer(data); err != nil {
+		return nil, fmt.Errorf("failed to decode AMM v4 pool: %w", err)
+	}
+
+	return &State{
+		Protocol:  ProtocolRaydiumAmmV4,
+		MintA:     pool.BaseMint,
+		MintB:     pool.QuoteMint,
+		VaultA:    pool.BaseVault,
+		VaultB:    pool.QuoteVault,
+		DecimalsA: uint8(pool.BaseDecimal),
+		DecimalsB: uint8(pool.QuoteDecimal),
+	}, nil
+}
+
+func decodeCpmm(data []byte) (*State, error) {
+	var pool raydium.CpmmPool
+	if err := pool.FromBuffer(data); err != nil {
+		return nil, fmt.Errorf("failed to decode CPMM pool: %w", err)
+	}
+
+	return &State{
+		Protocol:  ProtocolRaydiumCpmm,
+		MintA:     pool.Token0Mint,
+		MintB:     pool.Token1Mint,
+		VaultA:    pool.Token0Vault,
+		VaultB:    pool.Token1Vault,
+		DecimalsA: pool.Mint0Decimals,
+		DecimalsB: pool.Mint1Decimals,
+	}, nil
+}
+
+// DecodeClmmPool decodes a Raydium CLMM PoolState account
+func DecodeClmmPool(data []byte) (*State, error) {
+	if len(data) < clmmTickOffset+4 || !bytes.Equal(data[:8], ClmmPoolDiscriminator[:]) {
+		return nil, fmt.Errorf("invalid CLMM pool account")
+	}
+
+	return &State{
+		Protocol:     ProtocolRaydiumClmm,
+		MintA:        publicKeyAt(data, clmmMint0Offset),
+		MintB:        publicKeyAt(data, clmmMint1Offset),
+		VaultA:       publicKeyAt(data, clmmVault0Offset),
+		VaultB:       publicKeyAt(data, clmmVault1Offset),
+		DecimalsA:    data[clmmDecimals0Offset],
+		DecimalsB:    data[clmmDecimals1Offset],
+		TickSpacing:  binary.LittleEndian.Uint16(data[clmmTickSpacingOffset:]),
+		Liquidity:    uint128At(data, clmmLiquidityOffset),
+		SqrtPriceX64: uint128At(data, clmmSqrtPriceOffset),
+		Tick:         int32(binary.LittleEndian.Uint32(data[clmmTickOffset:])),
+	}, nil
+}
+
+// DecodeWhirlpool decodes an Orca Whirlpool account
+func DecodeWhirlpool(data []byte) (*State, error) {
+	if len(data) < whirlpoolVaultBOffset+32 || !bytes.Equal(data[:8], WhirlpoolDiscriminator[:]) {
+		return nil, fmt.Errorf("invalid whirlpool account")
+	}
+
+	return &State{
+		Protocol:     ProtocolWhirlpool,
+		MintA:        publicKeyAt(data, whirlpoolMintAOffset),
+		MintB:        publicKeyAt(data, whirlpoolMintBOffset),
+		VaultA:       publicKeyAt(data, whirlpoolVaultAOffset),
+		VaultB:       publicKeyAt(data, whirlpoolVaultBOffset),
+		TickSpacing:  binary.LittleEndian.Uint16(data[whirlpoolTickSpacingOffset:]),
+		Liquidity:    uint128At(data, whirlpoolLiquidityOffset),
+		SqrtPriceX64: uint128At(data, whirlpoolSqrtPriceOffset),
+		Tick:         int32(binary.LittleEndian.Uint32(data[whirlpoolTickOffset:])),
+	}, nil
+}
+
+// DecodeLbPair decodes a Meteora DLMM LbPair account. Its reserves are
+// token accounts, stored in the vault fields.
+func DecodeLbPair(data []byte) (*State, error) {
+	if len(data) < lbPairReserveYOffset+32 || !bytes.Equal(data[:8], LbPairDiscriminator[:]) {
+		return nil, fmt.Errorf("invalid DLMM pair account")
+	}
+
+	return &State{
+		Protocol:  ProtocolMeteoraDlmm,
+		MintA:     publicKeyAt(data, lbPairMintXOffset),
+		MintB:     publicKeyAt(data, lbPairMintYOffset),
+		VaultA:    publicKeyAt(data, lbPairReserveXOffset),
+		VaultB:    publicKeyAt(data, lbPairReserveYOffset),
+		ActiveBin: int32(binary.LittleEndian.Uint32(data[lbPairActiveIDOffset:])),
+		BinStep:   binary.LittleEndian.Uint16(data[lbPairBinStepOffset:]),
+	}, nil
+}
+
+// DecodeBinArray decodes the reserves of the bins in a DLMM bin array.
+// Empty bins are left out.
+func DecodeBinArray(data []byte) ([]Bin, error) {
+	if len(data) < binArrayBinsOffset+BinsPerArray*binSize || !bytes.Equal(data[:8], BinArrayDiscriminator[:]) {
+		return nil, fmt.Errorf("invalid DLMM bin array account")
+	}
+
+	index := int64(binary.LittleEndian.Uint64(data[binArrayIndexOffset:]))
+
+	var bins []Bin
+	for i := 0; i < BinsPerArray; i++ {
+		offset := binArrayBinsOffset + i*binSize
+		bin := Bin{
+			ID:      int32(index*BinsPerArray + int64(i)),
+			AmountX: binary.LittleEndian.Uint64(data[offset:]),
+			AmountY: binary.LittleEndian.Uint64(data[offset+8:]),
+		}
+		if bin.AmountX > 0 || bin.AmountY > 0 {
+			bins = append(bins, bin)
+		}
+	}
+	return bins, nil
+}
+
+// BinArrayIndex is the index of the bin array holding a bin
+func BinArrayIndex(binID int32) int64 {
+	index := int64(binID) / BinsPerArray
+	if binID < 0 && int64(binID)%BinsPerArray != 0 {
+		index--
+	}
+	return index
+}
+
+// DeriveBinArray derives the address of a DLMM pair's bin array
+func DeriveBinArray(lbPair solana.PublicKey, index int64) (solana.PublicKey, error) {
+	indexBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(indexBytes, uint64(index))
+
+	address, _, err := solana.FindProgramAddress(
+		[][]byte{[]byte(binArraySeed), lbPair.Bytes(), indexBytes},
+		MeteoraDlmmProgramID,
+	)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive bin array address: %w", err)
+	}
+	return address, nil
+}
+
+func publicKeyAt(data []byte, offset int) solana.PublicKey {
+	return solana.PublicKeyFromBytes(data[offset : offset+32])
+}
+
+// uint128At reads a little-endian u128
+func uint128At(data []byte, offset int) *big.Int {
+	be := make([]byte, 16)
+	for i := 0; i < 16; i++ {
+		be[15-i] = data[offset+i]
+	}
+	return new(big.Int).SetBytes(be)
+}
@@ -0,0 +1,149 @@
+package pool_state
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+// mintDecimalsOffset is where a mint account stores its decimals
+const mintDecimalsOffset = 44
+
+// Load fetches a pool and what its price and depth need beyond the pool
+// account: the reserves of AMM v4 and CPMM pools, the mint decimals of
+// Whirlpool and DLMM pools and the DLMM bins within the largest of
+// DepthPercents.
+func Load(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey) (*State, error) {
+	account, err := rpcClient.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool account: %w", err)
+	}
+
+	owner := account.Value.Owner
+	if owner.Equals(raydium.AmmV4ProgramID) || owner.Equals(raydium.CpmmProgramID) {
+		pool, err := raydium.LoadPool(ctx, rpcClient, address, account.Value)
+		if err != nil {
+			return nil, err
+		}
+		return FromRaydiumPool(pool), nil
+	}
+
+	state, err := Decode(address, owner, account.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Protocol == ProtocolWhirlpool || state.Protocol == ProtocolMeteoraDlmm {
+		if err := state.loadDecimals(ctx, rpcClient); err != nil {
+			return nil, err
+		}
+	}
+	if state.Protocol == ProtocolMeteoraDlmm {
+		if err := state.loadBins(ctx, rpcClient); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// FromRaydiumPool converts a pool loaded for swapping, reserves included
+func FromRaydiumPool(pool *raydium.Pool) *State {
+	state := &State{
+		Address:  pool.Address,
+		MintA:    pool.MintA,
+		MintB:    pool.MintB,
+		VaultA:   pool.VaultA,
+		VaultB:   pool.VaultB,
+		ReserveA: pool.ReserveA,
+		ReserveB: pool.ReserveB,
+	}
+
+	switch pool.Type {
+	case raydium.PoolTypeAmmV4:
+		state.Protocol = ProtocolRaydiumAmmV4
+		state.DecimalsA = uint8(pool.AmmV4.BaseDecimal)
+		state.DecimalsB = uint8(pool.AmmV4.QuoteDecimal)
+	case raydium.PoolTypeCpmm:
+		state.Protocol = ProtocolRaydiumCpmm
+		state.DecimalsA = pool.Cpmm.Mint0Decimals
+		state.DecimalsB = pool.Cpmm.Mint1Decimals
+	}
+
+	return state
+}
+
+func (s *State) loadDecimals(ctx context.Context, rpcClient *rpc.Client) error {
+	mints, err := rpcClient.GetMultipleAccounts(ctx, s.MintA, s.MintB)
+	if err != nil {
+		return fmt.Errorf("failed to get pool mints: %w", err)
+	}
+
+	decimals := make([]uint8, 2)
+	for i, mint := range mints.Value {
+		if mint == nil {
+			return fmt.Errorf("pool mint not found")
+		}
+		data := mint.Data.GetBinary()
+		if len(data) <= mintDecimalsOffset {
+			return fmt.Errorf("invalid pool mint data")
+		}
+		decimals[i] = data[mintDecimalsOffset]
+	}
+	s.DecimalsA, s.DecimalsB = decimals[0], decimals[1]
+
+	return nil
+}
+
+// binSpan is how many bins the largest depth move crosses. A move down
+// crosses more bins than the same move up.
+func binSpan(binStep uint16) int32 {
+	maxPercent := 0.0
+	for _, percent := range DepthPercents {
+		maxPercent = math.Max(maxPercent, percent)
+	}
+	move := max(math.Log(1+maxPercent/100), -math.Log(1-maxPercent/100))
+	return int32(math.Ceil(move / math.Log(1+float64(binStep)/10_000)))
+}
+
+// loadBins loads the bin arrays covering the largest depth move on either
+// side of the active bin. Arrays nobody initialized hold no liquidity.
+func (s *State) loadBins(ctx context.Context, rpcClient *rpc.Client) error {
+	if s.BinStep == 0 {
+		return fmt.Errorf("DLMM pair %s has no bin step", s.Address)
+	}
+
+	span := binSpan(s.BinStep)
+
+	var addresses []solana.PublicKey
+	for index := BinArrayIndex(s.ActiveBin - span); index <= BinArrayIndex(s.ActiveBin+span); index++ {
+		address, err := DeriveBinArray(s.Address, index)
+		if err != nil {
+			return err
+		}
+		addresses = append(addresses, address)
+	}
+
+	accounts, err := rpcClient.GetMultipleAccounts(ctx, addresses...)
+	if err != nil {
+		return fmt.Errorf("failed to get bin arrays: %w", err)
+	}
+
+	s.Bins = nil
+	for _, account := range accounts.Value {
+		if account == nil {
+			continue
+		}
+		bins, err := DecodeBinArray(account.Data.GetBinary())
+		if err != nil {
+			return err
+		}
+		s.Bins = append(s.Bins, bins...)
+	}
+
+	return nil
+}
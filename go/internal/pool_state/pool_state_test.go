@@ -0,0 +1,408 @@
+package pool_state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+var usdcMint = solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+
+// fixture reads account bytes from testdata. The fixtures are synthetic
+// SOL/USDC pools priced at 150 USDC, see testdata/README.md.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	return data
+}
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Abs(b)
+}
+
+func TestDiscriminators(t *testing.T) {
+	for name, discriminator := range map[string][8]byte{
+		"PoolState": ClmmPoolDiscriminator,
+		"Whirlpool": WhirlpoolDiscriminator,
+		"LbPair":    LbPairDiscriminator,
+		"BinArray":  BinArrayDiscriminator,
+	} {
+		sum := sha256.Sum256([]byte("account:" + name))
+		if [8]byte(sum[:8]) != discriminator {
+			t.Errorf("%s discriminator = %v, want %v", name, discriminator, sum[:8])
+		}
+	}
+}
+
+func TestConcentratedLiquidity(t *testing.T) {
+	whirlpool, err := Decode(solana.NewWallet().PublicKey(), WhirlpoolProgramID, fixture(t, "whirlpool_sol_usdc.b64"))
+	if err != nil {
+		t.Fatalf("failed to decode whirlpool: %v", err)
+	}
+	// Whirlpools don't store decimals, Load reads them from the mints
+	whirlpool.DecimalsA, whirlpool.DecimalsB = 9, 6
+
+	clmm, err := Decode(solana.NewWallet().PublicKey(), RaydiumClmmProgramID, fixture(t, "clmm_sol_usdc.b64"))
+	if err != nil {
+		t.Fatalf("failed to decode CLMM pool: %v", err)
+	}
+
+	for _, state := range []*State{whirlpool, clmm} {
+		if !state.MintA.Equals(solana.SolMint) || !state.MintB.Equals(usdcMint) {
+			t.Errorf("%s mints = %s/%s", state.Protocol, state.MintA, state.MintB)
+		}
+		if state.DecimalsA != 9 || state.DecimalsB != 6 {
+			t.Errorf("%s decimals = %d/%d", state.Protocol, state.DecimalsA, state.DecimalsB)
+		}
+		if state.Tick != -18973 || state.SqrtPriceX64.Uint64() != 7144393258922745856 {
+			t.Errorf("%s tick = %d, sqrt price = %s", state.Protocol, state.Tick, state.SqrtPriceX64)
+		}
+
+		price, err := state.SpotPrice()
+		if err != nil || !near(price, 150, 1e-9) {
+			t.Errorf("%s price = %f, %v, want 150", state.Protocol, price, err)
+		}
+
+		// The tick is the floor of the price's log base 1.0001
+		tickPrice := math.Pow(1.0001, float64(state.Tick)) * 1e3
+		if tickPrice > price || tickPrice*1.0001 <= price {
+			t.Errorf("%s tick %d doesn't hold price %f", state.Protocol, state.Tick, price)
+		}
+
+		// Buying up to the target price adds L * (sqrt(P') - sqrt(P)) of B
+		liquidity, _ := new(big.Float).SetInt(state.Liquidity).Float64()
+		for _, percent := range DepthPercents {
+			depth, err := state.Depth(percent)
+			if err != nil {
+				t.Fatalf("failed to get depth: %v", err)
+			}
+			sqrtPrice := math.Sqrt(price * 1e-3)
+			upSqrt := sqrtPrice + depth.UpB*1e6/liquidity
+			if !near(upSqrt*upSqrt*1e3, price*(1+percent/100), 1e-9) {
+				t.Errorf("%s +%v%% lands at %f", state.Protocol, percent, upSqrt*upSqrt*1e3)
+			}
+			downSqrt := sqrtPrice - depth.DownB*1e6/liquidity
+			if !near(downSqrt*downSqrt*1e3, price*(1-percent/100), 1e-9) {
+				t.Errorf("%s -%v%% lands at %f", state.Protocol, percent, downSqrt*downSqrt*1e3)
+			}
+			if depth.UpA <= 0 || depth.DownA <= 0 {
+				t.Errorf("%s depth = %+v", state.Protocol, depth)
+			}
+		}
+	}
+
+	if _, err := DecodeWhirlpool(fixture(t, "clmm_sol_usdc.b64")); err == nil {
+		t.Error("decoded a CLMM pool as a whirlpool")
+	}
+}
+
+func TestDLMM(t *testing.T) {
+	pair, err := Decode(solana.NewWallet().PublicKey(), MeteoraDlmmProgramID, fixture(t, "lb_pair_sol_usdc.b64"))
+	if err != nil {
+		t.Fatalf("failed to decode DLMM pair: %v", err)
+	}
+	if pair.ActiveBin != -1899 || pair.BinStep != 10 || !pair.MintA.Equals(solana.SolMint) {
+		t.Fatalf("pair = %+v", pair)
+	}
+	pair.DecimalsA, pair.DecimalsB = 9, 6
+
+	// The array holds bins -1960 to -1891: 10 SOL in each bin above the
+	// active one, 1500 USDC in each below, and 5 SOL and 750 USDC in it
+	if index := BinArrayIndex(pair.ActiveBin); index != -28 {
+		t.Fatalf("bin array index = %d, want -28", index)
+	}
+	bins, err := DecodeBinArray(fixture(t, "bin_array_sol_usdc.b64"))
+	if err != nil {
+		t.Fatalf("failed to decode bin array: %v", err)
+	}
+	if len(bins) != BinsPerArray || bins[0].ID != -1960 || bins[len(bins)-1].ID != -1891 {
+		t.Fatalf("bins = %d from %d to %d", len(bins), bins[0].ID, bins[len(bins)-1].ID)
+	}
+	pair.Bins = bins
+
+	price, err := pair.SpotPrice()
+	if err != nil || price > 150 || price < 150/1.001 {
+		t.Errorf("price = %f, %v, want the bin holding 150", price, err)
+	}
+
+	// +1% reaches 9 bins up, of which 8 are loaded. -1% reaches 10 bins down.
+	depth, err := pair.Depth(1)
+	if err != nil {
+		t.Fatalf("failed to get depth: %v", err)
+	}
+	if !near(depth.UpA, 85, 1e-12) || !near(depth.DownB, 15_750, 1e-12) {
+		t.Errorf("depth = %+v, want 85 SOL up and 15750 USDC down", depth)
+	}
+	if !near(depth.UpB/depth.UpA, price*1.0045, 1e-3) {
+		t.Errorf("average buy price = %f", depth.UpB/depth.UpA)
+	}
+
+	// The bins loaded reach the largest move in both directions
+	maxPercent := DepthPercents[len(DepthPercents)-1]
+	for _, step := range []uint16{1, 10, 100} {
+		span := float64(binSpan(step))
+		if up := math.Pow(1+float64(step)/10_000, span); up < 1+maxPercent/100 {
+			t.Errorf("bin step %d: %v bins reach +%.2f%%", step, span, (up-1)*100)
+		}
+		if down := math.Pow(1+float64(step)/10_000, -span); down > 1-maxPercent/100 {
+			t.Errorf("bin step %d: %v bins reach -%.2f%%", step, span, (1-down)*100)
+		}
+	}
+
+	for binID, want := range map[int32]int64{0: 0, 69: 0, 70: 1, -1: -1, -70: -1, -71: -2} {
+		if index := BinArrayIndex(binID); index != want {
+			t.Errorf("BinArrayIndex(%d) = %d, want %d", binID, index, want)
+		}
+	}
+}
+
+func TestConstantProduct(t *testing.T) {
+	state := FromRaydiumPool(&raydium.Pool{
+		Type:     raydium.PoolTypeCpmm,
+		MintA:    solana.SolMint,
+		MintB:    usdcMint,
+		ReserveA: 1_000 * 1e9,
+		ReserveB: 150_000 * 1e6,
+		Cpmm:     &raydium.CpmmPool{Mint0Decimals: 9, Mint1Decimals: 6},
+	})
+
+	summary, err := state.Summary()
+	if err != nil {
+		t.Fatalf("failed to summarize pool: %v", err)
+	}
+	if summary.Protocol != ProtocolRaydiumCpmm || !near(summary.Price, 150, 1e-12) || len(summary.Depth) != len(DepthPercents) {
+		t.Fatalf("summary = %+v", summary)
+	}
+
+	for _, depth := range summary.Depth {
+		x, y := 1_000.0, 150_000.0
+		if after := (y + depth.UpB) / (x - depth.UpA); !near(after, 150*(1+depth.Percent/100), 1e-9) {
+			t.Errorf("+%v%% lands at %f", depth.Percent, after)
+		}
+		if after := (y - depth.DownB) / (x + depth.DownA); !near(after, 150*(1-depth.Percent/100), 1e-9) {
+			t.Errorf("-%v%% lands at %f", depth.Percent, after)
+		}
+		if !near((x-depth.UpA)*(y+depth.UpB), x*y, 1e-9) {
+			t.Errorf("+%v%% breaks the constant product", depth.Percent)
+		}
+	}
+
+	if _, err := (&State{Protocol: ProtocolRaydiumAmmV4}).SpotPrice(); err == nil {
+		t.Error("priced an empty pool")
+	}
+}
+
+// The layouts below follow the programs' IDLs field by field, independently
+// of the decoder's offsets. u128 fields are [2]uint64, low half first.
+
+type whirlpoolLayout struct {
+	Discriminator              [8]byte
+	WhirlpoolsConfig           solana.PublicKey
+	WhirlpoolBump              [1]byte
+	TickSpacing                uint16
+	TickSpacingSeed            [2]byte
+	FeeRate                    uint16
+	ProtocolFeeRate            uint16
+	Liquidity                  [2]uint64
+	SqrtPrice                  [2]uint64
+	TickCurrentIndex           int32
+	ProtocolFeeOwedA           uint64
+	ProtocolFeeOwedB           uint64
+	TokenMintA                 solana.PublicKey
+	TokenVaultA                solana.PublicKey
+	FeeGrowthGlobalA           [2]uint64
+	TokenMintB                 solana.PublicKey
+	TokenVaultB                solana.PublicKey
+	FeeGrowthGlobalB           [2]uint64
+	RewardLastUpdatedTimestamp uint64
+	RewardInfos                [3]struct {
+		Mint, Vault, Authority solana.PublicKey
+		EmissionsPerSecondX64  [2]uint64
+		GrowthGlobalX64        [2]uint64
+	}
+}
+
+type clmmPoolLayout struct {
+	Discriminator                            [8]byte
+	Bump                                     [1]byte
+	AmmConfig, Owner                         solana.PublicKey
+	TokenMint0, TokenMint1                   solana.PublicKey
+	TokenVault0, TokenVault1                 solana.PublicKey
+	ObservationKey                           solana.PublicKey
+	MintDecimals0, MintDecimals1             uint8
+	TickSpacing                              uint16
+	Liquidity                                [2]uint64
+	SqrtPriceX64                             [2]uint64
+	TickCurrent                              int32
+	Padding3, Padding4                       uint16
+	FeeGrowthGlobal0X64, FeeGrowthGlobal1X64 [2]uint64
+	ProtocolFeesToken0, ProtocolFeesToken1   uint64
+	SwapAmounts                              [4][2]uint64
+	Status                                   uint8
+	Padding                                  [7]byte
+	RewardInfos                              [3]struct {
+		RewardState                          uint8
+		OpenTime, EndTime, LastUpdateTime    uint64
+		EmissionsPerSecondX64                [2]uint64
+		RewardTotalEmissioned, RewardClaimed uint64
+		TokenMint, TokenVault, Authority     solana.PublicKey
+		RewardGrowthGlobalX64                [2]uint64
+	}
+	TickArrayBitmap [16]uint64
+	TotalFees       [4]uint64
+	FundFees        [2]uint64
+	OpenTime        uint64
+	RecentEpoch     uint64
+	Padding1        [24]uint64
+	Padding2        [32]uint64
+}
+
+type lbPairLayout struct {
+	Discriminator           [8]byte
+	StaticParameters        [32]byte
+	VariableParameters      [32]byte
+	BumpSeed                [1]byte
+	BinStepSeed             [2]byte
+	PairType                uint8
+	ActiveID                int32
+	BinStep                 uint16
+	Status                  uint8
+	RequireBaseFactorSeed   uint8
+	BaseFactorSeed          [2]byte
+	ActivationType          uint8
+	CreatorPoolOnOffControl uint8
+	TokenXMint, TokenYMint  solana.PublicKey
+	ReserveX, ReserveY      solana.PublicKey
+	ProtocolFee             [2]uint64
+	Padding1                [32]byte
+	RewardInfos             [2]struct {
+		Mint, Vault, Funder                       solana.PublicKey
+		RewardDuration, RewardDurationEnd         uint64
+		RewardRate                                [2]uint64
+		LastUpdateTime                            uint64
+		CumulativeSecondsWithEmptyLiquidityReward uint64
+	}
+	Oracle                   solana.PublicKey
+	BinArrayBitmap           [16]uint64
+	LastUpdatedAt            int64
+	Padding2                 [32]byte
+	PreActivationSwapAddress solana.PublicKey
+	BaseKey                  solana.PublicKey
+	ActivationPoint          uint64
+	PreActivationDuration    uint64
+	Padding3                 [8]byte
+	Padding4                 uint64
+	Creator                  solana.PublicKey
+	TokenMintProgramFlags    [2]uint8
+	Reserved                 [22]byte
+}
+
+type binArrayLayout struct {
+	Discriminator [8]byte
+	Index         int64
+	Version       uint8
+	Padding       [7]byte
+	LbPair        solana.PublicKey
+	Bins          [BinsPerArray]struct {
+		AmountX, AmountY     uint64
+		Price                [2]uint64
+		LiquiditySupply      [2]uint64
+		RewardPerTokenStored [2][2]uint64
+		FeeAmountPerToken    [2][2]uint64
+		AmountIn             [2][2]uint64
+	}
+}
+
+// readLayout reads a fixture into its IDL layout, which must span it exactly
+func readLayout(t *testing.T, data []byte, layout interface{}) {
+	t.Helper()
+	if size := binary.Size(layout); size != len(data) {
+		t.Fatalf("%T is %d bytes, the account is %d", layout, size, len(data))
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, layout); err != nil {
+		t.Fatalf("failed to read %T: %v", layout, err)
+	}
+}
+
+func u128(v [2]uint64) *big.Int {
+	return new(big.Int).Add(new(big.Int).Lsh(new(big.Int).SetUint64(v[1]), 64), new(big.Int).SetUint64(v[0]))
+}
+
+// TestLayouts checks the decoders against the IDL layouts, read from the
+// same fixtures
+func TestLayouts(t *testing.T) {
+	var whirlpool whirlpoolLayout
+	data := fixture(t, "whirlpool_sol_usdc.b64")
+	readLayout(t, data, &whirlpool)
+	state, err := DecodeWhirlpool(data)
+	if err != nil {
+		t.Fatalf("failed to decode whirlpool: %v", err)
+	}
+	if state.MintA != whirlpool.TokenMintA || state.MintB != whirlpool.TokenMintB ||
+		state.VaultA != whirlpool.TokenVaultA || state.VaultB != whirlpool.TokenVaultB ||
+		state.TickSpacing != whirlpool.TickSpacing || state.Tick != whirlpool.TickCurrentIndex ||
+		state.Liquidity.Cmp(u128(whirlpool.Liquidity)) != 0 || state.SqrtPriceX64.Cmp(u128(whirlpool.SqrtPrice)) != 0 {
+		t.Errorf("whirlpool = %+v, layout = %+v", state, whirlpool)
+	}
+
+	var clmm clmmPoolLayout
+	data = fixture(t, "clmm_sol_usdc.b64")
+	readLayout(t, data, &clmm)
+	if state, err = DecodeClmmPool(data); err != nil {
+		t.Fatalf("failed to decode CLMM pool: %v", err)
+	}
+	if state.MintA != clmm.TokenMint0 || state.MintB != clmm.TokenMint1 ||
+		state.VaultA != clmm.TokenVault0 || state.VaultB != clmm.TokenVault1 ||
+		state.DecimalsA != clmm.MintDecimals0 || state.DecimalsB != clmm.MintDecimals1 ||
+		state.TickSpacing != clmm.TickSpacing || state.Tick != clmm.TickCurrent ||
+		state.Liquidity.Cmp(u128(clmm.Liquidity)) != 0 || state.SqrtPriceX64.Cmp(u128(clmm.SqrtPriceX64)) != 0 {
+		t.Errorf("CLMM pool = %+v, layout = %+v", state, clmm)
+	}
+
+	var pair lbPairLayout
+	data = fixture(t, "lb_pair_sol_usdc.b64")
+	readLayout(t, data, &pair)
+	if state, err = DecodeLbPair(data); err != nil {
+		t.Fatalf("failed to decode DLMM pair: %v", err)
+	}
+	if state.MintA != pair.TokenXMint || state.MintB != pair.TokenYMint ||
+		state.VaultA != pair.ReserveX || state.VaultB != pair.ReserveY ||
+		state.ActiveBin != pair.ActiveID || state.BinStep != pair.BinStep {
+		t.Errorf("DLMM pair = %+v, layout = %+v", state, pair)
+	}
+
+	var array binArrayLayout
+	data = fixture(t, "bin_array_sol_usdc.b64")
+	readLayout(t, data, &array)
+	bins, err := DecodeBinArray(data)
+	if err != nil {
+		t.Fatalf("failed to decode bin array: %v", err)
+	}
+	var want []Bin
+	for i, bin := range array.Bins {
+		if bin.AmountX > 0 || bin.AmountY > 0 {
+			want = append(want, Bin{ID: int32(array.Index*BinsPerArray + int64(i)), AmountX: bin.AmountX, AmountY: bin.AmountY})
+		}
+	}
+	if !reflect.DeepEqual(bins, want) {
+		t.Errorf("bins = %+v, want %+v", bins, want)
+	}
+}
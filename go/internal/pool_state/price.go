@@ -0,0 +1,146 @@
+package pool_state
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// q64 is 2^64, the scale of Q64.64 square root prices
+var q64 = new(big.Float).SetMantExp(big.NewFloat(1), 64)
+
+// SpotPrice is the price of one whole MintA in whole MintB
+func (s *State) SpotPrice() (float64, error) {
+	raw, err := s.rawPrice()
+	if err != nil {
+		return 0, err
+	}
+	return raw * math.Pow10(int(s.DecimalsA)-int(s.DecimalsB)), nil
+}
+
+// rawPrice is the price of one raw unit of MintA in raw units of MintB
+func (s *State) rawPrice() (float64, error) {
+	switch s.Protocol {
+	case ProtocolRaydiumAmmV4, ProtocolRaydiumCpmm:
+		if s.ReserveA == 0 {
+			return 0, fmt.Errorf("pool %s has no reserves", s.Address)
+		}
+		return float64(s.ReserveB) / float64(s.ReserveA), nil
+	case ProtocolRaydiumClmm, ProtocolWhirlpool:
+		sqrtPrice, err := s.sqrtPrice()
+		if err != nil {
+			return 0, err
+		}
+		return sqrtPrice * sqrtPrice, nil
+	case ProtocolMeteoraDlmm:
+		return binPrice(s.ActiveBin, s.BinStep), nil
+	default:
+		return 0, fmt.Errorf("unsupported protocol %q", s.Protocol)
+	}
+}
+
+// Depth is the trade that moves the price by percent. Constant product
+// depth is exact. Concentrated liquidity depth assumes the liquidity at the
+// current tick holds over the whole move, DLMM depth counts the loaded bins.
+func (s *State) Depth(percent float64) (Depth, error) {
+	if percent <= 0 || percent >= 100 {
+		return Depth{}, fmt.Errorf("depth percent must be between 0 and 100")
+	}
+	up, down := 1+percent/100, 1-percent/100
+
+	var upA, upB, downA, downB float64
+	switch s.Protocol {
+	case ProtocolRaydiumAmmV4, ProtocolRaydiumCpmm:
+		if s.ReserveA == 0 || s.ReserveB == 0 {
+			return Depth{}, fmt.Errorf("pool %s has no reserves", s.Address)
+		}
+		// x*y stays constant while y/x moves to the target price
+		x, y := float64(s.ReserveA), float64(s.ReserveB)
+		upA, upB = x-x/math.Sqrt(up), y*math.Sqrt(up)-y
+		downA, downB = x/math.Sqrt(down)-x, y-y*math.Sqrt(down)
+	case ProtocolRaydiumClmm, ProtocolWhirlpool:
+		sqrtPrice, err := s.sqrtPrice()
+		if err != nil {
+			return Depth{}, err
+		}
+		liquidity, _ := new(big.Float).SetInt(s.Liquidity).Float64()
+		sqrtUp, sqrtDown := sqrtPrice*math.Sqrt(up), sqrtPrice*math.Sqrt(down)
+		upA, upB = liquidity*(1/sqrtPrice-1/sqrtUp), liquidity*(sqrtUp-sqrtPrice)
+		downA, downB = liquidity*(1/sqrtDown-1/sqrtPrice), liquidity*(sqrtPrice-sqrtDown)
+	case ProtocolMeteoraDlmm:
+		upA, upB, downA, downB = s.binDepth(up, down)
+	default:
+		return Depth{}, fmt.Errorf("unsupported protocol %q", s.Protocol)
+	}
+
+	scaleA, scaleB := math.Pow10(int(s.DecimalsA)), math.Pow10(int(s.DecimalsB))
+	return Depth{
+		Percent: percent,
+		UpA:     upA / scaleA,
+		UpB:     upB / scaleB,
+		DownA:   downA / scaleA,
+		DownB:   downB / scaleB,
+	}, nil
+}
+
+// Summary reports the spot price and the depth at DepthPercents
+func (s *State) Summary() (*Summary, error) {
+	price, err := s.SpotPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Protocol: s.Protocol,
+		Address:  s.Address.String(),
+		MintA:    s.MintA.String(),
+		MintB:    s.MintB.String(),
+		Price:    price,
+		Depth:    make([]Depth, 0, len(DepthPercents)),
+	}
+	for _, percent := range DepthPercents {
+		depth, err := s.Depth(percent)
+		if err != nil {
+			return nil, err
+		}
+		summary.Depth = append(summary.Depth, depth)
+	}
+
+	return summary, nil
+}
+
+func (s *State) sqrtPrice() (float64, error) {
+	if s.SqrtPriceX64 == nil || s.SqrtPriceX64.Sign() == 0 {
+		return 0, fmt.Errorf("pool %s has no price", s.Address)
+	}
+	sqrtPrice, _ := new(big.Float).Quo(new(big.Float).SetInt(s.SqrtPriceX64), q64).Float64()
+	return sqrtPrice, nil
+}
+
+// binDepth walks the bins from the active one outwards. Buying A empties
+// the X of the active and higher bins, selling A the Y of the active and
+// lower bins, each at its own bin price.
+func (s *State) binDepth(up, down float64) (upA, upB, downA, downB float64) {
+	bins := append([]Bin(nil), s.Bins...)
+	sort.Slice(bins, func(i, j int) bool { return bins[i].ID < bins[j].ID })
+
+	active := binPrice(s.ActiveBin, s.BinStep)
+	for _, bin := range bins {
+		price := binPrice(bin.ID, s.BinStep)
+		if bin.ID >= s.ActiveBin && price <= active*up {
+			upA += float64(bin.AmountX)
+			upB += float64(bin.AmountX) * price
+		}
+		if bin.ID <= s.ActiveBin && price >= active*down {
+			downB += float64(bin.AmountY)
+			downA += float64(bin.AmountY) / price
+		}
+	}
+	return upA, upB, downA, downB
+}
+
+// binPrice is the raw price of a DLMM bin, (1 + binStep / 10000) ^ id
+func binPrice(id int32, binStep uint16) float64 {
+	return math.Pow(1+float64(binStep)/10_000, float64(id))
+}
@@ -0,0 +1,102 @@
+package pool_state
+
+import (
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+type Protocol string
+
+const (
+	ProtocolRaydiumAmmV4 Protocol = "raydium_amm_v4"
+	ProtocolRaydiumCpmm  Protocol = "raydium_cpmm"
+	ProtocolRaydiumClmm  Protocol = "raydium_clmm"
+	ProtocolWhirlpool    Protocol = "orca_whirlpool"
+	ProtocolMeteoraDlmm  Protocol = "meteora_dlmm"
+)
+
+var (
+	RaydiumClmmProgramID = solana.MustPublicKeyFromBase58("CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK")
+	WhirlpoolProgramID   = solana.MustPublicKeyFromBase58("whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc")
+	MeteoraDlmmProgramID = solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo")
+
+	// CLMM pools share the Anchor account name, and so the discriminator,
+	// with CPMM pools
+	ClmmPoolDiscriminator  = [8]byte{247, 237, 227, 245, 215, 195, 222, 70}
+	WhirlpoolDiscriminator = [8]byte{63, 149, 209, 12, 225, 128, 99, 9}
+	LbPairDiscriminator    = [8]byte{33, 11, 49, 98, 181, 101, 177, 13}
+	BinArrayDiscriminator  = [8]byte{92, 142, 92, 220, 5, 148, 70, 181}
+)
+
+const (
+	// BinsPerArray is how many DLMM bins one bin array account holds
+	BinsPerArray = 70
+
+	binArraySeed = "bin_array"
+)
+
+// DepthPercents are the price moves State.Summary reports depth for
+var DepthPercents = []float64{1, 2, 5}
+
+// State is the pricing state of a pool of any supported protocol. MintA is
+// priced in MintB: the base and quote of AMM v4, token 0 and 1 of CPMM and
+// CLMM, token A and B of Whirlpool and X and Y of DLMM.
+type State struct {
+	Protocol Protocol
+	Address  solana.PublicKey
+
+	MintA     solana.PublicKey
+	MintB     solana.PublicKey
+	VaultA    solana.PublicKey
+	VaultB    solana.PublicKey
+	DecimalsA uint8
+	DecimalsB uint8
+
+	// ReserveA and ReserveB are the raw vault balances of AMM v4 and CPMM
+	// pools, less fees owed to the protocol
+	ReserveA uint64
+	ReserveB uint64
+
+	// SqrtPriceX64 is the square root of the raw price as Q64.64 and
+	// Liquidity the liquidity in range at Tick, for CLMM and Whirlpool
+	SqrtPriceX64 *big.Int
+	Liquidity    *big.Int
+	Tick         int32
+	TickSpacing  uint16
+
+	// ActiveBin is the DLMM bin trades happen in, each bin a BinStep basis
+	// points apart. Bins are the loaded bins around it, in ID order.
+	ActiveBin int32
+	BinStep   uint16
+	Bins      []Bin
+}
+
+// Bin is a DLMM price bin with its raw reserves. Bins above the active one
+// hold only X, bins below only Y.
+type Bin struct {
+	ID      int32
+	AmountX uint64
+	AmountY uint64
+}
+
+// Depth is the trade that moves the price of MintA by Percent, in whole
+// tokens. Moving up buys A: UpA leaves the pool and UpB enters. Moving down
+// sells A: DownA enters and DownB leaves. Fees are not included.
+type Depth struct {
+	Percent float64 `json:"percent"`
+	UpA     float64 `json:"up_a"`
+	UpB     float64 `json:"up_b"`
+	DownA   float64 `json:"down_a"`
+	DownB   float64 `json:"down_b"`
+}
+
+// Summary is the spot price of MintA in MintB and the depth at DepthPercents
+type Summary struct {
+	Protocol Protocol `json:"protocol"`
+	Address  string   `json:"address"`
+	MintA    string   `json:"mint_a"`
+	MintB    string   `json:"mint_b"`
+	Price    float64  `json:"price"`
+	Depth    []Depth  `json:"depth"`
+}
@@ -0,0 +1,92 @@
+package pumpfun
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+// TokenDecimals are the decimals of every pump.fun mint
+const TokenDecimals = 6
+
+// InitialRealTokenReserves are the tokens sold on a new curve before it completes
+const InitialRealTokenReserves uint64 = 793_100_000_000_000
+
+// bondingCurveCreatorOffset is where newer curves store their creator,
+// after the discriminator, five reserves and the complete flag
+const bondingCurveCreatorOffset = 8 + 5*8 + 1
+
+// BondingCurve is the on-chain state of a pump.fun bonding curve
+type BondingCurve struct {
+	pump.BondingCurve
+	// Creator receives the curve's creator fees. It is zero on curves
+	// created before creator fees existed.
+	Creator solana.PublicKey
+}
+
+// DecodeBondingCurve decodes bonding curve account data. Fields appended by
+// later program versions other than the creator are ignored.
+func DecodeBondingCurve(data []byte) (*BondingCurve, error) {
+	var curve BondingCurve
+	decoder := ag_binary.NewBorshDecoder(data)
+	if err := curve.UnmarshalWithDecoder(decoder); err != nil {
+		return nil, fmt.Errorf("failed to decode bonding curve: %w", err)
+	}
+	if decoder.Remaining() >= solana.PublicKeyLength {
+		if err := decoder.Decode(&curve.Creator); err != nil {
+			return nil, fmt.Errorf("failed to decode bonding curve creator: %w", err)
+		}
+	}
+	return &curve, nil
+}
+
+// GetBondingCurve fetches and decodes the bonding curve of a mint. The error
+// wraps rpc.ErrNotFound when the mint has no bonding curve.
+func GetBondingCurve(ctx context.Context, rpcClient *rpc.Client, mint solana.PublicKey) (*BondingCurve, error) {
+	bondingCurve, _, err := DeriveBondingCurveAddresses(mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive bonding curve address: %w", err)
+	}
+
+	account, err := rpcClient.GetAccountInfo(ctx, bondingCurve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bonding curve account: %w", err)
+	}
+
+	return DecodeBondingCurve(account.Value.Data.GetBinary())
+}
+
+// PriceSol is the current price of one whole token in SOL
+func (c *BondingCurve) PriceSol() float64 {
+	if c.VirtualTokenReserves == 0 {
+		return 0
+	}
+
+	sol := float64(c.VirtualSolReserves) / float64(solana.LAMPORTS_PER_SOL)
+	tokens := float64(c.VirtualTokenReserves) / math.Pow10(TokenDecimals)
+	return sol / tokens
+}
+
+// MarketCapSol values the total token supply at the current price
+func (c *BondingCurve) MarketCapSol() float64 {
+	return c.PriceSol() * float64(c.TokenTotalSupply) / math.Pow10(TokenDecimals)
+}
+
+// Progress is the percentage of the curve's tokens sold, reaching 100 when
+// the curve completes and migrates
+func (c *BondingCurve) Progress() float64 {
+	if c.Complete || c.RealTokenReserves == 0 {
+		return 100
+	}
+	if c.RealTokenReserves >= InitialRealTokenReserves {
+		return 0
+	}
+
+	sold := InitialRealTokenReserves - c.RealTokenReserves
+	return float64(sold) * 100 / float64(InitialRealTokenReserves)
+}
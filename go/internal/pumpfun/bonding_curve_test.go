@@ -0,0 +1,66 @@
+package pumpfun
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+func encodeBondingCurve(t *testing.T, curve pump.BondingCurve) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := curve.MarshalWithEncoder(ag_binary.NewBorshEncoder(&buf)); err != nil {
+		t.Fatalf("failed to encode bonding curve: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeBondingCurve(t *testing.T) {
+	// A freshly created curve, followed by the creator field newer accounts carry
+	data := encodeBondingCurve(t, pump.BondingCurve{
+		VirtualTokenReserves: 1_073_000_000_000_000,
+		VirtualSolReserves:   30_000_000_000,
+		RealTokenReserves:    InitialRealTokenReserves,
+		TokenTotalSupply:     1_000_000_000_000_000,
+	})
+	creator := solana.NewWallet().PublicKey()
+	data = append(data, creator[:]...)
+
+	curve, err := DecodeBondingCurve(data)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if !curve.Creator.Equals(creator) {
+		t.Errorf("creator = %s, want %s", curve.Creator, creator)
+	}
+	if price := curve.PriceSol(); math.Abs(price-2.7959e-8) > 1e-12 {
+		t.Errorf("price = %g, want ~2.7959e-8", price)
+	}
+	if marketCap := curve.MarketCapSol(); math.Abs(marketCap-27.959) > 0.001 {
+		t.Errorf("market cap = %f, want ~27.959", marketCap)
+	}
+	if progress := curve.Progress(); progress != 0 {
+		t.Errorf("progress = %f, want 0", progress)
+	}
+
+	curve.RealTokenReserves = InitialRealTokenReserves / 4
+	if progress := curve.Progress(); progress != 75 {
+		t.Errorf("progress = %f, want 75", progress)
+	}
+
+	curve.Complete = true
+	if progress := curve.Progress(); progress != 100 {
+		t.Errorf("progress of a complete curve = %f, want 100", progress)
+	}
+
+	data[0] = 0
+	if _, err := DecodeBondingCurve(data); err == nil {
+		t.Error("expected discriminator error")
+	}
+}
@@ -3,6 +3,7 @@ package pumpfun
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -12,17 +13,83 @@ import (
 	"github.com/gagliardetto/solana-go/programs/token"
 	"github.com/gagliardetto/solana-go/rpc"
 	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_quote"
 )
 
 func CreateToken(ctx context.Context, request CreateTokenRequest) (*solana.Signature, error) {
+	feePayer := request.UserPrivateKey
+	if request.FeePayer != nil {
+		feePayer = *request.FeePayer
+	}
+
+	tx, err := BuildCreateTokenTransaction(ctx, CreateTokenTransactionRequest{
+		RpcClient:       request.RpcClient,
+		TokenInfo:       request.TokenInfo,
+		Mint:            request.Mint.PublicKey(),
+		User:            request.UserPrivateKey.PublicKey(),
+		FeePayer:        feePayer.PublicKey(),
+		BuyAmount:       request.BuyAmount,
+		SlippagePercent: request.SlippagePercent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign transaction
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if request.UserPrivateKey.PublicKey().Equals(key) {
+			return &request.UserPrivateKey
+		}
+		if request.Mint.PublicKey().Equals(key) {
+			return &request.Mint.PrivateKey
+		}
+		if feePayer.PublicKey().Equals(key) {
+			return &feePayer
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return sendAndConfirm(ctx, request.RpcClient, tx)
+}
+
+// BuildCreateTokenTransaction builds an unsigned create (and optional buy)
+// transaction. It must be signed by the mint, the user and the fee payer.
+func BuildCreateTokenTransaction(ctx context.Context, request CreateTokenTransactionRequest) (*solana.Transaction, error) {
+	if request.BuyAmount < 0 {
+		return nil, fmt.Errorf("invalid buy amount: %v", request.BuyAmount)
+	}
+	if request.SlippagePercent < 0 {
+		return nil, fmt.Errorf("invalid slippage percent: %v", request.SlippagePercent)
+	}
+
+	// The program rejects creates and trades while Global is uninitialized
+	var (
+		global *GlobalAccount
+		err    error
+	)
+	if request.GlobalCache != nil {
+		global, err = request.GlobalCache.Get(ctx)
+	} else {
+		global, err = FetchGlobalAccount(ctx, request.RpcClient)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global account: %w", err)
+	}
+	if err := global.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Derive bonding curve addresses
-	bondingCurve, associatedBondingCurve, err := DeriveBondingCurveAddresses(request.Mint.PublicKey())
+	bondingCurve, associatedBondingCurve, err := DeriveBondingCurveAddresses(request.Mint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive bonding curve addresses: %w", err)
 	}
 
 	// Get token metadata address
-	metadata, _, err := solana.FindTokenMetadataAddress(request.Mint.PublicKey())
+	metadata, _, err := solana.FindTokenMetadataAddress(request.Mint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find token metadata address: %w", err)
 	}
@@ -34,33 +101,27 @@ func CreateToken(ctx context.Context, request CreateTokenRequest) (*solana.Signa
 	}
 
 	// Add compute unit price instruction if available
-	if cupInst, err := getComputeUnitPriceInstruction(ctx, request.RpcClient, request.UserPrivateKey); err == nil {
+	if cupInst, err := getComputeUnitPriceInstruction(ctx, request.RpcClient, request.User); err == nil {
 		instructions = append(instructions, cupInst.Build())
 	}
 
 	// Create token instruction
 	createInst := buildCreateTokenInstruction(
 		request.TokenInfo,
-		request.Mint.PublicKey(),
+		request.Mint,
 		bondingCurve,
 		associatedBondingCurve,
 		metadata,
-		request.UserPrivateKey.PublicKey(),
+		request.User,
 	)
 	instructions = append(instructions, createInst)
 
 	// Add buy instructions if BuyAmount is specified
 	if request.BuyAmount > 0 {
-		// Get global account data
-		global, err := GetGlobalAccount(ctx, request.RpcClient)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get global account: %w", err)
-		}
-
 		buyInstructions, err := buildBuyInstructions(
 			request.RpcClient,
-			request.Mint.PublicKey(),
-			request.UserPrivateKey.PublicKey(),
+			request.Mint,
+			request.User,
 			global,
 			request.BuyAmount,
 			request.SlippagePercent,
@@ -77,32 +138,27 @@ func CreateToken(ctx context.Context, request CreateTokenRequest) (*solana.Signa
 		return nil, fmt.Errorf("failed to get recent blockhash: %w", err)
 	}
 
-	// Build and sign transaction
+	feePayer := request.FeePayer
+	if feePayer.IsZero() {
+		feePayer = request.User
+	}
+
 	tx, err := solana.NewTransaction(
 		instructions,
 		recent.Value.Blockhash,
-		solana.TransactionPayer(request.UserPrivateKey.PublicKey()),
+		solana.TransactionPayer(feePayer),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	// Sign transaction
-	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		if request.UserPrivateKey.PublicKey().Equals(key) {
-			return &request.UserPrivateKey
-		}
-		if request.Mint.PublicKey().Equals(key) {
-			return &request.Mint.PrivateKey
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
-	}
+	return tx, nil
+}
 
+// sendAndConfirm sends a signed transaction and polls until it is confirmed
+func sendAndConfirm(ctx context.Context, rpcClient *rpc.Client, tx *solana.Transaction) (*solana.Signature, error) {
 	// Send transaction
-	sig, err := request.RpcClient.SendTransaction(ctx, tx)
+	sig, err := rpcClient.SendTransaction(ctx, tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send transaction: %w", err)
 	}
@@ -116,7 +172,7 @@ func CreateToken(ctx context.Context, request CreateTokenRequest) (*solana.Signa
 		case <-ctx.Done():
 			return nil, fmt.Errorf("transaction confirmation timed out")
 		default:
-			status, err := request.RpcClient.GetSignatureStatuses(ctx, true, sig)
+			status, err := rpcClient.GetSignatureStatuses(ctx, true, sig)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get signature status: %w", err)
 			}
@@ -195,19 +251,25 @@ func buildBuyInstructions(
 		instructions = append(instructions, ataInstr)
 	}
 
-	buyAmount, err := global.GetInitialBuyPrice(lamports)
+	// The creator of a new token is the user making the initial buy
+	creatorVault, err := DeriveCreatorVault(user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate initial buy amount: %w", err)
+		return nil, fmt.Errorf("failed to derive creator vault: %w", err)
 	}
 
-	// Calculate buffer using provided slippage percentage (using lamports)
-	lamportsWithBuffer := uint64(float64(lamports) * (1 + slippagePercent/100))
+	// Quote the buy on the fresh curve the program will fill it on, fees
+	// included, and bound its cost by the slippage tolerance
+	slippageBps := uint64(math.Round(slippagePercent * 100))
+	quote, err := pumpfun_quote.BuyExactSolIn(global.InitialBondingCurve(), global.Fees(), lamports, slippageBps)
+	if err != nil {
+		return nil, fmt.Errorf("invalid initial buy: %w", err)
+	}
 
 	buyInstr := pump.NewBuyInstruction(
-		buyAmount,
-		lamportsWithBuffer,
+		quote.TokenAmount,
+		quote.MaxSolCost,
 		GlobalPumpFunAddress,
-		PumpFunFeeRecipient,
+		global.FeeRecipient,
 		mint,
 		bondingCurve,
 		associatedBondingCurve,
@@ -215,7 +277,7 @@ func buildBuyInstructions(
 		user,
 		system.ProgramID,
 		token.ProgramID,
-		solana.SysVarRentPubkey,
+		creatorVault,
 		EventAuthority,
 		ProgramID,
 	)
@@ -0,0 +1,226 @@
+package pumpfun
+
+import (
+	"context"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+// CollectCreatorFeeDiscriminator is the anchor discriminator of collect_creator_fee
+var CollectCreatorFeeDiscriminator = [8]byte{20, 22, 86, 123, 198, 28, 219, 132}
+
+// CreatorVaultRentExemption is the balance the creator vault keeps after a
+// claim, the rent-exempt minimum of an account without data
+const CreatorVaultRentExemption uint64 = 890_880
+
+// maxCurveBatch caps the requests sent in one JSON-RPC batch
+const maxCurveBatch = 100
+
+// CreatorFees is the state of a creator's fee vault. The program pools the
+// creator fees of all of a creator's curves in one vault. Fees earned after
+// migration accrue in a separate PumpSwap vault, which is not read here.
+type CreatorFees struct {
+	Creator   solana.PublicKey
+	Vault     solana.PublicKey
+	Balance   uint64
+	Claimable uint64
+}
+
+// CreatedToken is a pump.fun token whose bonding curve names a creator
+type CreatedToken struct {
+	Mint         solana.PublicKey
+	BondingCurve solana.PublicKey
+	Complete     bool
+}
+
+// DeriveCreatorVault returns the PDA that accrues a creator's fees
+func DeriveCreatorVault(creator solana.PublicKey) (solana.PublicKey, error) {
+	vault, _, err := solana.FindProgramAddress([][]byte{[]byte("creator-vault"), creator.Bytes()}, ProgramID)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return vault, nil
+}
+
+// GetCreatorFees reads the balance of a creator's bonding curve vault and how
+// much of it can be claimed. PumpSwap creator fees are not included.
+func GetCreatorFees(ctx context.Context, rpcClient *rpc.Client, creator solana.PublicKey) (*CreatorFees, error) {
+	vault, err := DeriveCreatorVault(creator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive creator vault: %w", err)
+	}
+
+	balance, err := rpcClient.GetBalance(ctx, vault, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get creator vault balance: %w", err)
+	}
+
+	fees := &CreatorFees{
+		Creator: creator,
+		Vault:   vault,
+		Balance: balance.Value,
+	}
+	if fees.Balance > CreatorVaultRentExemption {
+		fees.Claimable = fees.Balance - CreatorVaultRentExemption
+	}
+
+	return fees, nil
+}
+
+// GetCreatedTokens lists the tokens whose bonding curves name creator.
+// Curves created before creator fees existed don't record a creator and are
+// not found, nor are tokens minted with Token-2022.
+func GetCreatedTokens(ctx context.Context, rpcClient *rpc.Client, creator solana.PublicKey) ([]CreatedToken, error) {
+	accounts, err := rpcClient.GetProgramAccountsWithOpts(ctx, ProgramID, &rpc.GetProgramAccountsOpts{
+		Encoding: solana.EncodingBase64,
+		Filters: []rpc.RPCFilter{
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: pump.BondingCurveDiscriminator[:]}},
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: bondingCurveCreatorOffset, Bytes: creator.Bytes()}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bonding curves: %w", err)
+	}
+
+	curves := make([]solana.PublicKey, len(accounts))
+	for i, account := range accounts {
+		curves[i] = account.Pubkey
+	}
+
+	// The curve doesn't store its mint, but its token account does
+	mints, err := getCurveMints(ctx, rpcClient, curves)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]CreatedToken, 0, len(accounts))
+	for _, account := range accounts {
+		curve, err := DecodeBondingCurve(account.Account.Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bonding curve %s: %w", account.Pubkey, err)
+		}
+
+		mint, ok := mints[account.Pubkey]
+		if !ok {
+			continue
+		}
+		tokens = append(tokens, CreatedToken{
+			Mint:         mint,
+			BondingCurve: account.Pubkey,
+			Complete:     curve.Complete,
+		})
+	}
+
+	return tokens, nil
+}
+
+// getCurveMints finds the mint of each bonding curve from its token
+// accounts, asking for up to maxCurveBatch curves per batched request. Only
+// SPL Token accounts are queried, so curves of Token-2022 mints are skipped.
+func getCurveMints(ctx context.Context, rpcClient *rpc.Client, curves []solana.PublicKey) (map[solana.PublicKey]solana.PublicKey, error) {
+	mints := make(map[solana.PublicKey]solana.PublicKey, len(curves))
+	for start := 0; start < len(curves); start += maxCurveBatch {
+		batch := curves[start:min(start+maxCurveBatch, len(curves))]
+
+		requests := make(jsonrpc.RPCRequests, len(batch))
+		for i, curve := range batch {
+			requests[i] = jsonrpc.NewRequest("getTokenAccountsByOwner",
+				curve.String(),
+				rpc.M{"programId": token.ProgramID.String()},
+				rpc.M{"encoding": solana.EncodingBase64},
+			)
+			requests[i].ID = i
+		}
+
+		responses, err := rpcClient.RPCCallBatch(ctx, requests)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bonding curve token accounts: %w", err)
+		}
+
+		for _, response := range responses {
+			var i int
+			if _, err := fmt.Sscan(fmt.Sprint(response.ID), &i); err != nil || i < 0 || i >= len(batch) {
+				continue
+			}
+			if response.Error != nil {
+				return nil, fmt.Errorf("failed to get token accounts of bonding curve %s: %s (code %d)", batch[i], response.Error.Message, response.Error.Code)
+			}
+
+			var result rpc.GetTokenAccountsResult
+			if err := response.GetObject(&result); err != nil {
+				return nil, fmt.Errorf("failed to decode token accounts of bonding curve %s: %w", batch[i], err)
+			}
+
+			for _, tokenAccount := range result.Value {
+				var holding token.Account
+				if err := holding.UnmarshalWithDecoder(ag_binary.NewBinDecoder(tokenAccount.Account.Data.GetBinary())); err != nil {
+					continue
+				}
+
+				// Only the curve's own associated account identifies the mint
+				_, associated, err := DeriveBondingCurveAddresses(holding.Mint)
+				if err != nil || !associated.Equals(tokenAccount.Pubkey) {
+					continue
+				}
+				mints[batch[i]] = holding.Mint
+				break
+			}
+		}
+	}
+
+	return mints, nil
+}
+
+// BuildCollectCreatorFeeTransaction builds an unsigned transaction moving
+// the claimable creator fees to the creator. It must be signed by the
+// creator and the fee payer.
+func BuildCollectCreatorFeeTransaction(ctx context.Context, rpcClient *rpc.Client, creator, feePayer solana.PublicKey) (*solana.Transaction, error) {
+	fees, err := GetCreatorFees(ctx, rpcClient, creator)
+	if err != nil {
+		return nil, err
+	}
+	if fees.Claimable == 0 {
+		return nil, fmt.Errorf("no creator fees to claim for %s", creator)
+	}
+
+	recent, err := rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent blockhash: %w", err)
+	}
+
+	if feePayer.IsZero() {
+		feePayer = creator
+	}
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{buildCollectCreatorFeeInstruction(creator, fees.Vault)},
+		recent.Value.Blockhash,
+		solana.TransactionPayer(feePayer),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+func buildCollectCreatorFeeInstruction(creator, vault solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(
+		ProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(creator).WRITE().SIGNER(),
+			solana.Meta(vault).WRITE(),
+			solana.Meta(system.ProgramID),
+			solana.Meta(EventAuthority),
+			solana.Meta(ProgramID),
+		},
+		CollectCreatorFeeDiscriminator[:],
+	)
+}
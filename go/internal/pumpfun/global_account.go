@@ -2,99 +2,133 @@ package pumpfun
 
 import (
 	"context"
-	"encoding/binary"
 	"fmt"
-	"math/big"
+	"sync"
+	"time"
 
-	"github.com/gagliardetto/solana-go"
+	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go/rpc"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_quote"
 )
 
-// GlobalAccount represents the global state of the pump.fun program
+// GlobalAccountTTL is how long a fetched Global account is reused
+const GlobalAccountTTL = 5 * time.Minute
+
+// GlobalAccount is the global state of the pump.fun program: fee recipient,
+// fee and the reserves every new bonding curve starts with
 type GlobalAccount struct {
-	Discriminator               uint64
-	Initialized                 bool
-	Authority                   solana.PublicKey
-	FeeRecipient                solana.PublicKey
-	InitialVirtualTokenReserves uint64
-	InitialVirtualSolReserves   uint64
-	InitialRealTokenReserves    uint64
-	TokenTotalSupply            uint64
-	FeeBasisPoints              uint64
+	pump.Global
 }
 
-func (g *GlobalAccount) FromBuffer(data []byte) error {
-	if len(data) < 8+1+32+32+8+8+8+8+8 {
-		return fmt.Errorf("buffer too short")
+// DecodeGlobalAccount decodes Global account data
+func DecodeGlobalAccount(data []byte) (*GlobalAccount, error) {
+	var global GlobalAccount
+	if err := global.FromBuffer(data); err != nil {
+		return nil, err
 	}
+	return &global, nil
+}
 
-	g.Discriminator = binary.LittleEndian.Uint64(data[0:8])
-	g.Initialized = data[8] != 0
-	copy(g.Authority[:], data[9:41])
-	copy(g.FeeRecipient[:], data[41:73])
-	g.InitialVirtualTokenReserves = binary.LittleEndian.Uint64(data[73:81])
-	g.InitialVirtualSolReserves = binary.LittleEndian.Uint64(data[81:89])
-	g.InitialRealTokenReserves = binary.LittleEndian.Uint64(data[89:97])
-	g.TokenTotalSupply = binary.LittleEndian.Uint64(data[97:105])
-	g.FeeBasisPoints = binary.LittleEndian.Uint64(data[105:113])
-
+func (g *GlobalAccount) FromBuffer(data []byte) error {
+	if err := g.UnmarshalWithDecoder(ag_binary.NewBorshDecoder(data)); err != nil {
+		return fmt.Errorf("failed to decode global account: %w", err)
+	}
 	return nil
 }
 
-func (g *GlobalAccount) GetInitialBuyPrice(solAmount uint64) (uint64, error) {
-	if solAmount <= 0 {
-		return 0, nil
+// Validate checks that the program parameters allow trading
+func (g *GlobalAccount) Validate() error {
+	if !g.Initialized {
+		return fmt.Errorf("pump.fun global account is not initialized")
 	}
+	if g.FeeRecipient.IsZero() {
+		return fmt.Errorf("pump.fun global account has no fee recipient")
+	}
+	if g.FeeBasisPoints >= 10_000 {
+		return fmt.Errorf("pump.fun fee of %d bps is invalid", g.FeeBasisPoints)
+	}
+	if g.InitialVirtualTokenReserves == 0 || g.InitialVirtualSolReserves == 0 {
+		return fmt.Errorf("pump.fun global account has no initial virtual reserves")
+	}
+	if g.InitialRealTokenReserves > g.InitialVirtualTokenReserves || g.InitialRealTokenReserves > g.TokenTotalSupply {
+		return fmt.Errorf("pump.fun initial real token reserves exceed the virtual reserves or supply")
+	}
+	return nil
+}
 
-	// Use big.Int for calculations to prevent overflow
-	vSol := new(big.Int).SetUint64(g.InitialVirtualSolReserves)
-	vToken := new(big.Int).SetUint64(g.InitialVirtualTokenReserves)
-
-	// Add 5% buffer to solAmount for slippage
-	amount := new(big.Int).SetUint64(solAmount)
-	buffer := new(big.Int).Div(amount, big.NewInt(20)) // 5% = 1/20
-	amount = new(big.Int).Add(amount, buffer)
+// InitialBondingCurve is the state of a bonding curve right after create
+func (g *GlobalAccount) InitialBondingCurve() pump.BondingCurve {
+	return pump.BondingCurve{
+		VirtualTokenReserves: g.InitialVirtualTokenReserves,
+		VirtualSolReserves:   g.InitialVirtualSolReserves,
+		RealTokenReserves:    g.InitialRealTokenReserves,
+		TokenTotalSupply:     g.TokenTotalSupply,
+	}
+}
 
-	// Calculate k = x * y
-	k := new(big.Int).Mul(vSol, vToken)
+// Fees returns the protocol fee for quoting trades with pumpfun_quote
+func (g *GlobalAccount) Fees() pumpfun_quote.Fees {
+	return pumpfun_quote.Fees{ProtocolBps: g.FeeBasisPoints}
+}
 
-	// Calculate new sol reserves: i = x + amount
-	newSolReserves := new(big.Int).Add(vSol, amount)
+// GlobalAccountCache reuses the Global account read through one RPC client
+// for GlobalAccountTTL. Callers own the cache and share it between the
+// builders that read Global.
+type GlobalAccountCache struct {
+	rpcClient *rpc.Client
 
-	// Calculate r = k/i (rounded up)
-	r := new(big.Int).Div(k, newSolReserves)
-	r.Add(r, big.NewInt(1)) // Add 1 to handle division rounding
+	mu        sync.Mutex
+	global    *GlobalAccount
+	fetchedAt time.Time
+}
 
-	// Calculate s = vToken - r
-	s := new(big.Int).Sub(vToken, r)
+func NewGlobalAccountCache(rpcClient *rpc.Client) *GlobalAccountCache {
+	return &GlobalAccountCache{rpcClient: rpcClient}
+}
 
-	// Check if s is negative
-	if s.Sign() < 0 {
-		return 0, fmt.Errorf("negative token amount calculated")
+// Get returns the Global account, fetched at most once per GlobalAccountTTL
+func (c *GlobalAccountCache) Get(ctx context.Context) (*GlobalAccount, error) {
+	c.mu.Lock()
+	if c.global != nil && time.Since(c.fetchedAt) < GlobalAccountTTL {
+		global := *c.global
+		c.mu.Unlock()
+		return &global, nil
 	}
+	c.mu.Unlock()
 
-	// Convert back to uint64, checking for overflow
-	if !s.IsUint64() {
-		return 0, fmt.Errorf("token amount overflow")
+	global, err := FetchGlobalAccount(ctx, c.rpcClient)
+	if err != nil {
+		return nil, err
 	}
 
-	result := s.Uint64()
-	if result < g.InitialRealTokenReserves {
-		return result, nil
-	}
-	return g.InitialRealTokenReserves, nil
+	c.mu.Lock()
+	cached := *global
+	c.global, c.fetchedAt = &cached, time.Now()
+	c.mu.Unlock()
+
+	return global, nil
+}
+
+// Invalidate drops the cached Global account, e.g. after a SetParamsEvent
+func (c *GlobalAccountCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.global = nil
 }
 
-func GetGlobalAccount(ctx context.Context, rpcClient *rpc.Client) (*GlobalAccount, error) {
+// FetchGlobalAccount reads the Global account, bypassing the cache
+func FetchGlobalAccount(ctx context.Context, rpcClient *rpc.Client) (*GlobalAccount, error) {
 	accountInfo, err := rpcClient.GetAccountInfo(ctx, GlobalPumpFunAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get global account info: %w", err)
 	}
 
-	var global GlobalAccount
-	if err := global.FromBuffer(accountInfo.Value.Data.GetBinary()); err != nil {
+	global, err := DecodeGlobalAccount(accountInfo.Value.Data.GetBinary())
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse global account data: %w", err)
 	}
 
-	return &global, nil
+	return global, nil
 }
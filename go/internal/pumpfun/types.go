@@ -46,6 +46,23 @@ type CreateTokenRequest struct {
 	UserPrivateKey  solana.PrivateKey
 	BuyAmount       float64
 	SlippagePercent float64
+	// FeePayer pays the transaction fees instead of the user when set. Rent
+	// for the bonding curve and token accounts is still paid by the user.
+	FeePayer *solana.PrivateKey
+}
+
+// CreateTokenTransactionRequest describes a create transaction by public keys only
+type CreateTokenTransactionRequest struct {
+	RpcClient       *rpc.Client
+	TokenInfo       CreateTokenInformation
+	Mint            solana.PublicKey
+	User            solana.PublicKey
+	FeePayer        solana.PublicKey // pays the transaction fees, defaults to User
+	BuyAmount       float64
+	SlippagePercent float64
+	// GlobalCache optionally reuses the Global account, it is fetched on
+	// every build otherwise
+	GlobalCache *GlobalAccountCache
 }
 
 type CreateTokenInformation struct {
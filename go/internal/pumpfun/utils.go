@@ -2,6 +2,7 @@ package pumpfun
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/gagliardetto/solana-go"
 	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
@@ -41,11 +42,11 @@ func shouldCreateAta(rpcClient *rpc.Client, ata solana.PublicKey) (bool, error)
 	return account == nil, nil
 }
 
-func getComputeUnitPriceInstruction(ctx context.Context, rpcClient *rpc.Client, user solana.PrivateKey) (*computebudget.SetComputeUnitPrice, error) {
+func getComputeUnitPriceInstruction(ctx context.Context, rpcClient *rpc.Client, user solana.PublicKey) (*computebudget.SetComputeUnitPrice, error) {
 	out, err := rpcClient.GetRecentPrioritizationFees(
 		ctx,
 		solana.PublicKeySlice{
-			user.PublicKey(),
+			user,
 			ProgramID,
 			MintAuthority,
 			GlobalPumpFunAddress,
@@ -60,6 +61,9 @@ func getComputeUnitPriceInstruction(ctx context.Context, rpcClient *rpc.Client,
 	if err != nil {
 		return nil, err
 	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no recent prioritization fees")
+	}
 
 	var median uint64
 	for _, fee := range out {
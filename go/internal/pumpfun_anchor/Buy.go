@@ -33,7 +33,7 @@ type Buy struct {
 	//
 	// [8] = [] tokenProgram
 	//
-	// [9] = [] rent
+	// [9] = [WRITE] creatorVault
 	//
 	// [10] = [] eventAuthority
 	//
@@ -160,14 +160,14 @@ func (inst *Buy) GetTokenProgramAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice.Get(8)
 }
 
-// SetRentAccount sets the "rent" account.
-func (inst *Buy) SetRentAccount(rent ag_solanago.PublicKey) *Buy {
-	inst.AccountMetaSlice[9] = ag_solanago.Meta(rent)
+// SetCreatorVaultAccount sets the "creatorVault" account.
+func (inst *Buy) SetCreatorVaultAccount(creatorVault ag_solanago.PublicKey) *Buy {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(creatorVault).WRITE()
 	return inst
 }
 
-// GetRentAccount gets the "rent" account.
-func (inst *Buy) GetRentAccount() *ag_solanago.AccountMeta {
+// GetCreatorVaultAccount gets the "creatorVault" account.
+func (inst *Buy) GetCreatorVaultAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice.Get(9)
 }
 
@@ -251,7 +251,7 @@ func (inst *Buy) Validate() error {
 			return errors.New("accounts.TokenProgram is not set")
 		}
 		if inst.AccountMetaSlice[9] == nil {
-			return errors.New("accounts.Rent is not set")
+			return errors.New("accounts.CreatorVault is not set")
 		}
 		if inst.AccountMetaSlice[10] == nil {
 			return errors.New("accounts.EventAuthority is not set")
@@ -288,7 +288,7 @@ func (inst *Buy) EncodeToTree(parent ag_treeout.Branches) {
 						accountsBranch.Child(ag_format.Meta("                  user", inst.AccountMetaSlice.Get(6)))
 						accountsBranch.Child(ag_format.Meta("         systemProgram", inst.AccountMetaSlice.Get(7)))
 						accountsBranch.Child(ag_format.Meta("          tokenProgram", inst.AccountMetaSlice.Get(8)))
-						accountsBranch.Child(ag_format.Meta("                  rent", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("          creatorVault", inst.AccountMetaSlice.Get(9)))
 						accountsBranch.Child(ag_format.Meta("        eventAuthority", inst.AccountMetaSlice.Get(10)))
 						accountsBranch.Child(ag_format.Meta("               program", inst.AccountMetaSlice.Get(11)))
 					})
@@ -338,7 +338,7 @@ func NewBuyInstruction(
 	user ag_solanago.PublicKey,
 	systemProgram ag_solanago.PublicKey,
 	tokenProgram ag_solanago.PublicKey,
-	rent ag_solanago.PublicKey,
+	creatorVault ag_solanago.PublicKey,
 	eventAuthority ag_solanago.PublicKey,
 	program ag_solanago.PublicKey) *Buy {
 	return NewBuyInstructionBuilder().
@@ -353,7 +353,7 @@ func NewBuyInstruction(
 		SetUserAccount(user).
 		SetSystemProgramAccount(systemProgram).
 		SetTokenProgramAccount(tokenProgram).
-		SetRentAccount(rent).
+		SetCreatorVaultAccount(creatorVault).
 		SetEventAuthorityAccount(eventAuthority).
 		SetProgramAccount(program)
 }
@@ -0,0 +1,250 @@
+package pump
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+var (
+	// Emitted when a coin and its bonding curve are created.
+	Event_Create = ag_binary.TypeID([8]byte{27, 114, 169, 77, 222, 235, 99, 118})
+	// Emitted on every buy and sell.
+	Event_Trade = ag_binary.TypeID([8]byte{189, 219, 127, 211, 78, 230, 97, 238})
+	// Emitted when a bonding curve sells its last token.
+	Event_Complete = ag_binary.TypeID([8]byte{95, 114, 97, 156, 212, 46, 152, 8})
+	// Emitted when the global parameters change.
+	Event_SetParams = ag_binary.TypeID([8]byte{223, 195, 159, 246, 62, 48, 143, 131})
+)
+
+// EventCPIDiscriminator prefixes events emitted through a self-CPI, where
+// they show up as inner instruction data instead of "Program data:" logs.
+var EventCPIDiscriminator = [8]byte{228, 69, 165, 46, 81, 203, 154, 29}
+
+// Sizes of the fields newer program versions append to older event layouts
+const (
+	createEventExtensionSize = 32 + 8 + 4*8
+	tradeEventExtensionSize  = 2*8 + 32 + 2*8 + 32 + 2*8
+)
+
+// EventIDToName returns the name of the event given its ID.
+func EventIDToName(id ag_binary.TypeID) string {
+	switch id {
+	case Event_Create:
+		return "CreateEvent"
+	case Event_Trade:
+		return "TradeEvent"
+	case Event_Complete:
+		return "CompleteEvent"
+	case Event_SetParams:
+		return "SetParamsEvent"
+	default:
+		return ""
+	}
+}
+
+// Event is a decoded program event. Impl is one of *CreateEvent,
+// *TradeEvent, *CompleteEvent or *SetParamsEvent.
+type Event struct {
+	TypeID ag_binary.TypeID
+	Impl   interface{}
+}
+
+// Name returns the name of the event.
+func (e *Event) Name() string {
+	return EventIDToName(e.TypeID)
+}
+
+var eventImpls = map[ag_binary.TypeID]func() ag_binary.BinaryUnmarshaler{
+	Event_Create:    func() ag_binary.BinaryUnmarshaler { return new(CreateEvent) },
+	Event_Trade:     func() ag_binary.BinaryUnmarshaler { return new(TradeEvent) },
+	Event_Complete:  func() ag_binary.BinaryUnmarshaler { return new(CompleteEvent) },
+	Event_SetParams: func() ag_binary.BinaryUnmarshaler { return new(SetParamsEvent) },
+}
+
+// DecodeEvent decodes event data, either as logged after "Program data:" or
+// as self-CPI instruction data starting with EventCPIDiscriminator.
+func DecodeEvent(data []byte) (*Event, error) {
+	if len(data) >= 16 && bytes.Equal(data[:8], EventCPIDiscriminator[:]) {
+		data = data[8:]
+	}
+	if len(data) < 8 {
+		return nil, fmt.Errorf("unable to decode event: data too short")
+	}
+
+	typeID := ag_binary.TypeIDFromBytes(data[:8])
+	newImpl, ok := eventImpls[typeID]
+	if !ok {
+		return nil, fmt.Errorf("unable to decode event: unknown discriminator %v", data[:8])
+	}
+
+	impl := newImpl()
+	if err := impl.UnmarshalWithDecoder(ag_binary.NewBorshDecoder(data)); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", EventIDToName(typeID), err)
+	}
+
+	return &Event{TypeID: typeID, Impl: impl}, nil
+}
+
+// CreateEvent is emitted when a coin is created. Creator, Timestamp and the
+// reserves are only logged by newer program versions; for older events
+// Creator is set to User.
+type CreateEvent struct {
+	Name         string
+	Symbol       string
+	Uri          string
+	Mint         ag_solanago.PublicKey
+	BondingCurve ag_solanago.PublicKey
+	User         ag_solanago.PublicKey
+
+	Creator              ag_solanago.PublicKey
+	Timestamp            int64
+	VirtualTokenReserves uint64
+	VirtualSolReserves   uint64
+	RealTokenReserves    uint64
+	TokenTotalSupply     uint64
+}
+
+func (obj CreateEvent) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	return encodeEvent(encoder, Event_Create,
+		obj.Name, obj.Symbol, obj.Uri, obj.Mint, obj.BondingCurve, obj.User,
+		obj.Creator, obj.Timestamp, obj.VirtualTokenReserves, obj.VirtualSolReserves, obj.RealTokenReserves, obj.TokenTotalSupply,
+	)
+}
+
+func (obj *CreateEvent) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	if err := decodeEvent(decoder, Event_Create,
+		&obj.Name, &obj.Symbol, &obj.Uri, &obj.Mint, &obj.BondingCurve, &obj.User,
+	); err != nil {
+		return err
+	}
+
+	if decoder.Remaining() < createEventExtensionSize {
+		obj.Creator = obj.User
+		return nil
+	}
+	return decodeFields(decoder,
+		&obj.Creator, &obj.Timestamp, &obj.VirtualTokenReserves, &obj.VirtualSolReserves, &obj.RealTokenReserves, &obj.TokenTotalSupply,
+	)
+}
+
+// TradeEvent is emitted on every buy and sell. The real reserves and fee
+// fields are only logged by newer program versions.
+type TradeEvent struct {
+	Mint                 ag_solanago.PublicKey
+	SolAmount            uint64
+	TokenAmount          uint64
+	IsBuy                bool
+	User                 ag_solanago.PublicKey
+	Timestamp            int64
+	VirtualSolReserves   uint64
+	VirtualTokenReserves uint64
+
+	RealSolReserves       uint64
+	RealTokenReserves     uint64
+	FeeRecipient          ag_solanago.PublicKey
+	FeeBasisPoints        uint64
+	Fee                   uint64
+	Creator               ag_solanago.PublicKey
+	CreatorFeeBasisPoints uint64
+	CreatorFee            uint64
+}
+
+func (obj TradeEvent) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	return encodeEvent(encoder, Event_Trade,
+		obj.Mint, obj.SolAmount, obj.TokenAmount, obj.IsBuy, obj.User, obj.Timestamp, obj.VirtualSolReserves, obj.VirtualTokenReserves,
+		obj.RealSolReserves, obj.RealTokenReserves, obj.FeeRecipient, obj.FeeBasisPoints, obj.Fee, obj.Creator, obj.CreatorFeeBasisPoints, obj.CreatorFee,
+	)
+}
+
+func (obj *TradeEvent) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	if err := decodeEvent(decoder, Event_Trade,
+		&obj.Mint, &obj.SolAmount, &obj.TokenAmount, &obj.IsBuy, &obj.User, &obj.Timestamp, &obj.VirtualSolReserves, &obj.VirtualTokenReserves,
+	); err != nil {
+		return err
+	}
+
+	if decoder.Remaining() < tradeEventExtensionSize {
+		return nil
+	}
+	return decodeFields(decoder,
+		&obj.RealSolReserves, &obj.RealTokenReserves, &obj.FeeRecipient, &obj.FeeBasisPoints, &obj.Fee, &obj.Creator, &obj.CreatorFeeBasisPoints, &obj.CreatorFee,
+	)
+}
+
+// CompleteEvent is emitted when a bonding curve sells its last token and
+// becomes ready for migration.
+type CompleteEvent struct {
+	User         ag_solanago.PublicKey
+	Mint         ag_solanago.PublicKey
+	BondingCurve ag_solanago.PublicKey
+	Timestamp    int64
+}
+
+func (obj CompleteEvent) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	return encodeEvent(encoder, Event_Complete, obj.User, obj.Mint, obj.BondingCurve, obj.Timestamp)
+}
+
+func (obj *CompleteEvent) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	return decodeEvent(decoder, Event_Complete, &obj.User, &obj.Mint, &obj.BondingCurve, &obj.Timestamp)
+}
+
+// SetParamsEvent is emitted when the authority changes the global parameters.
+type SetParamsEvent struct {
+	FeeRecipient                ag_solanago.PublicKey
+	InitialVirtualTokenReserves uint64
+	InitialVirtualSolReserves   uint64
+	InitialRealTokenReserves    uint64
+	TokenTotalSupply            uint64
+	FeeBasisPoints              uint64
+}
+
+func (obj SetParamsEvent) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	return encodeEvent(encoder, Event_SetParams,
+		obj.FeeRecipient, obj.InitialVirtualTokenReserves, obj.InitialVirtualSolReserves, obj.InitialRealTokenReserves, obj.TokenTotalSupply, obj.FeeBasisPoints,
+	)
+}
+
+func (obj *SetParamsEvent) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	return decodeEvent(decoder, Event_SetParams,
+		&obj.FeeRecipient, &obj.InitialVirtualTokenReserves, &obj.InitialVirtualSolReserves, &obj.InitialRealTokenReserves, &obj.TokenTotalSupply, &obj.FeeBasisPoints,
+	)
+}
+
+func encodeEvent(encoder *ag_binary.Encoder, typeID ag_binary.TypeID, fields ...interface{}) error {
+	if err := encoder.WriteBytes(typeID[:], false); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if err := encoder.Encode(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeEvent checks the event discriminator and decodes fields in order
+func decodeEvent(decoder *ag_binary.Decoder, typeID ag_binary.TypeID, fields ...interface{}) error {
+	discriminator, err := decoder.ReadTypeID()
+	if err != nil {
+		return err
+	}
+	if !discriminator.Equal(typeID[:]) {
+		return fmt.Errorf(
+			"wrong discriminator: wanted %s, got %s",
+			fmt.Sprint(typeID[:]),
+			fmt.Sprint(discriminator[:]))
+	}
+	return decodeFields(decoder, fields...)
+}
+
+func decodeFields(decoder *ag_binary.Decoder, fields ...interface{}) error {
+	for _, field := range fields {
+		if err := decoder.Decode(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
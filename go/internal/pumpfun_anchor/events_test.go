@@ -0,0 +1,54 @@
+package pump
+
+import (
+	"bytes"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_gofuzz "github.com/gagliardetto/gofuzz"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode_Events(t *testing.T) {
+	fu := ag_gofuzz.New().NilChance(0)
+
+	for _, params := range []interface{}{
+		new(CreateEvent),
+		new(TradeEvent),
+		new(CompleteEvent),
+		new(SetParamsEvent),
+	} {
+		fu.Fuzz(params)
+
+		buf := new(bytes.Buffer)
+		ag_require.NoError(t, ag_binary.NewBorshEncoder(buf).Encode(params))
+
+		// Logged events and self-CPI events decode the same
+		for _, data := range [][]byte{buf.Bytes(), append(EventCPIDiscriminator[:], buf.Bytes()...)} {
+			event, err := DecodeEvent(data)
+			ag_require.NoError(t, err)
+			ag_require.Equal(t, params, event.Impl)
+		}
+	}
+}
+
+func TestDecodeEvent_LegacyLayout(t *testing.T) {
+	event := CreateEvent{Name: "Moon Cat", Symbol: "MCAT", Uri: "https://example.com"}
+	event.User[0] = 1
+
+	// Older program versions stop after the user
+	buf := new(bytes.Buffer)
+	encoder := ag_binary.NewBorshEncoder(buf)
+	ag_require.NoError(t, encodeEvent(encoder, Event_Create, event.Name, event.Symbol, event.Uri, event.Mint, event.BondingCurve, event.User))
+
+	decoded, err := DecodeEvent(buf.Bytes())
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "CreateEvent", decoded.Name())
+
+	create := decoded.Impl.(*CreateEvent)
+	ag_require.Equal(t, event.User, create.Creator)
+	ag_require.Zero(t, create.Timestamp)
+
+	_, err = DecodeEvent(append([]byte{1, 2, 3, 4, 5, 6, 7, 8}, buf.Bytes()[8:]...))
+	ag_require.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package pumpfun_quote
+
+import (
+	"testing"
+
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+var (
+	// newCurve is the state of every curve right after create
+	newCurve = pump.BondingCurve{
+		VirtualTokenReserves: 1_073_000_000_000_000,
+		VirtualSolReserves:   30_000_000_000,
+		RealTokenReserves:    793_100_000_000_000,
+		TokenTotalSupply:     1_000_000_000_000_000,
+	}
+	// midCurve is newCurve after 20 SOL of buys
+	midCurve = pump.BondingCurve{
+		VirtualTokenReserves: 643_800_000_000_000,
+		VirtualSolReserves:   50_000_000_000,
+		RealTokenReserves:    363_900_000_000_000,
+		RealSolReserves:      20_000_000_000,
+		TokenTotalSupply:     1_000_000_000_000_000,
+	}
+	fees = Fees{ProtocolBps: 95, CreatorBps: 5}
+)
+
+func TestBuyExactSolIn(t *testing.T) {
+	quote, err := BuyExactSolIn(newCurve, fees, 1_000_000_000, 500)
+	if err != nil {
+		t.Fatalf("failed to quote: %v", err)
+	}
+
+	if quote.TokenAmount != 34_281_150_129_545 {
+		t.Errorf("token amount = %d, want 34281150129545", quote.TokenAmount)
+	}
+	if quote.SolAmount != 1_000_000_000 {
+		t.Errorf("sol amount = %d, want the full 1000000000", quote.SolAmount)
+	}
+	if quote.ProtocolFee != 9_405_941 || quote.CreatorFee != 495_050 {
+		t.Errorf("fees = %d/%d, want 9405941/495050", quote.ProtocolFee, quote.CreatorFee)
+	}
+	if quote.MaxSolCost != 1_050_000_000 {
+		t.Errorf("max sol cost = %d, want 1050000000", quote.MaxSolCost)
+	}
+	if quote.PriceImpactPct <= 0 {
+		t.Errorf("expected positive price impact, got %f", quote.PriceImpactPct)
+	}
+	if quote.Curve.VirtualSolReserves != newCurve.VirtualSolReserves+990_099_009 {
+		t.Errorf("unexpected curve after buy: %+v", quote.Curve)
+	}
+}
+
+func TestBuyExactTokensOut(t *testing.T) {
+	quote, err := BuyExactTokensOut(newCurve, fees, 1_000_000_000_000, 100)
+	if err != nil {
+		t.Fatalf("failed to quote: %v", err)
+	}
+	if quote.SolAmount != 28_264_927 {
+		t.Errorf("sol amount = %d, want 28264927", quote.SolAmount)
+	}
+	if quote.MaxSolCost != MaxSolCost(28_264_927, 100) {
+		t.Errorf("unexpected max sol cost %d", quote.MaxSolCost)
+	}
+
+	if _, err := BuyExactTokensOut(newCurve, fees, newCurve.RealTokenReserves+1, 100); err == nil {
+		t.Error("expected error buying more tokens than the curve holds")
+	}
+}
+
+func TestBuyCapsAtRealReserves(t *testing.T) {
+	quote, err := BuyExactSolIn(midCurve, fees, 1_000_000_000_000, 0)
+	if err != nil {
+		t.Fatalf("failed to quote: %v", err)
+	}
+	if quote.TokenAmount != midCurve.RealTokenReserves {
+		t.Errorf("token amount = %d, want the remaining %d", quote.TokenAmount, midCurve.RealTokenReserves)
+	}
+	if !quote.Curve.Complete {
+		t.Error("expected the curve to complete after buying every token")
+	}
+
+	if _, err := BuyExactSolIn(quote.Curve, fees, 1_000_000, 0); err == nil {
+		t.Error("expected error quoting a complete curve")
+	}
+}
+
+func TestSellExactTokensIn(t *testing.T) {
+	quote, err := SellExactTokensIn(midCurve, fees, 10_000_000_000_000, 100)
+	if err != nil {
+		t.Fatalf("failed to quote: %v", err)
+	}
+
+	if quote.SolAmount != 757_112_266 {
+		t.Errorf("sol amount = %d, want 757112266", quote.SolAmount)
+	}
+	if quote.ProtocolFee != 7_265_219 || quote.CreatorFee != 382_380 {
+		t.Errorf("fees = %d/%d, want 7265219/382380", quote.ProtocolFee, quote.CreatorFee)
+	}
+	if quote.MinSolOutput != 749_541_143 {
+		t.Errorf("min sol output = %d, want 749541143", quote.MinSolOutput)
+	}
+	if quote.Curve.RealSolReserves != midCurve.RealSolReserves-764_759_865 {
+		t.Errorf("unexpected curve after sell: %+v", quote.Curve)
+	}
+
+	// The virtual reserves alone would pay out more SOL than the curve holds
+	if _, err := SellExactTokensIn(newCurve, fees, 10_000_000_000_000, 100); err == nil {
+		t.Error("expected error selling into a curve without real SOL")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	buy, err := BuyExactSolIn(newCurve, fees, 5_000_000_000, 0)
+	if err != nil {
+		t.Fatalf("failed to quote buy: %v", err)
+	}
+	sell, err := SellExactTokensIn(buy.Curve, fees, buy.TokenAmount, 0)
+	if err != nil {
+		t.Fatalf("failed to quote sell: %v", err)
+	}
+
+	// Selling everything back loses the fees and rounding, nothing more
+	lost := buy.SolAmount - sell.SolAmount
+	paidFees := buy.ProtocolFee + buy.CreatorFee + sell.ProtocolFee + sell.CreatorFee
+	if lost < paidFees || lost > paidFees+2 {
+		t.Errorf("round trip lost %d lamports, fees were %d", lost, paidFees)
+	}
+}
@@ -0,0 +1,175 @@
+package pumpfun_quote
+
+import (
+	"fmt"
+	"math/big"
+
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+const bpsDenominator = 10_000
+
+// BuyExactSolIn quotes spending lamports, fees included, on tokens. When the
+// curve has fewer tokens left the buy is capped and costs less.
+func BuyExactSolIn(curve pump.BondingCurve, fees Fees, lamports uint64, slippageBps uint64) (*Quote, error) {
+	if err := checkCurve(curve); err != nil {
+		return nil, err
+	}
+	if lamports == 0 {
+		return nil, fmt.Errorf("sol amount must be greater than zero")
+	}
+
+	// Fees are charged on top of the curve cost, so take them out first
+	in := new(big.Int).SetUint64(lamports)
+	in.Mul(in, big.NewInt(bpsDenominator))
+	in.Div(in, new(big.Int).SetUint64(bpsDenominator+fees.ProtocolBps+fees.CreatorBps))
+
+	// Constant product: out = vToken * in / (vSol + in)
+	out := new(big.Int).Mul(new(big.Int).SetUint64(curve.VirtualTokenReserves), in)
+	out.Div(out, new(big.Int).Add(new(big.Int).SetUint64(curve.VirtualSolReserves), in))
+
+	tokens := out.Uint64()
+	if tokens > curve.RealTokenReserves {
+		tokens = curve.RealTokenReserves
+	}
+	if tokens == 0 {
+		return nil, fmt.Errorf("sol amount is too small to buy any tokens")
+	}
+
+	return BuyExactTokensOut(curve, fees, tokens, slippageBps)
+}
+
+// BuyExactTokensOut quotes the SOL cost, fees included, of buying tokens
+func BuyExactTokensOut(curve pump.BondingCurve, fees Fees, tokens uint64, slippageBps uint64) (*Quote, error) {
+	if err := checkCurve(curve); err != nil {
+		return nil, err
+	}
+	if tokens == 0 {
+		return nil, fmt.Errorf("token amount must be greater than zero")
+	}
+	if tokens > curve.RealTokenReserves {
+		return nil, fmt.Errorf("token amount %d exceeds the %d tokens left on the curve", tokens, curve.RealTokenReserves)
+	}
+
+	// The program charges vSol * tokens / (vToken - tokens), plus one lamport
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(curve.VirtualSolReserves), new(big.Int).SetUint64(tokens))
+	cost.Div(cost, new(big.Int).SetUint64(curve.VirtualTokenReserves-tokens))
+	cost.Add(cost, big.NewInt(1))
+	if !cost.IsUint64() {
+		return nil, fmt.Errorf("sol cost overflow")
+	}
+	solCost := cost.Uint64()
+
+	protocolFee := fee(solCost, fees.ProtocolBps)
+	creatorFee := fee(solCost, fees.CreatorBps)
+	total := solCost + protocolFee + creatorFee
+
+	after := curve
+	after.VirtualTokenReserves -= tokens
+	after.RealTokenReserves -= tokens
+	after.VirtualSolReserves += solCost
+	after.RealSolReserves += solCost
+	after.Complete = after.RealTokenReserves == 0
+
+	return &Quote{
+		SolAmount:      total,
+		TokenAmount:    tokens,
+		ProtocolFee:    protocolFee,
+		CreatorFee:     creatorFee,
+		MaxSolCost:     MaxSolCost(total, slippageBps),
+		PriceImpactPct: priceImpact(curve, solCost, tokens),
+		Curve:          after,
+	}, nil
+}
+
+// SellExactTokensIn quotes the SOL received, after fees, for selling tokens
+func SellExactTokensIn(curve pump.BondingCurve, fees Fees, tokens uint64, slippageBps uint64) (*Quote, error) {
+	if err := checkCurve(curve); err != nil {
+		return nil, err
+	}
+	if tokens == 0 {
+		return nil, fmt.Errorf("token amount must be greater than zero")
+	}
+
+	// Constant product: out = vSol * tokens / (vToken + tokens)
+	out := new(big.Int).Mul(new(big.Int).SetUint64(curve.VirtualSolReserves), new(big.Int).SetUint64(tokens))
+	out.Div(out, new(big.Int).Add(new(big.Int).SetUint64(curve.VirtualTokenReserves), new(big.Int).SetUint64(tokens)))
+
+	solOut := out.Uint64()
+	if solOut > curve.RealSolReserves {
+		return nil, fmt.Errorf("sell output %d exceeds the %d lamports held by the curve", solOut, curve.RealSolReserves)
+	}
+
+	protocolFee := fee(solOut, fees.ProtocolBps)
+	creatorFee := fee(solOut, fees.CreatorBps)
+	if protocolFee+creatorFee >= solOut {
+		return nil, fmt.Errorf("token amount is too small to sell after fees")
+	}
+	net := solOut - protocolFee - creatorFee
+
+	after := curve
+	after.VirtualTokenReserves += tokens
+	after.RealTokenReserves += tokens
+	after.VirtualSolReserves -= solOut
+	after.RealSolReserves -= solOut
+
+	return &Quote{
+		SolAmount:      net,
+		TokenAmount:    tokens,
+		ProtocolFee:    protocolFee,
+		CreatorFee:     creatorFee,
+		MinSolOutput:   MinSolOutput(net, slippageBps),
+		PriceImpactPct: priceImpact(curve, solOut, tokens),
+		Curve:          after,
+	}, nil
+}
+
+// MaxSolCost applies a slippage tolerance in basis points to the SOL cost of a buy
+func MaxSolCost(solCost uint64, slippageBps uint64) uint64 {
+	out := new(big.Int).Mul(new(big.Int).SetUint64(solCost), new(big.Int).SetUint64(bpsDenominator+slippageBps))
+	out.Div(out, big.NewInt(bpsDenominator))
+	if !out.IsUint64() {
+		return ^uint64(0)
+	}
+	return out.Uint64()
+}
+
+// MinSolOutput applies a slippage tolerance in basis points to the SOL output of a sell
+func MinSolOutput(solOutput uint64, slippageBps uint64) uint64 {
+	if slippageBps >= bpsDenominator {
+		return 0
+	}
+	out := new(big.Int).Mul(new(big.Int).SetUint64(solOutput), new(big.Int).SetUint64(bpsDenominator-slippageBps))
+	return out.Div(out, big.NewInt(bpsDenominator)).Uint64()
+}
+
+func checkCurve(curve pump.BondingCurve) error {
+	if curve.Complete {
+		return fmt.Errorf("bonding curve is complete")
+	}
+	if curve.VirtualSolReserves == 0 || curve.VirtualTokenReserves == 0 {
+		return fmt.Errorf("bonding curve has no reserves")
+	}
+	return nil
+}
+
+// fee rounds up, like the program does
+func fee(amount uint64, bps uint64) uint64 {
+	if bps == 0 {
+		return 0
+	}
+	f := new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(bps))
+	f.Add(f, big.NewInt(bpsDenominator-1))
+	return f.Div(f, big.NewInt(bpsDenominator)).Uint64()
+}
+
+// priceImpact compares the execution price of a trade to the spot price, in percent
+func priceImpact(curve pump.BondingCurve, sol, tokens uint64) float64 {
+	spot := new(big.Float).Quo(new(big.Float).SetUint64(curve.VirtualSolReserves), new(big.Float).SetUint64(curve.VirtualTokenReserves))
+	execution := new(big.Float).Quo(new(big.Float).SetUint64(sol), new(big.Float).SetUint64(tokens))
+	ratio, _ := new(big.Float).Quo(execution, spot).Float64()
+	if ratio < 1 {
+		return (1 - ratio) * 100
+	}
+	return (ratio - 1) * 100
+}
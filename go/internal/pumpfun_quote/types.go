@@ -0,0 +1,31 @@
+package pumpfun_quote
+
+import pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+
+// Fees charged by the pump.fun program on the SOL side of every trade, in basis points
+type Fees struct {
+	ProtocolBps uint64
+	CreatorBps  uint64
+}
+
+// Quote is the result of a trade against a bonding curve. Amounts are in
+// lamports and raw token units.
+type Quote struct {
+	// SolAmount is the SOL paid including fees for buys, or received after fees for sells
+	SolAmount   uint64
+	TokenAmount uint64
+
+	ProtocolFee uint64
+	CreatorFee  uint64
+
+	// MaxSolCost bounds a buy and MinSolOutput bounds a sell after slippage,
+	// as passed to the buy and sell instructions
+	MaxSolCost   uint64
+	MinSolOutput uint64
+
+	// PriceImpactPct compares the execution price before fees to the spot price
+	PriceImpactPct float64
+
+	// Curve is the bonding curve state after the trade
+	Curve pump.BondingCurve
+}
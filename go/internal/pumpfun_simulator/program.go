@@ -0,0 +1,472 @@
+package pumpfun_simulator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_quote"
+)
+
+var (
+	computeBudgetProgramID = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+	memoProgramID          = solana.MustPublicKeyFromBase58("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
+)
+
+// runtime executes the instructions of one transaction against a state copy
+type runtime struct {
+	state         *state
+	now           time.Time
+	creatorFeeBps uint64
+	logs          []string
+}
+
+func (r *runtime) instruction(programID solana.PublicKey, accounts []*solana.AccountMeta, data []byte) error {
+	switch {
+	case programID.Equals(computeBudgetProgramID), programID.Equals(memoProgramID):
+		return nil
+	case programID.Equals(system.ProgramID):
+		return r.system(accounts, data)
+	case programID.Equals(token.ProgramID):
+		return r.token(accounts, data)
+	case programID.Equals(associatedtokenaccount.ProgramID):
+		return r.associatedTokenAccount(accounts, data)
+	case programID.Equals(pumpfun.ProgramID):
+		return r.pump(accounts, data)
+	default:
+		return fmt.Errorf("program %s is not simulated", programID)
+	}
+}
+
+func (r *runtime) system(accounts []*solana.AccountMeta, data []byte) error {
+	instruction, err := system.DecodeInstruction(accounts, data)
+	if err != nil {
+		return err
+	}
+
+	transfer, ok := instruction.Impl.(*system.Transfer)
+	if !ok {
+		return fmt.Errorf("system instruction %s is not simulated", system.InstructionIDToName(instruction.TypeID.Uint32()))
+	}
+
+	from := transfer.GetFundingAccount()
+	if !from.IsSigner {
+		return fmt.Errorf("missing required signature for %s", from.PublicKey)
+	}
+	return r.transferLamports(from.PublicKey, transfer.GetRecipientAccount().PublicKey, *transfer.Lamports)
+}
+
+func (r *runtime) token(accounts []*solana.AccountMeta, data []byte) error {
+	instruction, err := token.DecodeInstruction(accounts, data)
+	if err != nil {
+		return err
+	}
+
+	transfer, ok := instruction.Impl.(*token.Transfer)
+	if !ok {
+		return fmt.Errorf("token instruction %s is not simulated", token.InstructionIDToName(instruction.TypeID.Uint8()))
+	}
+
+	owner := transfer.GetOwnerAccount()
+	if !owner.IsSigner {
+		return fmt.Errorf("missing required signature for %s", owner.PublicKey)
+	}
+	source, ok := r.state.tokenAccounts[transfer.GetSourceAccount().PublicKey]
+	if !ok || !source.owner.Equals(owner.PublicKey) {
+		return fmt.Errorf("invalid source token account")
+	}
+	return r.transferTokens(transfer.GetSourceAccount().PublicKey, transfer.GetDestinationAccount().PublicKey, *transfer.Amount)
+}
+
+func (r *runtime) associatedTokenAccount(accounts []*solana.AccountMeta, data []byte) error {
+	if len(accounts) < 4 {
+		return fmt.Errorf("not enough accounts")
+	}
+	ata, wallet, mint := accounts[1].PublicKey, accounts[2].PublicKey, accounts[3].PublicKey
+
+	expected, _, err := solana.FindAssociatedTokenAddress(wallet, mint)
+	if err != nil || !expected.Equals(ata) {
+		return fmt.Errorf("associated token account %s does not match wallet and mint", ata)
+	}
+	if _, ok := r.state.mints[mint]; !ok {
+		return fmt.Errorf("mint %s does not exist", mint)
+	}
+
+	if _, ok := r.state.tokenAccounts[ata]; ok {
+		// CreateIdempotent tolerates an existing account, Create does not
+		if len(data) > 0 && data[0] == 1 {
+			return nil
+		}
+		return fmt.Errorf("account %s already in use", ata)
+	}
+
+	r.state.tokenAccounts[ata] = &tokenAccount{mint: mint, owner: wallet}
+	return nil
+}
+
+func (r *runtime) pump(accounts []*solana.AccountMeta, data []byte) error {
+	// collect_creator_fee is newer than the generated instruction set
+	if bytes.HasPrefix(data, pumpfun.CollectCreatorFeeDiscriminator[:]) {
+		return r.collectCreatorFee(accounts)
+	}
+
+	instruction, err := pump.DecodeInstruction(accounts, data)
+	if err != nil {
+		return err
+	}
+
+	global := r.state.global
+	if !global.Initialized {
+		return fmt.Errorf("global is not initialized")
+	}
+
+	switch impl := instruction.Impl.(type) {
+	case *pump.Create:
+		return r.create(impl)
+	case *pump.Buy:
+		return r.buy(impl)
+	case *pump.Sell:
+		return r.sell(impl)
+	default:
+		return fmt.Errorf("pump instruction %s is not simulated", pump.InstructionIDToName(instruction.TypeID))
+	}
+}
+
+func (r *runtime) create(instruction *pump.Create) error {
+	mint := instruction.GetMintAccount()
+	user := instruction.GetUserAccount()
+	if !mint.IsSigner || !user.IsSigner {
+		return fmt.Errorf("missing required signature")
+	}
+	if err := r.checkGlobal(instruction.GetGlobalAccount()); err != nil {
+		return err
+	}
+	if !instruction.GetMintAuthorityAccount().PublicKey.Equals(pumpfun.MintAuthority) {
+		return fmt.Errorf("invalid mint authority")
+	}
+	if _, ok := r.state.mints[mint.PublicKey]; ok {
+		return fmt.Errorf("mint %s already exists", mint.PublicKey)
+	}
+
+	curveAddress, associatedCurve, err := r.checkCurveAccounts(mint.PublicKey, instruction.GetBondingCurveAccount(), instruction.GetAssociatedBondingCurveAccount())
+	if err != nil {
+		return err
+	}
+
+	global := r.state.global
+	r.state.mints[mint.PublicKey] = &mintAccount{supply: global.TokenTotalSupply, decimals: pumpfun.TokenDecimals}
+	r.state.tokenAccounts[associatedCurve] = &tokenAccount{mint: mint.PublicKey, owner: curveAddress, amount: global.TokenTotalSupply}
+
+	curve := pump.BondingCurve{
+		VirtualTokenReserves: global.InitialVirtualTokenReserves,
+		VirtualSolReserves:   global.InitialVirtualSolReserves,
+		RealTokenReserves:    global.InitialRealTokenReserves,
+		TokenTotalSupply:     global.TokenTotalSupply,
+	}
+	r.state.curves[curveAddress] = &bondingCurve{mint: mint.PublicKey, creator: user.PublicKey, curve: curve}
+
+	return r.emit(pump.CreateEvent{
+		Name:                 *instruction.Name,
+		Symbol:               *instruction.Symbol,
+		Uri:                  *instruction.Uri,
+		Mint:                 mint.PublicKey,
+		BondingCurve:         curveAddress,
+		User:                 user.PublicKey,
+		Creator:              user.PublicKey,
+		Timestamp:            r.now.Unix(),
+		VirtualTokenReserves: curve.VirtualTokenReserves,
+		VirtualSolReserves:   curve.VirtualSolReserves,
+		RealTokenReserves:    curve.RealTokenReserves,
+		TokenTotalSupply:     curve.TokenTotalSupply,
+	})
+}
+
+func (r *runtime) buy(instruction *pump.Buy) error {
+	mint := instruction.GetMintAccount().PublicKey
+	user := instruction.GetUserAccount()
+	curve, curveAddress, associatedCurve, associatedUser, err := r.checkTrade(
+		mint, user,
+		instruction.GetGlobalAccount(), instruction.GetFeeRecipientAccount(),
+		instruction.GetBondingCurveAccount(), instruction.GetAssociatedBondingCurveAccount(), instruction.GetAssociatedUserAccount(),
+	)
+	if err != nil {
+		return err
+	}
+	if err := r.checkCreatorVault(curve, instruction.GetCreatorVaultAccount()); err != nil {
+		return err
+	}
+
+	// The program sells whatever is left when asked for more
+	tokens := *instruction.Amount
+	if tokens > curve.curve.RealTokenReserves {
+		tokens = curve.curve.RealTokenReserves
+	}
+
+	quote, err := pumpfun_quote.BuyExactTokensOut(curve.curve, r.fees(), tokens, 0)
+	if err != nil {
+		return err
+	}
+	if quote.SolAmount > *instruction.MaxSolCost {
+		return fmt.Errorf("TooMuchSolRequired: buy costs %d lamports, max is %d", quote.SolAmount, *instruction.MaxSolCost)
+	}
+
+	if err := r.transferLamports(user.PublicKey, curveAddress, quote.SolAmount-quote.ProtocolFee-quote.CreatorFee); err != nil {
+		return err
+	}
+	if err := r.transferLamports(user.PublicKey, r.state.global.FeeRecipient, quote.ProtocolFee); err != nil {
+		return err
+	}
+	if err := r.payCreatorFee(user.PublicKey, curve, quote.CreatorFee); err != nil {
+		return err
+	}
+	if err := r.transferTokens(associatedCurve, associatedUser, tokens); err != nil {
+		return err
+	}
+
+	return r.trade(curve, quote, true, user.PublicKey)
+}
+
+func (r *runtime) sell(instruction *pump.Sell) error {
+	mint := instruction.GetMintAccount().PublicKey
+	user := instruction.GetUserAccount()
+	curve, curveAddress, associatedCurve, associatedUser, err := r.checkTrade(
+		mint, user,
+		instruction.GetGlobalAccount(), instruction.GetFeeRecipientAccount(),
+		instruction.GetBondingCurveAccount(), instruction.GetAssociatedBondingCurveAccount(), instruction.GetAssociatedUserAccount(),
+	)
+	if err != nil {
+		return err
+	}
+
+	quote, err := pumpfun_quote.SellExactTokensIn(curve.curve, r.fees(), *instruction.Amount, 0)
+	if err != nil {
+		return err
+	}
+	if quote.SolAmount < *instruction.MinSolOutput {
+		return fmt.Errorf("TooLittleSolReceived: sell returns %d lamports, min is %d", quote.SolAmount, *instruction.MinSolOutput)
+	}
+
+	if err := r.transferTokens(associatedUser, associatedCurve, quote.TokenAmount); err != nil {
+		return err
+	}
+	if err := r.transferLamports(curveAddress, user.PublicKey, quote.SolAmount); err != nil {
+		return err
+	}
+	if err := r.transferLamports(curveAddress, r.state.global.FeeRecipient, quote.ProtocolFee); err != nil {
+		return err
+	}
+	if err := r.payCreatorFee(curveAddress, curve, quote.CreatorFee); err != nil {
+		return err
+	}
+
+	return r.trade(curve, quote, false, user.PublicKey)
+}
+
+// trade applies a quote to the curve and emits the trade event, followed by
+// the complete event when the last token was sold
+func (r *runtime) trade(curve *bondingCurve, quote *pumpfun_quote.Quote, isBuy bool, user solana.PublicKey) error {
+	curve.curve = quote.Curve
+
+	solAmount := quote.SolAmount
+	if isBuy {
+		solAmount -= quote.ProtocolFee + quote.CreatorFee
+	} else {
+		solAmount += quote.ProtocolFee + quote.CreatorFee
+	}
+
+	if err := r.emit(pump.TradeEvent{
+		Mint:                  curve.mint,
+		SolAmount:             solAmount,
+		TokenAmount:           quote.TokenAmount,
+		IsBuy:                 isBuy,
+		User:                  user,
+		Timestamp:             r.now.Unix(),
+		VirtualSolReserves:    curve.curve.VirtualSolReserves,
+		VirtualTokenReserves:  curve.curve.VirtualTokenReserves,
+		RealSolReserves:       curve.curve.RealSolReserves,
+		RealTokenReserves:     curve.curve.RealTokenReserves,
+		FeeRecipient:          r.state.global.FeeRecipient,
+		FeeBasisPoints:        r.state.global.FeeBasisPoints,
+		Fee:                   quote.ProtocolFee,
+		Creator:               curve.creator,
+		CreatorFeeBasisPoints: r.creatorFeeBps,
+		CreatorFee:            quote.CreatorFee,
+	}); err != nil {
+		return err
+	}
+
+	if !curve.curve.Complete {
+		return nil
+	}
+
+	curveAddress, _, _ := pumpfun.DeriveBondingCurveAddresses(curve.mint)
+	return r.emit(pump.CompleteEvent{
+		User:         user,
+		Mint:         curve.mint,
+		BondingCurve: curveAddress,
+		Timestamp:    r.now.Unix(),
+	})
+}
+
+// checkTrade validates the accounts shared by buy and sell
+func (r *runtime) checkTrade(
+	mint solana.PublicKey,
+	user, global, feeRecipient, bondingCurveAccount, associatedBondingCurve, associatedUser *solana.AccountMeta,
+) (curve *bondingCurve, curveAddress, associatedCurve, userAccount solana.PublicKey, err error) {
+	if !user.IsSigner {
+		return nil, curveAddress, associatedCurve, userAccount, fmt.Errorf("missing required signature for %s", user.PublicKey)
+	}
+	if err := r.checkGlobal(global); err != nil {
+		return nil, curveAddress, associatedCurve, userAccount, err
+	}
+	if !feeRecipient.PublicKey.Equals(r.state.global.FeeRecipient) {
+		return nil, curveAddress, associatedCurve, userAccount, fmt.Errorf("NotAuthorized: fee recipient %s does not match global %s", feeRecipient.PublicKey, r.state.global.FeeRecipient)
+	}
+
+	curveAddress, associatedCurve, err = r.checkCurveAccounts(mint, bondingCurveAccount, associatedBondingCurve)
+	if err != nil {
+		return nil, curveAddress, associatedCurve, userAccount, err
+	}
+
+	curve, ok := r.state.curves[curveAddress]
+	if !ok {
+		return nil, curveAddress, associatedCurve, userAccount, fmt.Errorf("bonding curve %s does not exist", curveAddress)
+	}
+	if curve.curve.Complete {
+		return nil, curveAddress, associatedCurve, userAccount, fmt.Errorf("BondingCurveComplete: the bonding curve has completed")
+	}
+
+	userAccount = associatedUser.PublicKey
+	account, ok := r.state.tokenAccounts[userAccount]
+	if !ok || !account.mint.Equals(mint) || !account.owner.Equals(user.PublicKey) {
+		return nil, curveAddress, associatedCurve, userAccount, fmt.Errorf("invalid user token account %s", userAccount)
+	}
+
+	return curve, curveAddress, associatedCurve, userAccount, nil
+}
+
+func (r *runtime) checkGlobal(global *solana.AccountMeta) error {
+	if !global.PublicKey.Equals(pumpfun.GlobalPumpFunAddress) {
+		return fmt.Errorf("invalid global account %s", global.PublicKey)
+	}
+	return nil
+}
+
+func (r *runtime) checkCurveAccounts(mint solana.PublicKey, bondingCurveAccount, associatedBondingCurve *solana.AccountMeta) (solana.PublicKey, solana.PublicKey, error) {
+	curveAddress, associatedCurve, err := pumpfun.DeriveBondingCurveAddresses(mint)
+	if err != nil {
+		return solana.PublicKey{}, solana.PublicKey{}, err
+	}
+	if !bondingCurveAccount.PublicKey.Equals(curveAddress) {
+		return solana.PublicKey{}, solana.PublicKey{}, fmt.Errorf("invalid bonding curve %s for mint %s", bondingCurveAccount.PublicKey, mint)
+	}
+	if !associatedBondingCurve.PublicKey.Equals(associatedCurve) {
+		return solana.PublicKey{}, solana.PublicKey{}, fmt.Errorf("invalid associated bonding curve %s for mint %s", associatedBondingCurve.PublicKey, mint)
+	}
+	return curveAddress, associatedCurve, nil
+}
+
+// checkCreatorVault requires the vault derived from the curve's creator, which
+// buys pay their creator fee into
+func (r *runtime) checkCreatorVault(curve *bondingCurve, creatorVault *solana.AccountMeta) error {
+	vault, err := pumpfun.DeriveCreatorVault(curve.creator)
+	if err != nil {
+		return err
+	}
+	if !creatorVault.PublicKey.Equals(vault) || !creatorVault.IsWritable {
+		return fmt.Errorf("ConstraintSeeds: invalid creator vault %s, want writable %s", creatorVault.PublicKey, vault)
+	}
+	return nil
+}
+
+func (r *runtime) fees() pumpfun_quote.Fees {
+	return pumpfun_quote.Fees{ProtocolBps: r.state.global.FeeBasisPoints, CreatorBps: r.creatorFeeBps}
+}
+
+func (r *runtime) payCreatorFee(from solana.PublicKey, curve *bondingCurve, fee uint64) error {
+	if fee == 0 {
+		return nil
+	}
+	vault, err := pumpfun.DeriveCreatorVault(curve.creator)
+	if err != nil {
+		return err
+	}
+	// The vault is created rent-exempt by its first fee
+	if _, ok := r.state.lamports[vault]; !ok {
+		if err := r.transferLamports(from, vault, pumpfun.CreatorVaultRentExemption); err != nil {
+			return err
+		}
+	}
+	return r.transferLamports(from, vault, fee)
+}
+
+// collectCreatorFee moves everything above the vault's rent exemption to the creator
+func (r *runtime) collectCreatorFee(accounts []*solana.AccountMeta) error {
+	if len(accounts) < 2 {
+		return fmt.Errorf("not enough accounts")
+	}
+	creator, vaultAccount := accounts[0], accounts[1]
+	if !creator.IsSigner {
+		return fmt.Errorf("missing required signature for %s", creator.PublicKey)
+	}
+
+	vault, err := pumpfun.DeriveCreatorVault(creator.PublicKey)
+	if err != nil {
+		return err
+	}
+	if !vaultAccount.PublicKey.Equals(vault) {
+		return fmt.Errorf("ConstraintSeeds: invalid creator vault %s for %s", vaultAccount.PublicKey, creator.PublicKey)
+	}
+
+	if balance := r.state.lamports[vault]; balance > pumpfun.CreatorVaultRentExemption {
+		return r.transferLamports(vault, creator.PublicKey, balance-pumpfun.CreatorVaultRentExemption)
+	}
+	return nil
+}
+
+func (r *runtime) transferLamports(from, to solana.PublicKey, lamports uint64) error {
+	if r.state.lamports[from] < lamports {
+		return fmt.Errorf("insufficient lamports in %s: need %d, have %d", from, lamports, r.state.lamports[from])
+	}
+	r.state.lamports[from] -= lamports
+	r.state.lamports[to] += lamports
+	return nil
+}
+
+func (r *runtime) transferTokens(from, to solana.PublicKey, amount uint64) error {
+	source, ok := r.state.tokenAccounts[from]
+	if !ok {
+		return fmt.Errorf("token account %s does not exist", from)
+	}
+	destination, ok := r.state.tokenAccounts[to]
+	if !ok {
+		return fmt.Errorf("token account %s does not exist", to)
+	}
+	if !source.mint.Equals(destination.mint) {
+		return fmt.Errorf("token accounts %s and %s hold different mints", from, to)
+	}
+	if source.amount < amount {
+		return fmt.Errorf("insufficient tokens in %s: need %d, have %d", from, amount, source.amount)
+	}
+	source.amount -= amount
+	destination.amount += amount
+	return nil
+}
+
+// emit logs an event the way the program does, as "Program data:" base64
+func (r *runtime) emit(event ag_binary.BinaryMarshaler) error {
+	buf := new(bytes.Buffer)
+	if err := event.MarshalWithEncoder(ag_binary.NewBorshEncoder(buf)); err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	r.logs = append(r.logs, "Program data: "+base64.StdEncoding.EncodeToString(buf.Bytes()))
+	return nil
+}
@@ -0,0 +1,477 @@
+package pumpfun_simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+)
+
+// JSON-RPC error codes returned by the simulator, matching a validator
+const (
+	codeMethodNotFound    = -32601
+	codeInvalidParams     = -32602
+	codeSimulationFailure = -32002
+)
+
+// Sizes of the SPL token program accounts
+const (
+	mintAccountSize  = 82
+	tokenAccountSize = 165
+)
+
+// CallForInto serves a JSON-RPC call from the simulated state. It implements
+// rpc.JSONRPCClient together with CallWithCallback and CallBatch.
+func (s *Simulator) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	result, err := s.call(method, params)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s result: %w", method, err)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// CallWithCallback is not supported by the simulator
+func (s *Simulator) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return fmt.Errorf("CallWithCallback is not supported by the simulator")
+}
+
+// CallBatch serves each request in order
+func (s *Simulator) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	responses := make(jsonrpc.RPCResponses, 0, len(requests))
+	for _, request := range requests {
+		params, _ := request.Params.([]interface{})
+
+		response := &jsonrpc.RPCResponse{JSONRPC: "2.0", ID: request.ID}
+		result, err := s.call(request.Method, params)
+		if err != nil {
+			rpcErr, ok := err.(*jsonrpc.RPCError)
+			if !ok {
+				rpcErr = &jsonrpc.RPCError{Code: codeInvalidParams, Message: err.Error()}
+			}
+			response.Error = rpcErr
+		} else if response.Result, err = json.Marshal(result); err != nil {
+			return nil, fmt.Errorf("failed to encode %s result: %w", request.Method, err)
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *Simulator) call(method string, params []interface{}) (interface{}, error) {
+	raw, err := rawParams(params)
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	switch method {
+	case "getAccountInfo":
+		var account solana.PublicKey
+		if err := paramAt(raw, 0, &account); err != nil {
+			return nil, err
+		}
+		return s.withContext(s.accountInfo(account)), nil
+
+	case "getMultipleAccounts":
+		var accounts []solana.PublicKey
+		if err := paramAt(raw, 0, &accounts); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(accounts))
+		for i, account := range accounts {
+			values[i] = s.accountInfo(account)
+		}
+		return s.withContext(values), nil
+
+	case "getProgramAccounts":
+		var programID solana.PublicKey
+		if err := paramAt(raw, 0, &programID); err != nil {
+			return nil, err
+		}
+		var opts struct {
+			Filters   []rpc.RPCFilter `json:"filters"`
+			DataSlice *rpc.DataSlice  `json:"dataSlice"`
+		}
+		if len(raw) > 1 {
+			if err := paramAt(raw, 1, &opts); err != nil {
+				return nil, err
+			}
+		}
+		return s.programAccounts(programID, opts.Filters, opts.DataSlice), nil
+
+	case "getTokenAccountsByOwner":
+		var owner solana.PublicKey
+		if err := paramAt(raw, 0, &owner); err != nil {
+			return nil, err
+		}
+		var config struct {
+			Mint *solana.PublicKey `json:"mint"`
+		}
+		if err := paramAt(raw, 1, &config); err != nil {
+			return nil, err
+		}
+		return s.withContext(s.tokenAccountsByOwner(owner, config.Mint)), nil
+
+	case "getTokenLargestAccounts":
+		var mint solana.PublicKey
+		if err := paramAt(raw, 0, &mint); err != nil {
+			return nil, err
+		}
+		return s.withContext(s.tokenLargestAccounts(mint)), nil
+
+	case "getBalance":
+		var account solana.PublicKey
+		if err := paramAt(raw, 0, &account); err != nil {
+			return nil, err
+		}
+		return s.withContext(s.Balance(account)), nil
+
+	case "getLatestBlockhash":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": s.slot},
+			"value": map[string]interface{}{
+				"blockhash":            s.blockhash().String(),
+				"lastValidBlockHeight": s.slot + 150,
+			},
+		}, nil
+
+	case "getRecentBlockhash":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": s.slot},
+			"value": map[string]interface{}{
+				"blockhash":     s.blockhash().String(),
+				"feeCalculator": map[string]interface{}{"lamportsPerSignature": LamportsPerSignature},
+			},
+		}, nil
+
+	case "getRecentPrioritizationFees":
+		return []interface{}{}, nil
+
+	case "getSlot":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.slot, nil
+
+	case "sendTransaction":
+		tx, err := decodeTransactionParam(raw)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := s.Process(tx)
+		if err != nil {
+			return nil, &jsonrpc.RPCError{
+				Code:    codeSimulationFailure,
+				Message: fmt.Sprintf("Transaction simulation failed: %v", err),
+			}
+		}
+		return signature.String(), nil
+
+	case "simulateTransaction":
+		tx, err := decodeTransactionParam(raw)
+		if err != nil {
+			return nil, err
+		}
+		result := s.Simulate(tx)
+
+		var txErr interface{}
+		if result.Err != nil {
+			txErr = result.Err.Error()
+		}
+		return s.withContext(map[string]interface{}{
+			"err":           txErr,
+			"logs":          result.Logs,
+			"unitsConsumed": 0,
+		}), nil
+
+	case "getSignatureStatuses":
+		var signatures []solana.Signature
+		if err := paramAt(raw, 0, &signatures); err != nil {
+			return nil, err
+		}
+		statuses := make([]interface{}, len(signatures))
+		for i, signature := range signatures {
+			if tx, ok := s.Transaction(signature); ok {
+				statuses[i] = map[string]interface{}{
+					"slot":               tx.Slot,
+					"confirmations":      1,
+					"err":                nil,
+					"confirmationStatus": "confirmed",
+				}
+			}
+		}
+		return s.withContext(statuses), nil
+
+	default:
+		return nil, &jsonrpc.RPCError{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("Method not found: %s is not simulated", method),
+		}
+	}
+}
+
+func (s *Simulator) withContext(value interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return map[string]interface{}{
+		"context": map[string]interface{}{"slot": s.slot},
+		"value":   value,
+	}
+}
+
+// accountInfo encodes an account as getAccountInfo returns it with base64
+// encoding, or nil when the account doesn't exist
+func (s *Simulator) accountInfo(address solana.PublicKey) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner, data, ok := s.account(address)
+	if !ok {
+		return nil
+	}
+	return s.encodeAccount(address, owner, data)
+}
+
+// programAccounts serves getProgramAccounts for the pump.fun and token
+// programs, applying memcmp and dataSize filters and the data slice
+func (s *Simulator) programAccounts(programID solana.PublicKey, filters []rpc.RPCFilter, slice *rpc.DataSlice) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var addresses []solana.PublicKey
+	switch {
+	case programID.Equals(pumpfun.ProgramID):
+		addresses = append(addresses, pumpfun.GlobalPumpFunAddress)
+		for address := range s.state.curves {
+			addresses = append(addresses, address)
+		}
+	case programID.Equals(token.ProgramID):
+		for address := range s.state.mints {
+			addresses = append(addresses, address)
+		}
+		for address := range s.state.tokenAccounts {
+			addresses = append(addresses, address)
+		}
+	}
+
+	out := []interface{}{}
+	for _, address := range addresses {
+		owner, data, ok := s.account(address)
+		if !ok || !matchFilters(data, filters) {
+			continue
+		}
+		out = append(out, map[string]interface{}{
+			"pubkey":  address.String(),
+			"account": s.encodeAccount(address, owner, sliceData(data, slice)),
+		})
+	}
+	return out
+}
+
+// sliceData returns the part of data a dataSlice asks for
+func sliceData(data []byte, slice *rpc.DataSlice) []byte {
+	if slice == nil || slice.Offset == nil || slice.Length == nil {
+		return data
+	}
+	start := min(*slice.Offset, uint64(len(data)))
+	end := min(start+*slice.Length, uint64(len(data)))
+	return data[start:end]
+}
+
+// tokenAccountsByOwner serves getTokenAccountsByOwner, optionally for one mint
+func (s *Simulator) tokenAccountsByOwner(owner solana.PublicKey, mint *solana.PublicKey) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []interface{}{}
+	for address, account := range s.state.tokenAccounts {
+		if !account.owner.Equals(owner) || (mint != nil && !account.mint.Equals(*mint)) {
+			continue
+		}
+		out = append(out, map[string]interface{}{
+			"pubkey":  address.String(),
+			"account": s.encodeAccount(address, token.ProgramID, encodeTokenAccount(account)),
+		})
+	}
+	return out
+}
+
+// tokenLargestAccounts serves getTokenLargestAccounts: the 20 largest
+// accounts of a mint, largest first
+func (s *Simulator) tokenLargestAccounts(mint solana.PublicKey) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var decimals uint8
+	if account, ok := s.state.mints[mint]; ok {
+		decimals = account.decimals
+	}
+
+	type holding struct {
+		address solana.PublicKey
+		amount  uint64
+	}
+	var holdings []holding
+	for address, account := range s.state.tokenAccounts {
+		if account.mint.Equals(mint) {
+			holdings = append(holdings, holding{address, account.amount})
+		}
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		return holdings[i].amount > holdings[j].amount
+	})
+	if len(holdings) > 20 {
+		holdings = holdings[:20]
+	}
+
+	out := []interface{}{}
+	for _, h := range holdings {
+		uiAmount := float64(h.amount) / math.Pow10(int(decimals))
+		out = append(out, map[string]interface{}{
+			"address":        h.address.String(),
+			"amount":         strconv.FormatUint(h.amount, 10),
+			"decimals":       decimals,
+			"uiAmount":       uiAmount,
+			"uiAmountString": strconv.FormatFloat(uiAmount, 'f', -1, 64),
+		})
+	}
+	return out
+}
+
+// account returns the owner program and data of an address. Callers hold s.mu.
+func (s *Simulator) account(address solana.PublicKey) (solana.PublicKey, []byte, bool) {
+	if address.Equals(pumpfun.GlobalPumpFunAddress) {
+		data, err := borsh(s.state.global)
+		return pumpfun.ProgramID, data, err == nil
+	}
+	if curve, ok := s.state.curves[address]; ok {
+		// Newer curves store their creator after the reserves
+		data, err := borsh(curve.curve)
+		return pumpfun.ProgramID, append(data, curve.creator[:]...), err == nil
+	}
+	if mint, ok := s.state.mints[address]; ok {
+		return token.ProgramID, encodeMint(mint), true
+	}
+	if account, ok := s.state.tokenAccounts[address]; ok {
+		return token.ProgramID, encodeTokenAccount(account), true
+	}
+	if _, ok := s.state.lamports[address]; ok {
+		return solana.SystemProgramID, nil, true
+	}
+	return solana.PublicKey{}, nil, false
+}
+
+func (s *Simulator) encodeAccount(address, owner solana.PublicKey, data []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"lamports":   s.state.lamports[address],
+		"owner":      owner.String(),
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"rentEpoch":  0,
+		"space":      len(data),
+	}
+}
+
+func matchFilters(data []byte, filters []rpc.RPCFilter) bool {
+	for _, filter := range filters {
+		if filter.DataSize != 0 && uint64(len(data)) != filter.DataSize {
+			return false
+		}
+		if filter.Memcmp != nil {
+			offset := filter.Memcmp.Offset
+			if offset > uint64(len(data)) || !bytes.HasPrefix(data[offset:], filter.Memcmp.Bytes) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func borsh(value ag_binary.BinaryMarshaler) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := value.MarshalWithEncoder(ag_binary.NewBorshEncoder(buf)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeMint lays out a mint whose authorities were revoked, as pump.fun does
+func encodeMint(mint *mintAccount) []byte {
+	data := make([]byte, mintAccountSize)
+	binary.LittleEndian.PutUint64(data[36:44], mint.supply)
+	data[44] = mint.decimals
+	data[45] = 1 // initialized
+	return data
+}
+
+func encodeTokenAccount(account *tokenAccount) []byte {
+	data := make([]byte, tokenAccountSize)
+	copy(data[0:32], account.mint[:])
+	copy(data[32:64], account.owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], account.amount)
+	data[108] = 1 // initialized
+	return data
+}
+
+func rawParams(params []interface{}) ([]json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func paramAt(raw []json.RawMessage, index int, out interface{}) error {
+	if index >= len(raw) {
+		return &jsonrpc.RPCError{Code: codeInvalidParams, Message: fmt.Sprintf("missing parameter %d", index)}
+	}
+	if err := json.Unmarshal(raw[index], out); err != nil {
+		return &jsonrpc.RPCError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid parameter %d: %v", index, err)}
+	}
+	return nil
+}
+
+// decodeTransactionParam decodes the base64 transaction of sendTransaction
+// and simulateTransaction
+func decodeTransactionParam(raw []json.RawMessage) (*solana.Transaction, error) {
+	var encoded string
+	if err := paramAt(raw, 0, &encoded); err != nil {
+		return nil, err
+	}
+	tx, err := solana.TransactionFromBase64(encoded)
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: codeInvalidParams, Message: fmt.Sprintf("failed to decode transaction: %v", err)}
+	}
+	return tx, nil
+}
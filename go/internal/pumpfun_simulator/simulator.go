@@ -0,0 +1,349 @@
+package pumpfun_simulator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+// LamportsPerSignature is the base transaction fee charged to the fee payer
+const LamportsPerSignature = 5_000
+
+// DefaultGlobal holds the mainnet parameters of the pump.fun program
+var DefaultGlobal = pump.Global{
+	Initialized:                 true,
+	Authority:                   solana.MustPublicKeyFromBase58("DCpJReAfonSrgohiQbTmKKbjbqVofspFRHz9yQikzooP"),
+	FeeRecipient:                pumpfun.PumpFunFeeRecipient,
+	InitialVirtualTokenReserves: 1_073_000_000_000_000,
+	InitialVirtualSolReserves:   30_000_000_000,
+	InitialRealTokenReserves:    793_100_000_000_000,
+	TokenTotalSupply:            1_000_000_000_000_000,
+	FeeBasisPoints:              100,
+}
+
+type mintAccount struct {
+	supply   uint64
+	decimals uint8
+}
+
+type tokenAccount struct {
+	mint   solana.PublicKey
+	owner  solana.PublicKey
+	amount uint64
+}
+
+type bondingCurve struct {
+	mint    solana.PublicKey
+	creator solana.PublicKey
+	curve   pump.BondingCurve
+}
+
+// state is everything a transaction can change. Transactions run on a copy
+// that replaces the state only when every instruction succeeded.
+type state struct {
+	global        pump.Global
+	lamports      map[solana.PublicKey]uint64
+	mints         map[solana.PublicKey]*mintAccount
+	tokenAccounts map[solana.PublicKey]*tokenAccount
+	curves        map[solana.PublicKey]*bondingCurve // by bonding curve address
+}
+
+func (s *state) clone() *state {
+	c := &state{
+		global:        s.global,
+		lamports:      make(map[solana.PublicKey]uint64, len(s.lamports)),
+		mints:         make(map[solana.PublicKey]*mintAccount, len(s.mints)),
+		tokenAccounts: make(map[solana.PublicKey]*tokenAccount, len(s.tokenAccounts)),
+		curves:        make(map[solana.PublicKey]*bondingCurve, len(s.curves)),
+	}
+	for k, v := range s.lamports {
+		c.lamports[k] = v
+	}
+	for k, v := range s.mints {
+		m := *v
+		c.mints[k] = &m
+	}
+	for k, v := range s.tokenAccounts {
+		a := *v
+		c.tokenAccounts[k] = &a
+	}
+	for k, v := range s.curves {
+		b := *v
+		c.curves[k] = &b
+	}
+	return c
+}
+
+// Transaction is a processed transaction
+type Transaction struct {
+	Signature solana.Signature
+	Slot      uint64
+	Logs      []string
+	Err       error
+}
+
+// Simulator is an in-memory model of the pump.fun program and the accounts
+// it touches. It serves the JSON-RPC calls the pump.fun builders make, so
+// rpc.NewWithCustomRPCClient(simulator) can stand in for a live node.
+//
+// Rent, compute units and blockhash expiry are not modeled.
+type Simulator struct {
+	mu sync.Mutex
+
+	state        *state
+	slot         uint64
+	transactions map[solana.Signature]*Transaction
+
+	now           func() time.Time
+	creatorFeeBps uint64
+}
+
+type Option func(*Simulator)
+
+// WithGlobal starts the simulator with different program parameters
+func WithGlobal(global pump.Global) Option {
+	return func(s *Simulator) {
+		s.state.global = global
+	}
+}
+
+// WithClock sets the time used for event timestamps
+func WithClock(now func() time.Time) Option {
+	return func(s *Simulator) {
+		s.now = now
+	}
+}
+
+// WithCreatorFee charges a creator fee on every trade, paid into the
+// creator's vault
+func WithCreatorFee(bps uint64) Option {
+	return func(s *Simulator) {
+		s.creatorFeeBps = bps
+	}
+}
+
+// New creates a simulator with DefaultGlobal and no other accounts
+func New(opts ...Option) *Simulator {
+	s := &Simulator{
+		state: &state{
+			global:        DefaultGlobal,
+			lamports:      make(map[solana.PublicKey]uint64),
+			mints:         make(map[solana.PublicKey]*mintAccount),
+			tokenAccounts: make(map[solana.PublicKey]*tokenAccount),
+			curves:        make(map[solana.PublicKey]*bondingCurve),
+		},
+		slot:         1,
+		transactions: make(map[solana.Signature]*Transaction),
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Client returns an rpc.Client backed by the simulator
+func (s *Simulator) Client() *rpc.Client {
+	return rpc.NewWithCustomRPCClient(s)
+}
+
+// Airdrop credits lamports to an account
+func (s *Simulator) Airdrop(account solana.PublicKey, lamports uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.lamports[account] += lamports
+}
+
+// CreateToken funds a new creator with 100 SOL and creates a token from it
+// through the regular builder, with an initial buy of buySol. It returns the
+// creator and the mint.
+func (s *Simulator) CreateToken(ctx context.Context, buySol float64) (solana.PrivateKey, solana.PublicKey, error) {
+	creator := solana.NewWallet().PrivateKey
+	s.Airdrop(creator.PublicKey(), 100*solana.LAMPORTS_PER_SOL)
+
+	mint := solana.NewWallet()
+	if _, err := pumpfun.CreateToken(ctx, pumpfun.CreateTokenRequest{
+		RpcClient:       s.Client(),
+		TokenInfo:       pumpfun.CreateTokenInformation{Name: "Test", Symbol: "TEST"},
+		Mint:            mint,
+		UserPrivateKey:  creator,
+		BuyAmount:       buySol,
+		SlippagePercent: 10,
+	}); err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to create token: %w", err)
+	}
+	return creator, mint.PublicKey(), nil
+}
+
+// Balance returns the lamports held by an account
+func (s *Simulator) Balance(account solana.PublicKey) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.lamports[account]
+}
+
+// TokenBalance returns the owner's balance in its associated token account for mint
+func (s *Simulator) TokenBalance(owner, mint solana.PublicKey) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ata, _, err := solana.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		return 0
+	}
+	if account, ok := s.state.tokenAccounts[ata]; ok {
+		return account.amount
+	}
+	return 0
+}
+
+// Global returns the current program parameters
+func (s *Simulator) Global() pump.Global {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.global
+}
+
+// SetGlobal changes the program parameters, as the authority's set_params would
+func (s *Simulator) SetGlobal(global pump.Global) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.global = global
+}
+
+// BondingCurve returns the bonding curve of a mint
+func (s *Simulator) BondingCurve(mint solana.PublicKey) (pump.BondingCurve, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address, _, err := pumpfun.DeriveBondingCurveAddresses(mint)
+	if err != nil {
+		return pump.BondingCurve{}, false
+	}
+	if curve, ok := s.state.curves[address]; ok {
+		return curve.curve, true
+	}
+	return pump.BondingCurve{}, false
+}
+
+// Transaction returns a processed transaction by signature
+func (s *Simulator) Transaction(signature solana.Signature) (*Transaction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[signature]
+	return tx, ok
+}
+
+// Process verifies and executes a signed transaction. State only changes
+// when every instruction succeeds, and the error says which one failed.
+func (s *Simulator) Process(tx *solana.Transaction) (solana.Signature, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, next := s.execute(tx)
+	if result.Err != nil {
+		return result.Signature, result.Err
+	}
+	if _, ok := s.transactions[result.Signature]; ok {
+		return result.Signature, fmt.Errorf("transaction %s already processed", result.Signature)
+	}
+
+	s.state = next
+	s.slot++
+	result.Slot = s.slot
+	s.transactions[result.Signature] = result
+
+	return result.Signature, nil
+}
+
+// Simulate executes a transaction without changing any state
+func (s *Simulator) Simulate(tx *solana.Transaction) *Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, _ := s.execute(tx)
+	result.Slot = s.slot
+	return result
+}
+
+// execute runs the transaction on a copy of the state and returns it
+func (s *Simulator) execute(tx *solana.Transaction) (*Transaction, *state) {
+	result := &Transaction{}
+	if len(tx.Signatures) > 0 {
+		result.Signature = tx.Signatures[0]
+	}
+
+	if len(tx.Signatures) == 0 {
+		result.Err = fmt.Errorf("transaction has no signatures")
+		return result, nil
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		result.Err = fmt.Errorf("signature verification failed: %w", err)
+		return result, nil
+	}
+	if len(tx.Message.GetAddressTableLookups()) > 0 {
+		result.Err = fmt.Errorf("address lookup tables are not supported")
+		return result, nil
+	}
+
+	next := s.state.clone()
+
+	feePayer := tx.Message.AccountKeys[0]
+	fee := uint64(len(tx.Signatures)) * LamportsPerSignature
+	if next.lamports[feePayer] < fee {
+		result.Err = fmt.Errorf("insufficient funds for fee")
+		return result, nil
+	}
+	next.lamports[feePayer] -= fee
+
+	run := &runtime{state: next, now: s.now(), creatorFeeBps: s.creatorFeeBps}
+	for i, instruction := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(instruction.ProgramIDIndex)
+		if err != nil {
+			result.Err = fmt.Errorf("instruction %d: %w", i, err)
+			break
+		}
+
+		accounts, err := instruction.ResolveInstructionAccounts(&tx.Message)
+		if err != nil {
+			result.Err = fmt.Errorf("instruction %d: %w", i, err)
+			break
+		}
+
+		run.logs = append(run.logs, fmt.Sprintf("Program %s invoke [1]", programID))
+		if err := run.instruction(programID, accounts, instruction.Data); err != nil {
+			run.logs = append(run.logs, fmt.Sprintf("Program %s failed: %v", programID, err))
+			result.Err = fmt.Errorf("instruction %d: %w", i, err)
+			break
+		}
+		run.logs = append(run.logs, fmt.Sprintf("Program %s success", programID))
+	}
+
+	result.Logs = run.logs
+	if result.Err != nil {
+		return result, nil
+	}
+	return result, next
+}
+
+// blockhash returns a deterministic blockhash for the current slot
+func (s *Simulator) blockhash() solana.Hash {
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], s.slot)
+	return solana.Hash(sha256.Sum256(seed[:]))
+}
@@ -0,0 +1,374 @@
+package pumpfun_simulator
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_quote"
+)
+
+var testTime = time.Unix(1_700_000_000, 0)
+
+// newTestSimulator returns a simulator and a funded user
+func newTestSimulator(t *testing.T) (*Simulator, solana.PrivateKey) {
+	t.Helper()
+
+	sim := New(WithClock(func() time.Time { return testTime }))
+	user := solana.NewWallet().PrivateKey
+	sim.Airdrop(user.PublicKey(), 1_000*solana.LAMPORTS_PER_SOL)
+	return sim, user
+}
+
+// createToken creates a token through the regular builder, with an optional buy
+func createToken(t *testing.T, sim *Simulator, user solana.PrivateKey, buySol float64) solana.PublicKey {
+	t.Helper()
+
+	mint := solana.NewWallet()
+	_, err := pumpfun.CreateToken(context.Background(), pumpfun.CreateTokenRequest{
+		RpcClient:       sim.Client(),
+		TokenInfo:       pumpfun.CreateTokenInformation{Name: "Test", Symbol: "TEST", ImageURI: "https://example.com/test.json"},
+		Mint:            mint,
+		UserPrivateKey:  user,
+		BuyAmount:       buySol,
+		SlippagePercent: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	return mint.PublicKey()
+}
+
+func process(sim *Simulator, signer solana.PrivateKey, instructions ...solana.Instruction) (*Transaction, error) {
+	tx, err := solana.NewTransaction(instructions, sim.blockhash(), solana.TransactionPayer(signer.PublicKey()))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(signer.PublicKey()) {
+			return &signer
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	signature, err := sim.Process(tx)
+	if err != nil {
+		return nil, err
+	}
+	result, _ := sim.Transaction(signature)
+	return result, nil
+}
+
+func buyInstruction(mint, user solana.PublicKey, tokens, maxSolCost uint64) solana.Instruction {
+	bondingCurve, associatedBondingCurve, _ := pumpfun.DeriveBondingCurveAddresses(mint)
+	ata, _, _ := solana.FindAssociatedTokenAddress(user, mint)
+	// The tests only buy tokens the user created
+	creatorVault, _ := pumpfun.DeriveCreatorVault(user)
+	return pump.NewBuyInstruction(
+		tokens, maxSolCost,
+		pumpfun.GlobalPumpFunAddress, pumpfun.PumpFunFeeRecipient, mint, bondingCurve, associatedBondingCurve, ata, user,
+		system.ProgramID, token.ProgramID, creatorVault, pumpfun.EventAuthority, pumpfun.ProgramID,
+	).Build()
+}
+
+func sellInstruction(mint, user solana.PublicKey, tokens, minSolOutput uint64) solana.Instruction {
+	bondingCurve, associatedBondingCurve, _ := pumpfun.DeriveBondingCurveAddresses(mint)
+	ata, _, _ := solana.FindAssociatedTokenAddress(user, mint)
+	return pump.NewSellInstruction(
+		tokens, minSolOutput,
+		pumpfun.GlobalPumpFunAddress, pumpfun.PumpFunFeeRecipient, mint, bondingCurve, associatedBondingCurve, ata, user,
+		system.ProgramID, associatedtokenaccount.ProgramID, token.ProgramID, pumpfun.EventAuthority, pumpfun.ProgramID,
+	).Build()
+}
+
+func events(t *testing.T, logs []string) []*pump.Event {
+	t.Helper()
+
+	var out []*pump.Event
+	for _, log := range logs {
+		encoded, ok := strings.CutPrefix(log, "Program data: ")
+		if !ok {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			t.Fatalf("invalid event data: %v", err)
+		}
+		event, err := pump.DecodeEvent(data)
+		if err != nil {
+			t.Fatalf("failed to decode event: %v", err)
+		}
+		out = append(out, event)
+	}
+	return out
+}
+
+func TestCreateTokenWithBuy(t *testing.T) {
+	sim, user := newTestSimulator(t)
+	before := sim.Balance(user.PublicKey())
+
+	mint := createToken(t, sim, user, 1)
+
+	curve, ok := sim.BondingCurve(mint)
+	if !ok {
+		t.Fatal("bonding curve was not created")
+	}
+
+	bought := sim.TokenBalance(user.PublicKey(), mint)
+	if bought == 0 {
+		t.Fatal("user received no tokens")
+	}
+	if curve.RealTokenReserves != DefaultGlobal.InitialRealTokenReserves-bought {
+		t.Errorf("real token reserves = %d, want %d", curve.RealTokenReserves, DefaultGlobal.InitialRealTokenReserves-bought)
+	}
+
+	// The curve keeps the cost net of fees, the fee recipient gets the rest
+	fee := sim.Balance(pumpfun.PumpFunFeeRecipient)
+	spent := before - sim.Balance(user.PublicKey()) - 2*LamportsPerSignature
+	if spent != curve.RealSolReserves+fee {
+		t.Errorf("user spent %d, want reserves %d + fee %d", spent, curve.RealSolReserves, fee)
+	}
+	if want := (curve.RealSolReserves*100 + 9_999) / 10_000; fee != want && fee != want+1 {
+		t.Errorf("fee = %d, want ~%d", fee, want)
+	}
+
+	// GetBondingCurve reads the account the simulator serves
+	onchain, err := pumpfun.GetBondingCurve(context.Background(), sim.Client(), mint)
+	if err != nil {
+		t.Fatalf("failed to read bonding curve: %v", err)
+	}
+	if onchain.BondingCurve != curve {
+		t.Errorf("served curve = %+v, want %+v", onchain.BondingCurve, curve)
+	}
+}
+
+func TestSell(t *testing.T) {
+	sim, user := newTestSimulator(t)
+	mint := createToken(t, sim, user, 1)
+
+	tokens := sim.TokenBalance(user.PublicKey(), mint)
+	curveBefore, _ := sim.BondingCurve(mint)
+	balanceBefore := sim.Balance(user.PublicKey())
+
+	result, err := process(sim, user, sellInstruction(mint, user.PublicKey(), tokens/2, 0))
+	if err != nil {
+		t.Fatalf("sell failed: %v", err)
+	}
+
+	if got := sim.TokenBalance(user.PublicKey(), mint); got != tokens-tokens/2 {
+		t.Errorf("token balance = %d, want %d", got, tokens-tokens/2)
+	}
+
+	curve, _ := sim.BondingCurve(mint)
+	received := sim.Balance(user.PublicKey()) + LamportsPerSignature - balanceBefore
+	if taken := curveBefore.RealSolReserves - curve.RealSolReserves; taken <= received {
+		t.Errorf("curve paid out %d, want more than the %d received after fees", taken, received)
+	}
+
+	evs := events(t, result.Logs)
+	if len(evs) != 1 || evs[0].TypeID != pump.Event_Trade {
+		t.Fatalf("events = %v, want one trade", evs)
+	}
+	trade := evs[0].Impl.(*pump.TradeEvent)
+	if trade.IsBuy || trade.TokenAmount != tokens/2 || trade.Timestamp != testTime.Unix() {
+		t.Errorf("unexpected trade event %+v", trade)
+	}
+}
+
+func TestBuyCapsAtRealReservesAndCompletes(t *testing.T) {
+	sim, user := newTestSimulator(t)
+	mint := createToken(t, sim, user, 0)
+
+	ata, _, _ := solana.FindAssociatedTokenAddress(user.PublicKey(), mint)
+	result, err := process(sim, user,
+		associatedtokenaccount.NewCreateInstruction(user.PublicKey(), user.PublicKey(), mint).Build(),
+		buyInstruction(mint, user.PublicKey(), DefaultGlobal.TokenTotalSupply, 500*solana.LAMPORTS_PER_SOL),
+	)
+	if err != nil {
+		t.Fatalf("buy failed: %v", err)
+	}
+
+	if got := sim.TokenBalance(user.PublicKey(), mint); got != DefaultGlobal.InitialRealTokenReserves {
+		t.Errorf("token balance of %s = %d, want the real reserves %d", ata, got, DefaultGlobal.InitialRealTokenReserves)
+	}
+
+	curve, _ := sim.BondingCurve(mint)
+	if !curve.Complete || curve.RealTokenReserves != 0 {
+		t.Errorf("curve = %+v, want complete with no real tokens", curve)
+	}
+
+	evs := events(t, result.Logs)
+	if len(evs) != 2 || evs[0].TypeID != pump.Event_Trade || evs[1].TypeID != pump.Event_Complete {
+		t.Fatalf("events = %v, want trade then complete", evs)
+	}
+
+	if _, err := process(sim, user, buyInstruction(mint, user.PublicKey(), 1_000_000, solana.LAMPORTS_PER_SOL)); err == nil || !strings.Contains(err.Error(), "BondingCurveComplete") {
+		t.Errorf("buy after completion: err = %v, want BondingCurveComplete", err)
+	}
+}
+
+func TestFailedTransactionLeavesStateUnchanged(t *testing.T) {
+	sim, user := newTestSimulator(t)
+	mint := createToken(t, sim, user, 0)
+
+	curveBefore, _ := sim.BondingCurve(mint)
+	balanceBefore := sim.Balance(user.PublicKey())
+
+	// The ATA would be created, but the buy exceeds its max SOL cost
+	_, err := process(sim, user,
+		associatedtokenaccount.NewCreateInstruction(user.PublicKey(), user.PublicKey(), mint).Build(),
+		buyInstruction(mint, user.PublicKey(), 10_000_000_000_000, 1_000),
+	)
+	if err == nil || !strings.Contains(err.Error(), "TooMuchSolRequired") {
+		t.Fatalf("err = %v, want TooMuchSolRequired", err)
+	}
+
+	if curve, _ := sim.BondingCurve(mint); curve != curveBefore {
+		t.Errorf("curve changed to %+v", curve)
+	}
+	if balance := sim.Balance(user.PublicKey()); balance != balanceBefore {
+		t.Errorf("balance changed from %d to %d", balanceBefore, balance)
+	}
+	if _, err := process(sim, user, sellInstruction(mint, user.PublicKey(), 1, 0)); err == nil {
+		t.Error("sell succeeded without a token account")
+	}
+}
+
+func TestWrongFeeRecipient(t *testing.T) {
+	sim, user := newTestSimulator(t)
+	mint := createToken(t, sim, user, 0)
+
+	global := sim.Global()
+	global.FeeRecipient = solana.NewWallet().PublicKey()
+	sim.SetGlobal(global)
+
+	_, err := process(sim, user,
+		associatedtokenaccount.NewCreateInstruction(user.PublicKey(), user.PublicKey(), mint).Build(),
+		buyInstruction(mint, user.PublicKey(), 1_000_000, solana.LAMPORTS_PER_SOL),
+	)
+	if err == nil || !strings.Contains(err.Error(), "NotAuthorized") {
+		t.Errorf("err = %v, want NotAuthorized", err)
+	}
+}
+
+func TestBuyRequiresCreatorVault(t *testing.T) {
+	sim, user := newTestSimulator(t)
+	mint := createToken(t, sim, user, 0)
+
+	// Buys used to pass the rent sysvar where the creator vault now goes
+	buy := buyInstruction(mint, user.PublicKey(), 1_000_000, solana.LAMPORTS_PER_SOL)
+	buy.Accounts()[9].PublicKey = solana.SysVarRentPubkey
+
+	_, err := process(sim, user,
+		associatedtokenaccount.NewCreateInstruction(user.PublicKey(), user.PublicKey(), mint).Build(),
+		buy,
+	)
+	if err == nil || !strings.Contains(err.Error(), "ConstraintSeeds") {
+		t.Errorf("err = %v, want ConstraintSeeds", err)
+	}
+}
+
+func TestBuildersFollowGlobal(t *testing.T) {
+	sim, user := newTestSimulator(t)
+
+	global := sim.Global()
+	global.FeeRecipient = solana.NewWallet().PublicKey()
+	global.FeeBasisPoints = 200
+	sim.SetGlobal(global)
+
+	mint := createToken(t, sim, user, 1)
+
+	curve, _ := sim.BondingCurve(mint)
+	fee := sim.Balance(global.FeeRecipient)
+	if want := (curve.RealSolReserves*200 + 9_999) / 10_000; fee != want && fee != want+1 {
+		t.Errorf("fee paid to the Global fee recipient = %d, want ~%d", fee, want)
+	}
+	if sim.Balance(pumpfun.PumpFunFeeRecipient) != 0 {
+		t.Error("fee was paid to the hard-coded fee recipient")
+	}
+}
+
+func TestGlobalAccountCache(t *testing.T) {
+	sim := New()
+	client := sim.Client()
+	ctx := context.Background()
+
+	cache := pumpfun.NewGlobalAccountCache(client)
+	first, err := cache.Get(ctx)
+	if err != nil {
+		t.Fatalf("failed to get global account: %v", err)
+	}
+	if first.Global != DefaultGlobal {
+		t.Fatalf("global = %+v, want %+v", first.Global, DefaultGlobal)
+	}
+
+	global := sim.Global()
+	global.FeeBasisPoints = 50
+	sim.SetGlobal(global)
+
+	cached, _ := cache.Get(ctx)
+	if cached.FeeBasisPoints != DefaultGlobal.FeeBasisPoints {
+		t.Errorf("cached fee = %d bps, want %d", cached.FeeBasisPoints, DefaultGlobal.FeeBasisPoints)
+	}
+
+	cache.Invalidate()
+	fresh, _ := cache.Get(ctx)
+	if fresh.FeeBasisPoints != 50 {
+		t.Errorf("fee after invalidation = %d bps, want 50", fresh.FeeBasisPoints)
+	}
+}
+
+func TestCreateTokenValidatesAgainstGlobal(t *testing.T) {
+	sim, user := newTestSimulator(t)
+
+	request := pumpfun.CreateTokenRequest{
+		RpcClient:       sim.Client(),
+		TokenInfo:       pumpfun.CreateTokenInformation{Name: "Test", Symbol: "TEST"},
+		Mint:            solana.NewWallet(),
+		UserPrivateKey:  user,
+		BuyAmount:       1,
+		SlippagePercent: 1,
+	}
+
+	// The buy is quoted on the initial curve with the global fee, so it
+	// spends the requested SOL and fits a tight slippage
+	if _, err := pumpfun.CreateToken(context.Background(), request); err != nil {
+		t.Fatalf("failed to create token with 1%% slippage: %v", err)
+	}
+	global := sim.Global()
+	initial := pump.BondingCurve{
+		VirtualTokenReserves: global.InitialVirtualTokenReserves,
+		VirtualSolReserves:   global.InitialVirtualSolReserves,
+		RealTokenReserves:    global.InitialRealTokenReserves,
+		TokenTotalSupply:     global.TokenTotalSupply,
+	}
+	want, err := pumpfun_quote.BuyExactSolIn(initial, pumpfun_quote.Fees{ProtocolBps: global.FeeBasisPoints}, solana.LAMPORTS_PER_SOL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sim.TokenBalance(user.PublicKey(), request.Mint.PublicKey()); got != want.TokenAmount {
+		t.Errorf("bought %d tokens, want %d", got, want.TokenAmount)
+	}
+	if curve, _ := sim.BondingCurve(request.Mint.PublicKey()); curve.RealSolReserves > solana.LAMPORTS_PER_SOL {
+		t.Errorf("curve took %d lamports for a 1 SOL buy", curve.RealSolReserves)
+	}
+
+	global.Initialized = false
+	sim.SetGlobal(global)
+
+	request.RpcClient = sim.Client()
+	request.Mint = solana.NewWallet()
+	if _, err := pumpfun.CreateToken(context.Background(), request); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("err = %v, want not initialized", err)
+	}
+}
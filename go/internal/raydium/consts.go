@@ -0,0 +1,42 @@
+package raydium
+
+import "github.com/gagliardetto/solana-go"
+
+var (
+	AmmV4ProgramID = solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")
+	CpmmProgramID  = solana.MustPublicKeyFromBase58("CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C")
+
+	// AmmV4Authority is the PDA that owns the vaults of every AMM v4 pool
+	AmmV4Authority = solana.MustPublicKeyFromBase58("5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1")
+
+	WSOLMint = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+)
+
+const (
+	AmmV4PoolSize = 752
+	CpmmPoolSize  = 637
+
+	// TokenAccountRentLamports is the rent-exempt balance of a 165 byte SPL
+	// token account, such as a WSOL account
+	TokenAccountRentLamports = 2_039_280
+
+	// Offsets of the mint fields used to discover pools with getProgramAccounts
+	ammV4BaseMintOffset  = 400
+	ammV4QuoteMintOffset = 432
+	cpmmMint0Offset      = 168
+	cpmmMint1Offset      = 200
+
+	// CPMM fee rates are expressed in hundredths of a basis point
+	cpmmFeeRateDenominator = 1_000_000
+
+	ammV4SwapBaseInInstruction = 9
+
+	cpmmAuthoritySeed = "vault_and_lp_mint_auth_seed"
+)
+
+var (
+	CpmmPoolDiscriminator      = [8]byte{247, 237, 227, 245, 215, 195, 222, 70}
+	CpmmAmmConfigDiscriminator = [8]byte{218, 244, 33, 104, 203, 203, 43, 111}
+
+	cpmmSwapBaseInputDiscriminator = [8]byte{143, 190, 90, 218, 196, 30, 51, 222}
+)
@@ -0,0 +1,229 @@
+package raydium
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+// BuildSwapInstructions builds the instructions for an exact-input swap
+// through a pool, including creating the user's token accounts and wrapping
+// or unwrapping SOL when either side of the pool is WSOL
+func BuildSwapInstructions(request SwapRequest) ([]solana.Instruction, error) {
+	pool := request.Pool
+	if pool == nil {
+		return nil, fmt.Errorf("pool is required")
+	}
+	if !pool.HasMint(request.InputMint) {
+		return nil, fmt.Errorf("pool %s does not trade %s", pool.Address, request.InputMint)
+	}
+
+	inputMint, outputMint := pool.MintA, pool.MintB
+	inputProgram, outputProgram := pool.TokenProgramA, pool.TokenProgramB
+	inputVault, outputVault := pool.VaultA, pool.VaultB
+	if request.InputMint.Equals(pool.MintB) {
+		inputMint, outputMint = pool.MintB, pool.MintA
+		inputProgram, outputProgram = pool.TokenProgramB, pool.TokenProgramA
+		inputVault, outputVault = pool.VaultB, pool.VaultA
+	}
+
+	userInput, err := FindAssociatedTokenAddress(request.User, inputMint, inputProgram)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive input token account: %w", err)
+	}
+	userOutput, err := FindAssociatedTokenAddress(request.User, outputMint, outputProgram)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive output token account: %w", err)
+	}
+
+	payer := request.Payer
+	if payer.IsZero() {
+		payer = request.User
+	}
+
+	var instructions []solana.Instruction
+
+	if inputMint.Equals(WSOLMint) {
+		instructions = append(instructions,
+			NewCreateIdempotentATAInstruction(payer, request.User, inputMint, inputProgram),
+			system.NewTransferInstruction(request.AmountIn, request.User, userInput).Build(),
+			token.NewSyncNativeInstruction(userInput).Build(),
+		)
+	}
+	instructions = append(instructions, NewCreateIdempotentATAInstruction(payer, request.User, outputMint, outputProgram))
+
+	var swapIx solana.Instruction
+	switch pool.Type {
+	case PoolTypeAmmV4:
+		swapIx, err = newAmmV4SwapBaseInInstruction(pool, userInput, userOutput, request.User, request.AmountIn, request.MinAmountOut)
+	case PoolTypeCpmm:
+		swapIx, err = newCpmmSwapBaseInputInstruction(
+			pool,
+			request.User,
+			userInput, userOutput,
+			inputVault, outputVault,
+			inputProgram, outputProgram,
+			inputMint, outputMint,
+			request.AmountIn, request.MinAmountOut,
+		)
+	default:
+		err = fmt.Errorf("unsupported pool type: %s", pool.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	instructions = append(instructions, swapIx)
+
+	// Close WSOL accounts so the user ends up with native SOL. The emptied
+	// input account only holds rent, which goes back to the payer. The output
+	// account holds the proceeds too, so it closes to the user, who then
+	// refunds the rent the payer put up for it.
+	for _, wsolAccount := range []struct {
+		mint        solana.PublicKey
+		account     solana.PublicKey
+		destination solana.PublicKey
+	}{{inputMint, userInput, payer}, {outputMint, userOutput, request.User}} {
+		if wsolAccount.mint.Equals(WSOLMint) {
+			closeIx, err := token.NewCloseAccountInstruction(
+				wsolAccount.account,
+				wsolAccount.destination,
+				request.User,
+				[]solana.PublicKey{},
+			).ValidateAndBuild()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create close account instruction: %w", err)
+			}
+			instructions = append(instructions, closeIx)
+		}
+	}
+	if outputMint.Equals(WSOLMint) && !payer.Equals(request.User) {
+		instructions = append(instructions, system.NewTransferInstruction(TokenAccountRentLamports, request.User, payer).Build())
+	}
+
+	return instructions, nil
+}
+
+func newAmmV4SwapBaseInInstruction(
+	pool *Pool,
+	userSource, userDestination, user solana.PublicKey,
+	amountIn, minAmountOut uint64,
+) (solana.Instruction, error) {
+	market := pool.Market
+
+	nonce := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nonce, uint64(market.VaultSignerNonce))
+	vaultSigner, err := solana.CreateProgramAddress(
+		[][]byte{pool.AmmV4.MarketID.Bytes(), nonce},
+		pool.AmmV4.MarketProgramID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive market vault signer: %w", err)
+	}
+
+	data := make([]byte, 17)
+	data[0] = ammV4SwapBaseInInstruction
+	binary.LittleEndian.PutUint64(data[1:9], amountIn)
+	binary.LittleEndian.PutUint64(data[9:17], minAmountOut)
+
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(solana.TokenProgramID),
+		solana.Meta(pool.Address).WRITE(),
+		solana.Meta(AmmV4Authority),
+		solana.Meta(pool.AmmV4.OpenOrders).WRITE(),
+		solana.Meta(pool.AmmV4.TargetOrders).WRITE(),
+		solana.Meta(pool.AmmV4.BaseVault).WRITE(),
+		solana.Meta(pool.AmmV4.QuoteVault).WRITE(),
+		solana.Meta(pool.AmmV4.MarketProgramID),
+		solana.Meta(pool.AmmV4.MarketID).WRITE(),
+		solana.Meta(market.Bids).WRITE(),
+		solana.Meta(market.Asks).WRITE(),
+		solana.Meta(market.EventQueue).WRITE(),
+		solana.Meta(market.BaseVault).WRITE(),
+		solana.Meta(market.QuoteVault).WRITE(),
+		solana.Meta(vaultSigner),
+		solana.Meta(userSource).WRITE(),
+		solana.Meta(userDestination).WRITE(),
+		solana.Meta(user).SIGNER(),
+	}
+
+	return solana.NewInstruction(AmmV4ProgramID, accounts, data), nil
+}
+
+func newCpmmSwapBaseInputInstruction(
+	pool *Pool,
+	payer solana.PublicKey,
+	inputTokenAccount, outputTokenAccount solana.PublicKey,
+	inputVault, outputVault solana.PublicKey,
+	inputTokenProgram, outputTokenProgram solana.PublicKey,
+	inputMint, outputMint solana.PublicKey,
+	amountIn, minAmountOut uint64,
+) (solana.Instruction, error) {
+	authority, err := DeriveCpmmAuthority()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 24)
+	copy(data[0:8], cpmmSwapBaseInputDiscriminator[:])
+	binary.LittleEndian.PutUint64(data[8:16], amountIn)
+	binary.LittleEndian.PutUint64(data[16:24], minAmountOut)
+
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(payer).SIGNER(),
+		solana.Meta(authority),
+		solana.Meta(pool.Cpmm.AmmConfig),
+		solana.Meta(pool.Address).WRITE(),
+		solana.Meta(inputTokenAccount).WRITE(),
+		solana.Meta(outputTokenAccount).WRITE(),
+		solana.Meta(inputVault).WRITE(),
+		solana.Meta(outputVault).WRITE(),
+		solana.Meta(inputTokenProgram),
+		solana.Meta(outputTokenProgram),
+		solana.Meta(inputMint),
+		solana.Meta(outputMint),
+		solana.Meta(pool.Cpmm.ObservationKey).WRITE(),
+	}
+
+	return solana.NewInstruction(CpmmProgramID, accounts, data), nil
+}
+
+// DeriveCpmmAuthority returns the PDA that owns the vaults and LP mint of every CPMM pool
+func DeriveCpmmAuthority() (solana.PublicKey, error) {
+	authority, _, err := solana.FindProgramAddress([][]byte{[]byte(cpmmAuthoritySeed)}, CpmmProgramID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive CPMM authority: %w", err)
+	}
+	return authority, nil
+}
+
+// FindAssociatedTokenAddress derives the associated token account of a wallet
+// for a mint owned by either the Token or the Token-2022 program
+func FindAssociatedTokenAddress(owner, mint, tokenProgram solana.PublicKey) (solana.PublicKey, error) {
+	address, _, err := solana.FindProgramAddress(
+		[][]byte{owner.Bytes(), tokenProgram.Bytes(), mint.Bytes()},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
+	return address, err
+}
+
+// NewCreateIdempotentATAInstruction creates the associated token account of
+// owner for mint unless it already exists
+func NewCreateIdempotentATAInstruction(payer, owner, mint, tokenProgram solana.PublicKey) solana.Instruction {
+	ata, _ := FindAssociatedTokenAddress(owner, mint, tokenProgram)
+
+	return solana.NewInstruction(
+		solana.SPLAssociatedTokenAccountProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(payer).WRITE().SIGNER(),
+			solana.Meta(ata).WRITE(),
+			solana.Meta(owner),
+			solana.Meta(mint),
+			solana.Meta(solana.SystemProgramID),
+			solana.Meta(tokenProgram),
+		},
+		[]byte{1},
+	)
+}
@@ -0,0 +1,230 @@
+package raydium
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/serum"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// FindPools discovers every AMM v4 and CPMM pool trading the given pair and
+// loads its reserves. Pools are returned deepest first, measured by the
+// reserve of mintB.
+func FindPools(ctx context.Context, rpcClient *rpc.Client, mintA, mintB solana.PublicKey) ([]*Pool, error) {
+	var pools []*Pool
+
+	for _, pair := range [][2]solana.PublicKey{{mintA, mintB}, {mintB, mintA}} {
+		ammV4Accounts, err := rpcClient.GetProgramAccountsWithOpts(ctx, AmmV4ProgramID, &rpc.GetProgramAccountsOpts{
+			Encoding: solana.EncodingBase64,
+			Filters: []rpc.RPCFilter{
+				{DataSize: AmmV4PoolSize},
+				{Memcmp: &rpc.RPCFilterMemcmp{Offset: ammV4BaseMintOffset, Bytes: pair[0].Bytes()}},
+				{Memcmp: &rpc.RPCFilterMemcmp{Offset: ammV4QuoteMintOffset, Bytes: pair[1].Bytes()}},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get AMM v4 pools: %w", err)
+		}
+		for _, account := range ammV4Accounts {
+			pool, err := LoadPool(ctx, rpcClient, account.Pubkey, account.Account)
+			if err != nil {
+				continue
+			}
+			pools = append(pools, pool)
+		}
+
+		cpmmAccounts, err := rpcClient.GetProgramAccountsWithOpts(ctx, CpmmProgramID, &rpc.GetProgramAccountsOpts{
+			Encoding: solana.EncodingBase64,
+			Filters: []rpc.RPCFilter{
+				{DataSize: CpmmPoolSize},
+				{Memcmp: &rpc.RPCFilterMemcmp{Offset: cpmmMint0Offset, Bytes: pair[0].Bytes()}},
+				{Memcmp: &rpc.RPCFilterMemcmp{Offset: cpmmMint1Offset, Bytes: pair[1].Bytes()}},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CPMM pools: %w", err)
+		}
+		for _, account := range cpmmAccounts {
+			pool, err := LoadPool(ctx, rpcClient, account.Pubkey, account.Account)
+			if err != nil {
+				continue
+			}
+			pools = append(pools, pool)
+		}
+	}
+
+	if len(pools) == 0 {
+		return nil, fmt.Errorf("no Raydium pools found for %s/%s", mintA, mintB)
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].reserveOf(mintB) > pools[j].reserveOf(mintB)
+	})
+
+	return pools, nil
+}
+
+// GetPool fetches and decodes a single pool by address
+func GetPool(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey) (*Pool, error) {
+	account, err := rpcClient.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool account: %w", err)
+	}
+
+	return LoadPool(ctx, rpcClient, address, account.Value)
+}
+
+// LoadPool decodes a pool account owned by either Raydium program and loads
+// the accounts needed to quote and swap against it
+func LoadPool(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey, account *rpc.Account) (*Pool, error) {
+	if account == nil {
+		return nil, fmt.Errorf("pool account not found")
+	}
+
+	var (
+		pool *Pool
+		err  error
+	)
+	switch {
+	case account.Owner.Equals(AmmV4ProgramID):
+		pool, err = loadAmmV4Pool(ctx, rpcClient, address, account.Data.GetBinary())
+	case account.Owner.Equals(CpmmProgramID):
+		pool, err = loadCpmmPool(ctx, rpcClient, address, account.Data.GetBinary())
+	default:
+		return nil, fmt.Errorf("account %s is not a Raydium AMM v4 or CPMM pool", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.RefreshReserves(ctx, rpcClient); err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+func loadAmmV4Pool(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey, data []byte) (*Pool, error) {
+	var state AmmV4Pool
+	if err := state.FromBuffer(data); err != nil {
+		return nil, fmt.Errorf("failed to parse AMM v4 pool: %w", err)
+	}
+
+	marketAccount, err := rpcClient.GetAccountInfo(ctx, state.MarketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market account: %w", err)
+	}
+
+	var market serum.MarketV2
+	if err := market.Decode(marketAccount.Value.Data.GetBinary()); err != nil {
+		return nil, fmt.Errorf("failed to parse market account: %w", err)
+	}
+
+	return &Pool{
+		Type:           PoolTypeAmmV4,
+		Address:        address,
+		MintA:          state.BaseMint,
+		MintB:          state.QuoteMint,
+		VaultA:         state.BaseVault,
+		VaultB:         state.QuoteVault,
+		TokenProgramA:  solana.TokenProgramID,
+		TokenProgramB:  solana.TokenProgramID,
+		FeeNumerator:   state.SwapFeeNumerator,
+		FeeDenominator: state.SwapFeeDenominator,
+		AmmV4:          &state,
+		Market:         &market,
+	}, nil
+}
+
+func loadCpmmPool(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey, data []byte) (*Pool, error) {
+	var state CpmmPool
+	if err := state.FromBuffer(data); err != nil {
+		return nil, fmt.Errorf("failed to parse CPMM pool: %w", err)
+	}
+
+	configAccount, err := rpcClient.GetAccountInfo(ctx, state.AmmConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amm config account: %w", err)
+	}
+
+	var config CpmmAmmConfig
+	if err := config.FromBuffer(configAccount.Value.Data.GetBinary()); err != nil {
+		return nil, fmt.Errorf("failed to parse amm config account: %w", err)
+	}
+
+	return &Pool{
+		Type:           PoolTypeCpmm,
+		Address:        address,
+		MintA:          state.Token0Mint,
+		MintB:          state.Token1Mint,
+		VaultA:         state.Token0Vault,
+		VaultB:         state.Token1Vault,
+		TokenProgramA:  state.Token0Program,
+		TokenProgramB:  state.Token1Program,
+		FeeNumerator:   config.TradeFeeRate,
+		FeeDenominator: cpmmFeeRateDenominator,
+		Cpmm:           &state,
+		CpmmConfig:     &config,
+	}, nil
+}
+
+// RefreshReserves reloads the vault balances of the pool and subtracts the
+// amounts that belong to the protocol rather than to liquidity providers
+func (p *Pool) RefreshReserves(ctx context.Context, rpcClient *rpc.Client) error {
+	vaults, err := rpcClient.GetMultipleAccounts(ctx, p.VaultA, p.VaultB)
+	if err != nil {
+		return fmt.Errorf("failed to get pool vaults: %w", err)
+	}
+
+	balances := make([]uint64, 2)
+	for i, vault := range vaults.Value {
+		if vault == nil {
+			return fmt.Errorf("pool vault not found")
+		}
+		data := vault.Data.GetBinary()
+		if len(data) < 72 {
+			return fmt.Errorf("invalid pool vault data")
+		}
+		balances[i] = binary.LittleEndian.Uint64(data[64:72])
+	}
+
+	var pendingA, pendingB uint64
+	switch p.Type {
+	case PoolTypeAmmV4:
+		pendingA, pendingB = p.AmmV4.BaseNeedTakePnl, p.AmmV4.QuoteNeedTakePnl
+	case PoolTypeCpmm:
+		pendingA = p.Cpmm.ProtocolFeesToken0 + p.Cpmm.FundFeesToken0
+		pendingB = p.Cpmm.ProtocolFeesToken1 + p.Cpmm.FundFeesToken1
+	}
+
+	p.ReserveA = saturatingSub(balances[0], pendingA)
+	p.ReserveB = saturatingSub(balances[1], pendingB)
+
+	return nil
+}
+
+// HasMint reports whether the pool trades the given mint
+func (p *Pool) HasMint(mint solana.PublicKey) bool {
+	return p.MintA.Equals(mint) || p.MintB.Equals(mint)
+}
+
+func (p *Pool) reserveOf(mint solana.PublicKey) uint64 {
+	if p.MintA.Equals(mint) {
+		return p.ReserveA
+	}
+	if p.MintB.Equals(mint) {
+		return p.ReserveB
+	}
+	return 0
+}
+
+func saturatingSub(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,98 @@
+package raydium
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// GetQuote computes the output of swapping an exact amount of inputMint
+// through the pool, after the pool's trade fee. slippageBps derives the
+// minimum output that the swap instruction will accept.
+func (p *Pool) GetQuote(inputMint solana.PublicKey, amountIn uint64, slippageBps uint64) (*Quote, error) {
+	if !p.HasMint(inputMint) {
+		return nil, fmt.Errorf("pool %s does not trade %s", p.Address, inputMint)
+	}
+	if amountIn == 0 {
+		return nil, fmt.Errorf("amount in must be greater than zero")
+	}
+	if p.FeeDenominator == 0 {
+		return nil, fmt.Errorf("pool %s has no fee denominator", p.Address)
+	}
+
+	reserveIn, reserveOut, outputMint := p.ReserveA, p.ReserveB, p.MintB
+	if inputMint.Equals(p.MintB) {
+		reserveIn, reserveOut, outputMint = p.ReserveB, p.ReserveA, p.MintA
+	}
+	if reserveIn == 0 || reserveOut == 0 {
+		return nil, fmt.Errorf("pool %s has no liquidity", p.Address)
+	}
+
+	in := new(big.Int).SetUint64(amountIn)
+
+	fee := new(big.Int).Mul(in, new(big.Int).SetUint64(p.FeeNumerator))
+	denominator := new(big.Int).SetUint64(p.FeeDenominator)
+	fee.Add(fee, new(big.Int).Sub(denominator, big.NewInt(1)))
+	fee.Div(fee, denominator)
+
+	inLessFee := new(big.Int).Sub(in, fee)
+
+	// Constant product: out = reserveOut * inLessFee / (reserveIn + inLessFee)
+	out := new(big.Int).Mul(new(big.Int).SetUint64(reserveOut), inLessFee)
+	out.Div(out, new(big.Int).Add(new(big.Int).SetUint64(reserveIn), inLessFee))
+
+	if !out.IsUint64() || out.Sign() <= 0 {
+		return nil, fmt.Errorf("amount in is too small to produce any output")
+	}
+	amountOut := out.Uint64()
+
+	// Price impact compares the execution price against the spot price
+	spot := new(big.Float).Quo(new(big.Float).SetUint64(reserveOut), new(big.Float).SetUint64(reserveIn))
+	execution := new(big.Float).Quo(new(big.Float).SetUint64(amountOut), new(big.Float).SetUint64(amountIn))
+	ratio, _ := new(big.Float).Quo(execution, spot).Float64()
+
+	return &Quote{
+		Pool:           p.Address,
+		PoolType:       p.Type,
+		InputMint:      inputMint,
+		OutputMint:     outputMint,
+		AmountIn:       amountIn,
+		AmountOut:      amountOut,
+		MinAmountOut:   MinAmountOut(amountOut, slippageBps),
+		Fee:            fee.Uint64(),
+		PriceImpactPct: (1 - ratio) * 100,
+	}, nil
+}
+
+// MinAmountOut applies a slippage tolerance in basis points to an expected output
+func MinAmountOut(amountOut uint64, slippageBps uint64) uint64 {
+	if slippageBps >= 10_000 {
+		return 0
+	}
+	out := new(big.Int).Mul(new(big.Int).SetUint64(amountOut), new(big.Int).SetUint64(10_000-slippageBps))
+	return out.Div(out, big.NewInt(10_000)).Uint64()
+}
+
+// BestQuote quotes every pool and returns the one with the highest output
+func BestQuote(pools []*Pool, inputMint solana.PublicKey, amountIn uint64, slippageBps uint64) (*Pool, *Quote, error) {
+	var (
+		bestPool  *Pool
+		bestQuote *Quote
+	)
+	for _, pool := range pools {
+		quote, err := pool.GetQuote(inputMint, amountIn, slippageBps)
+		if err != nil {
+			continue
+		}
+		if bestQuote == nil || quote.AmountOut > bestQuote.AmountOut {
+			bestPool, bestQuote = pool, quote
+		}
+	}
+
+	if bestQuote == nil {
+		return nil, nil, fmt.Errorf("no Raydium pool can fill the swap")
+	}
+
+	return bestPool, bestQuote, nil
+}
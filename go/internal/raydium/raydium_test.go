@@ -0,0 +1,270 @@
+package raydium
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+func TestCpmmPoolFromBuffer(t *testing.T) {
+	mint0 := solana.NewWallet().PublicKey()
+	mint1 := solana.NewWallet().PublicKey()
+
+	data := make([]byte, CpmmPoolSize)
+	copy(data[0:8], CpmmPoolDiscriminator[:])
+	copy(data[cpmmMint0Offset:], mint0.Bytes())
+	copy(data[cpmmMint1Offset:], mint1.Bytes())
+	copy(data[232:264], solana.TokenProgramID.Bytes())
+	copy(data[264:296], solana.Token2022ProgramID.Bytes())
+	data[331] = 9
+	data[332] = 6
+	binary.LittleEndian.PutUint64(data[341:349], 11)
+	binary.LittleEndian.PutUint64(data[365:373], 22)
+
+	var pool CpmmPool
+	if err := pool.FromBuffer(data); err != nil {
+		t.Fatalf("failed to decode pool: %v", err)
+	}
+
+	if !pool.Token0Mint.Equals(mint0) || !pool.Token1Mint.Equals(mint1) {
+		t.Errorf("unexpected mints: %s %s", pool.Token0Mint, pool.Token1Mint)
+	}
+	if !pool.Token1Program.Equals(solana.Token2022ProgramID) {
+		t.Errorf("unexpected token 1 program: %s", pool.Token1Program)
+	}
+	if pool.Mint0Decimals != 9 || pool.Mint1Decimals != 6 {
+		t.Errorf("unexpected decimals: %d %d", pool.Mint0Decimals, pool.Mint1Decimals)
+	}
+	if pool.ProtocolFeesToken0 != 11 || pool.FundFeesToken1 != 22 {
+		t.Errorf("unexpected fees: %d %d", pool.ProtocolFeesToken0, pool.FundFeesToken1)
+	}
+
+	data[0] = 0
+	if err := pool.FromBuffer(data); err == nil {
+		t.Error("expected discriminator error")
+	}
+}
+
+func TestAmmV4PoolFromBuffer(t *testing.T) {
+	baseMint := solana.NewWallet().PublicKey()
+	quoteMint := solana.NewWallet().PublicKey()
+
+	data := make([]byte, AmmV4PoolSize)
+	binary.LittleEndian.PutUint64(data[176:184], 25)
+	binary.LittleEndian.PutUint64(data[184:192], 10_000)
+	copy(data[ammV4BaseMintOffset:], baseMint.Bytes())
+	copy(data[ammV4QuoteMintOffset:], quoteMint.Bytes())
+	binary.LittleEndian.PutUint64(data[720:728], 1_000_000)
+
+	var pool AmmV4Pool
+	if err := pool.FromBuffer(data); err != nil {
+		t.Fatalf("failed to decode pool: %v", err)
+	}
+
+	if !pool.BaseMint.Equals(baseMint) || !pool.QuoteMint.Equals(quoteMint) {
+		t.Errorf("unexpected mints: %s %s", pool.BaseMint, pool.QuoteMint)
+	}
+	if pool.SwapFeeNumerator != 25 || pool.SwapFeeDenominator != 10_000 {
+		t.Errorf("unexpected fee: %d/%d", pool.SwapFeeNumerator, pool.SwapFeeDenominator)
+	}
+	if pool.LpAmount != 1_000_000 {
+		t.Errorf("lp amount = %d, want 1000000", pool.LpAmount)
+	}
+}
+
+func TestGetQuote(t *testing.T) {
+	mintA := solana.NewWallet().PublicKey()
+
+	tests := []struct {
+		name      string
+		pool      Pool
+		inputMint solana.PublicKey
+		amountIn  uint64
+		wantOut   uint64
+		wantFee   uint64
+	}{
+		{
+			name: "amm v4 base in",
+			pool: Pool{
+				Type: PoolTypeAmmV4, MintA: mintA, MintB: WSOLMint,
+				ReserveA: 1_000_000_000, ReserveB: 2_000_000_000,
+				FeeNumerator: 25, FeeDenominator: 10_000,
+			},
+			inputMint: mintA,
+			amountIn:  1_000_000,
+			wantOut:   1_993_011,
+			wantFee:   2_500,
+		},
+		{
+			name: "amm v4 rounds fee up",
+			pool: Pool{
+				Type: PoolTypeAmmV4, MintA: mintA, MintB: WSOLMint,
+				ReserveA: 1_000_000_000, ReserveB: 2_000_000_000,
+				FeeNumerator: 25, FeeDenominator: 10_000,
+			},
+			inputMint: mintA,
+			amountIn:  1_000_001,
+			wantOut:   1_993_011,
+			wantFee:   2_501,
+		},
+		{
+			name: "cpmm rounds fee up",
+			pool: Pool{
+				Type: PoolTypeCpmm, MintA: WSOLMint, MintB: mintA,
+				ReserveA: 500_000_000, ReserveB: 800_000_000_000,
+				FeeNumerator: 2_500, FeeDenominator: cpmmFeeRateDenominator,
+			},
+			inputMint: mintA,
+			amountIn:  1_000_001,
+			wantOut:   623,
+			wantFee:   2_501,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := tt.pool.GetQuote(tt.inputMint, tt.amountIn, 100)
+			if err != nil {
+				t.Fatalf("failed to get quote: %v", err)
+			}
+			if quote.AmountOut != tt.wantOut {
+				t.Errorf("expected amount out %d, got %d", tt.wantOut, quote.AmountOut)
+			}
+			if quote.Fee != tt.wantFee {
+				t.Errorf("expected fee %d, got %d", tt.wantFee, quote.Fee)
+			}
+			if quote.MinAmountOut != MinAmountOut(tt.wantOut, 100) {
+				t.Errorf("unexpected min amount out %d", quote.MinAmountOut)
+			}
+			if quote.PriceImpactPct <= 0 {
+				t.Errorf("expected positive price impact, got %f", quote.PriceImpactPct)
+			}
+		})
+	}
+}
+
+func TestBuildSwapInstructionsWrapsSol(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	user := solana.NewWallet().PublicKey()
+
+	pool := &Pool{
+		Type:          PoolTypeCpmm,
+		Address:       solana.NewWallet().PublicKey(),
+		MintA:         WSOLMint,
+		MintB:         mint,
+		VaultA:        solana.NewWallet().PublicKey(),
+		VaultB:        solana.NewWallet().PublicKey(),
+		TokenProgramA: solana.TokenProgramID,
+		TokenProgramB: solana.Token2022ProgramID,
+		Cpmm: &CpmmPool{
+			AmmConfig:      solana.NewWallet().PublicKey(),
+			ObservationKey: solana.NewWallet().PublicKey(),
+		},
+	}
+
+	instructions, err := BuildSwapInstructions(SwapRequest{
+		Pool:         pool,
+		User:         user,
+		InputMint:    WSOLMint,
+		AmountIn:     1_000,
+		MinAmountOut: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to build swap instructions: %v", err)
+	}
+
+	// create WSOL ATA, transfer, sync, create output ATA, swap, close WSOL
+	if len(instructions) != 6 {
+		t.Fatalf("expected 6 instructions, got %d", len(instructions))
+	}
+
+	swap := instructions[4]
+	if !swap.ProgramID().Equals(CpmmProgramID) {
+		t.Fatalf("expected CPMM swap, got program %s", swap.ProgramID())
+	}
+
+	outputATA, err := FindAssociatedTokenAddress(user, mint, solana.Token2022ProgramID)
+	if err != nil {
+		t.Fatalf("failed to derive ATA: %v", err)
+	}
+	if !swap.Accounts()[5].PublicKey.Equals(outputATA) {
+		t.Errorf("expected Token-2022 output account %s, got %s", outputATA, swap.Accounts()[5].PublicKey)
+	}
+	if !swap.Accounts()[9].PublicKey.Equals(solana.Token2022ProgramID) {
+		t.Errorf("expected Token-2022 output program, got %s", swap.Accounts()[9].PublicKey)
+	}
+}
+
+func TestBuildSwapInstructionsWithPayer(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	user := solana.NewWallet().PublicKey()
+	payer := solana.NewWallet().PublicKey()
+
+	pool := &Pool{
+		Type:          PoolTypeCpmm,
+		Address:       solana.NewWallet().PublicKey(),
+		MintA:         WSOLMint,
+		MintB:         mint,
+		VaultA:        solana.NewWallet().PublicKey(),
+		VaultB:        solana.NewWallet().PublicKey(),
+		TokenProgramA: solana.TokenProgramID,
+		TokenProgramB: solana.TokenProgramID,
+		Cpmm: &CpmmPool{
+			AmmConfig:      solana.NewWallet().PublicKey(),
+			ObservationKey: solana.NewWallet().PublicKey(),
+		},
+	}
+
+	instructions, err := BuildSwapInstructions(SwapRequest{
+		Pool:         pool,
+		User:         user,
+		Payer:        payer,
+		InputMint:    WSOLMint,
+		AmountIn:     1_000,
+		MinAmountOut: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to build swap instructions: %v", err)
+	}
+
+	// Both ATA creations are funded by the payer but owned by the user
+	for _, i := range []int{0, 3} {
+		accounts := instructions[i].Accounts()
+		if !accounts[0].PublicKey.Equals(payer) || !accounts[2].PublicKey.Equals(user) {
+			t.Errorf("instruction %d: expected payer %s and owner %s, got %s and %s", i, payer, user, accounts[0].PublicKey, accounts[2].PublicKey)
+		}
+	}
+
+	// The emptied WSOL input account refunds its rent to the payer
+	closeAccounts := instructions[5].Accounts()
+	if !closeAccounts[1].PublicKey.Equals(payer) || !closeAccounts[2].PublicKey.Equals(user) {
+		t.Errorf("expected close to payer with user authority, got %s and %s", closeAccounts[1].PublicKey, closeAccounts[2].PublicKey)
+	}
+	// Selling closes the WSOL output account to the user, who refunds the
+	// payer the rent it put up
+	instructions, err = BuildSwapInstructions(SwapRequest{
+		Pool:         pool,
+		User:         user,
+		Payer:        payer,
+		InputMint:    mint,
+		AmountIn:     1_000,
+		MinAmountOut: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to build sell instructions: %v", err)
+	}
+	if len(instructions) != 4 {
+		t.Fatalf("expected create, swap, close and refund, got %d instructions", len(instructions))
+	}
+	closeAccounts = instructions[2].Accounts()
+	if !closeAccounts[1].PublicKey.Equals(user) {
+		t.Errorf("expected WSOL output to close to the user, got %s", closeAccounts[1].PublicKey)
+	}
+	refund := instructions[3]
+	refundAccounts := refund.Accounts()
+	if !refund.ProgramID().Equals(system.ProgramID) || !refundAccounts[0].PublicKey.Equals(user) || !refundAccounts[1].PublicKey.Equals(payer) {
+		t.Errorf("expected the user to refund the payer, got %s -> %s", refundAccounts[0].PublicKey, refundAccounts[1].PublicKey)
+	}
+}
@@ -0,0 +1,211 @@
+package raydium
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/serum"
+)
+
+type PoolType string
+
+const (
+	PoolTypeAmmV4 PoolType = "amm_v4"
+	PoolTypeCpmm  PoolType = "cpmm"
+)
+
+// AmmV4Pool holds the fields of a Raydium AMM v4 pool account needed for quoting and swapping
+type AmmV4Pool struct {
+	Status             uint64
+	Nonce              uint64
+	BaseDecimal        uint64
+	QuoteDecimal       uint64
+	SwapFeeNumerator   uint64
+	SwapFeeDenominator uint64
+	BaseNeedTakePnl    uint64
+	QuoteNeedTakePnl   uint64
+	PoolOpenTime       uint64
+	BaseVault          solana.PublicKey
+	QuoteVault         solana.PublicKey
+	BaseMint           solana.PublicKey
+	QuoteMint          solana.PublicKey
+	LpMint             solana.PublicKey
+	OpenOrders         solana.PublicKey
+	MarketID           solana.PublicKey
+	MarketProgramID    solana.PublicKey
+	TargetOrders       solana.PublicKey
+	// LpAmount is the LP supply the pool has minted. LP tokens burned by
+	// holders leave it unchanged while reducing the LP mint supply.
+	LpAmount uint64
+}
+
+func (p *AmmV4Pool) FromBuffer(data []byte) error {
+	if len(data) < AmmV4PoolSize {
+		return fmt.Errorf("buffer too short")
+	}
+
+	u64 := func(offset int) uint64 {
+		return binary.LittleEndian.Uint64(data[offset : offset+8])
+	}
+
+	p.Status = u64(0)
+	p.Nonce = u64(8)
+	p.BaseDecimal = u64(32)
+	p.QuoteDecimal = u64(40)
+	p.SwapFeeNumerator = u64(176)
+	p.SwapFeeDenominator = u64(184)
+	p.BaseNeedTakePnl = u64(192)
+	p.QuoteNeedTakePnl = u64(200)
+	p.PoolOpenTime = u64(224)
+	p.BaseVault = solana.PublicKeyFromBytes(data[336:368])
+	p.QuoteVault = solana.PublicKeyFromBytes(data[368:400])
+	p.BaseMint = solana.PublicKeyFromBytes(data[400:432])
+	p.QuoteMint = solana.PublicKeyFromBytes(data[432:464])
+	p.LpMint = solana.PublicKeyFromBytes(data[464:496])
+	p.OpenOrders = solana.PublicKeyFromBytes(data[496:528])
+	p.MarketID = solana.PublicKeyFromBytes(data[528:560])
+	p.MarketProgramID = solana.PublicKeyFromBytes(data[560:592])
+	p.TargetOrders = solana.PublicKeyFromBytes(data[592:624])
+	p.LpAmount = u64(720)
+
+	return nil
+}
+
+// CpmmPool holds the fields of a Raydium CPMM pool state account
+type CpmmPool struct {
+	AmmConfig          solana.PublicKey
+	PoolCreator        solana.PublicKey
+	Token0Vault        solana.PublicKey
+	Token1Vault        solana.PublicKey
+	LpMint             solana.PublicKey
+	Token0Mint         solana.PublicKey
+	Token1Mint         solana.PublicKey
+	Token0Program      solana.PublicKey
+	Token1Program      solana.PublicKey
+	ObservationKey     solana.PublicKey
+	AuthBump           uint8
+	Status             uint8
+	LpMintDecimals     uint8
+	Mint0Decimals      uint8
+	Mint1Decimals      uint8
+	LpSupply           uint64
+	ProtocolFeesToken0 uint64
+	ProtocolFeesToken1 uint64
+	FundFeesToken0     uint64
+	FundFeesToken1     uint64
+	OpenTime           uint64
+}
+
+func (p *CpmmPool) FromBuffer(data []byte) error {
+	if len(data) < 389 {
+		return fmt.Errorf("buffer too short")
+	}
+	if !bytes.Equal(data[:8], CpmmPoolDiscriminator[:]) {
+		return fmt.Errorf("invalid pool state discriminator")
+	}
+
+	u64 := func(offset int) uint64 {
+		return binary.LittleEndian.Uint64(data[offset : offset+8])
+	}
+
+	p.AmmConfig = solana.PublicKeyFromBytes(data[8:40])
+	p.PoolCreator = solana.PublicKeyFromBytes(data[40:72])
+	p.Token0Vault = solana.PublicKeyFromBytes(data[72:104])
+	p.Token1Vault = solana.PublicKeyFromBytes(data[104:136])
+	p.LpMint = solana.PublicKeyFromBytes(data[136:168])
+	p.Token0Mint = solana.PublicKeyFromBytes(data[168:200])
+	p.Token1Mint = solana.PublicKeyFromBytes(data[200:232])
+	p.Token0Program = solana.PublicKeyFromBytes(data[232:264])
+	p.Token1Program = solana.PublicKeyFromBytes(data[264:296])
+	p.ObservationKey = solana.PublicKeyFromBytes(data[296:328])
+	p.AuthBump = data[328]
+	p.Status = data[329]
+	p.LpMintDecimals = data[330]
+	p.Mint0Decimals = data[331]
+	p.Mint1Decimals = data[332]
+	p.LpSupply = u64(333)
+	p.ProtocolFeesToken0 = u64(341)
+	p.ProtocolFeesToken1 = u64(349)
+	p.FundFeesToken0 = u64(357)
+	p.FundFeesToken1 = u64(365)
+	p.OpenTime = u64(373)
+
+	return nil
+}
+
+// CpmmAmmConfig holds the fee configuration shared by CPMM pools
+type CpmmAmmConfig struct {
+	Bump              uint8
+	DisableCreatePool bool
+	Index             uint16
+	TradeFeeRate      uint64
+	ProtocolFeeRate   uint64
+	FundFeeRate       uint64
+}
+
+func (c *CpmmAmmConfig) FromBuffer(data []byte) error {
+	if len(data) < 36 {
+		return fmt.Errorf("buffer too short")
+	}
+	if !bytes.Equal(data[:8], CpmmAmmConfigDiscriminator[:]) {
+		return fmt.Errorf("invalid amm config discriminator")
+	}
+
+	c.Bump = data[8]
+	c.DisableCreatePool = data[9] != 0
+	c.Index = binary.LittleEndian.Uint16(data[10:12])
+	c.TradeFeeRate = binary.LittleEndian.Uint64(data[12:20])
+	c.ProtocolFeeRate = binary.LittleEndian.Uint64(data[20:28])
+	c.FundFeeRate = binary.LittleEndian.Uint64(data[28:36])
+
+	return nil
+}
+
+// Pool is a protocol-agnostic view of a Raydium constant-product pool with
+// its current reserves. MintA/MintB are base/quote for AMM v4 and
+// token0/token1 for CPMM.
+type Pool struct {
+	Type    PoolType
+	Address solana.PublicKey
+
+	MintA          solana.PublicKey
+	MintB          solana.PublicKey
+	VaultA         solana.PublicKey
+	VaultB         solana.PublicKey
+	TokenProgramA  solana.PublicKey
+	TokenProgramB  solana.PublicKey
+	ReserveA       uint64
+	ReserveB       uint64
+	FeeNumerator   uint64
+	FeeDenominator uint64
+
+	AmmV4  *AmmV4Pool
+	Market *serum.MarketV2
+
+	Cpmm       *CpmmPool
+	CpmmConfig *CpmmAmmConfig
+}
+
+// Quote is the expected result of swapping an exact input amount through a pool
+type Quote struct {
+	Pool           solana.PublicKey
+	PoolType       PoolType
+	InputMint      solana.PublicKey
+	OutputMint     solana.PublicKey
+	AmountIn       uint64
+	AmountOut      uint64
+	MinAmountOut   uint64
+	Fee            uint64
+	PriceImpactPct float64
+}
+
+type SwapRequest struct {
+	Pool         *Pool
+	User         solana.PublicKey
+	Payer        solana.PublicKey // funds token account rent, defaults to User
+	InputMint    solana.PublicKey
+	AmountIn     uint64
+	MinAmountOut uint64
+}
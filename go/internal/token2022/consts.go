@@ -0,0 +1,118 @@
+package token2022
+
+import "github.com/gagliardetto/solana-go"
+
+var ProgramID = solana.Token2022ProgramID
+
+const (
+	// MintSize is the size of the base mint, shared with the legacy token program
+	MintSize = 82
+	// AccountSize is the size of a token account. Mints with extensions are
+	// padded to it so the account type byte never collides with an account.
+	AccountSize = 165
+
+	accountTypeOffset = AccountSize
+	tlvStartOffset    = AccountSize + 1
+	tlvHeaderSize     = 4
+)
+
+// AccountType tells mints and token accounts apart once extensions are present
+type AccountType uint8
+
+const (
+	AccountTypeUninitialized AccountType = 0
+	AccountTypeMint          AccountType = 1
+	AccountTypeAccount       AccountType = 2
+)
+
+// ExtensionType is the type of a TLV extension entry
+type ExtensionType uint16
+
+const (
+	ExtensionUninitialized                 ExtensionType = 0
+	ExtensionTransferFeeConfig             ExtensionType = 1
+	ExtensionTransferFeeAmount             ExtensionType = 2
+	ExtensionMintCloseAuthority            ExtensionType = 3
+	ExtensionConfidentialTransferMint      ExtensionType = 4
+	ExtensionConfidentialTransferAccount   ExtensionType = 5
+	ExtensionDefaultAccountState           ExtensionType = 6
+	ExtensionImmutableOwner                ExtensionType = 7
+	ExtensionMemoTransfer                  ExtensionType = 8
+	ExtensionNonTransferable               ExtensionType = 9
+	ExtensionInterestBearingConfig         ExtensionType = 10
+	ExtensionCpiGuard                      ExtensionType = 11
+	ExtensionPermanentDelegate             ExtensionType = 12
+	ExtensionNonTransferableAccount        ExtensionType = 13
+	ExtensionTransferHook                  ExtensionType = 14
+	ExtensionTransferHookAccount           ExtensionType = 15
+	ExtensionConfidentialTransferFeeConfig ExtensionType = 16
+	ExtensionConfidentialTransferFeeAmount ExtensionType = 17
+	ExtensionMetadataPointer               ExtensionType = 18
+	ExtensionTokenMetadata                 ExtensionType = 19
+	ExtensionGroupPointer                  ExtensionType = 20
+	ExtensionTokenGroup                    ExtensionType = 21
+	ExtensionGroupMemberPointer            ExtensionType = 22
+	ExtensionTokenGroupMember              ExtensionType = 23
+	ExtensionConfidentialMintBurn          ExtensionType = 24
+	ExtensionScaledUiAmount                ExtensionType = 25
+	ExtensionPausable                      ExtensionType = 26
+	ExtensionPausableAccount               ExtensionType = 27
+)
+
+var extensionNames = map[ExtensionType]string{
+	ExtensionUninitialized:                 "uninitialized",
+	ExtensionTransferFeeConfig:             "transfer_fee_config",
+	ExtensionTransferFeeAmount:             "transfer_fee_amount",
+	ExtensionMintCloseAuthority:            "mint_close_authority",
+	ExtensionConfidentialTransferMint:      "confidential_transfer_mint",
+	ExtensionConfidentialTransferAccount:   "confidential_transfer_account",
+	ExtensionDefaultAccountState:           "default_account_state",
+	ExtensionImmutableOwner:                "immutable_owner",
+	ExtensionMemoTransfer:                  "memo_transfer",
+	ExtensionNonTransferable:               "non_transferable",
+	ExtensionInterestBearingConfig:         "interest_bearing_config",
+	ExtensionCpiGuard:                      "cpi_guard",
+	ExtensionPermanentDelegate:             "permanent_delegate",
+	ExtensionNonTransferableAccount:        "non_transferable_account",
+	ExtensionTransferHook:                  "transfer_hook",
+	ExtensionTransferHookAccount:           "transfer_hook_account",
+	ExtensionConfidentialTransferFeeConfig: "confidential_transfer_fee_config",
+	ExtensionConfidentialTransferFeeAmount: "confidential_transfer_fee_amount",
+	ExtensionMetadataPointer:               "metadata_pointer",
+	ExtensionTokenMetadata:                 "token_metadata",
+	ExtensionGroupPointer:                  "group_pointer",
+	ExtensionTokenGroup:                    "token_group",
+	ExtensionGroupMemberPointer:            "group_member_pointer",
+	ExtensionTokenGroupMember:              "token_group_member",
+	ExtensionConfidentialMintBurn:          "confidential_mint_burn",
+	ExtensionScaledUiAmount:                "scaled_ui_amount",
+	ExtensionPausable:                      "pausable",
+	ExtensionPausableAccount:               "pausable_account",
+}
+
+func (e ExtensionType) String() string {
+	if name, ok := extensionNames[e]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// AccountState is the state new token accounts start in
+type AccountState uint8
+
+const (
+	AccountStateUninitialized AccountState = 0
+	AccountStateInitialized   AccountState = 1
+	AccountStateFrozen        AccountState = 2
+)
+
+func (s AccountState) String() string {
+	switch s {
+	case AccountStateInitialized:
+		return "initialized"
+	case AccountStateFrozen:
+		return "frozen"
+	default:
+		return "uninitialized"
+	}
+}
@@ -0,0 +1,302 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Extension is a raw TLV entry
+type Extension struct {
+	Type ExtensionType
+	Data []byte
+}
+
+// TransferFee is a fee schedule that applies from Epoch on
+type TransferFee struct {
+	Epoch       uint64
+	MaximumFee  uint64
+	BasisPoints uint16
+}
+
+// TransferFeeConfig charges a fee on every transfer, withheld in the
+// recipient's account until the withdraw authority collects it
+type TransferFeeConfig struct {
+	ConfigAuthority   *solana.PublicKey
+	WithdrawAuthority *solana.PublicKey
+	WithheldAmount    uint64
+	OlderTransferFee  TransferFee
+	NewerTransferFee  TransferFee
+}
+
+// FeeAt returns the transfer fee schedule in effect at an epoch
+func (c *TransferFeeConfig) FeeAt(epoch uint64) TransferFee {
+	if epoch >= c.NewerTransferFee.Epoch {
+		return c.NewerTransferFee
+	}
+	return c.OlderTransferFee
+}
+
+// InterestBearingConfig accrues interest on UI amounts. Rates are in basis points.
+type InterestBearingConfig struct {
+	RateAuthority           *solana.PublicKey
+	InitializationTimestamp int64
+	PreUpdateAverageRate    int16
+	LastUpdateTimestamp     int64
+	CurrentRate             int16
+}
+
+// TransferHook makes every transfer call ProgramID
+type TransferHook struct {
+	Authority *solana.PublicKey
+	ProgramID *solana.PublicKey
+}
+
+// MetadataPointer names the account holding the mint's metadata
+type MetadataPointer struct {
+	Authority       *solana.PublicKey
+	MetadataAddress *solana.PublicKey
+}
+
+// TokenMetadata is metadata stored in the mint itself
+type TokenMetadata struct {
+	UpdateAuthority    *solana.PublicKey
+	Mint               solana.PublicKey
+	Name               string
+	Symbol             string
+	Uri                string
+	AdditionalMetadata [][2]string
+}
+
+// MintExtensions are the decoded extensions of a Token-2022 mint. Types
+// lists every extension present, including ones without a decoded field.
+type MintExtensions struct {
+	Types []ExtensionType
+
+	TransferFeeConfig     *TransferFeeConfig
+	MintCloseAuthority    *solana.PublicKey
+	DefaultAccountState   *AccountState
+	NonTransferable       bool
+	InterestBearingConfig *InterestBearingConfig
+	PermanentDelegate     *solana.PublicKey
+	TransferHook          *TransferHook
+	MetadataPointer       *MetadataPointer
+	TokenMetadata         *TokenMetadata
+}
+
+// ParseExtensions splits the TLV entries that follow the base mint or account
+func ParseExtensions(data []byte) (AccountType, []Extension, error) {
+	if len(data) <= AccountSize {
+		return AccountTypeUninitialized, nil, nil
+	}
+
+	accountType := AccountType(data[accountTypeOffset])
+
+	var extensions []Extension
+	offset := tlvStartOffset
+	for offset+tlvHeaderSize <= len(data) {
+		extensionType := ExtensionType(binary.LittleEndian.Uint16(data[offset : offset+2]))
+		length := int(binary.LittleEndian.Uint16(data[offset+2 : offset+4]))
+		offset += tlvHeaderSize
+
+		// Trailing zeroes are space reserved for extensions not written yet
+		if extensionType == ExtensionUninitialized {
+			break
+		}
+		if offset+length > len(data) {
+			return accountType, nil, fmt.Errorf("extension %s overruns account data", extensionType)
+		}
+
+		extensions = append(extensions, Extension{Type: extensionType, Data: data[offset : offset+length]})
+		offset += length
+	}
+
+	return accountType, extensions, nil
+}
+
+// DecodeMintExtensions decodes the extensions of a Token-2022 mint account
+func DecodeMintExtensions(data []byte) (*MintExtensions, error) {
+	accountType, extensions, err := ParseExtensions(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(extensions) > 0 && accountType != AccountTypeMint {
+		return nil, fmt.Errorf("account is not a mint")
+	}
+
+	out := &MintExtensions{Types: make([]ExtensionType, 0, len(extensions))}
+	for _, extension := range extensions {
+		out.Types = append(out.Types, extension.Type)
+
+		if err := out.decode(extension); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", extension.Type, err)
+		}
+	}
+
+	return out, nil
+}
+
+// Has reports whether the mint has an extension
+func (m *MintExtensions) Has(extensionType ExtensionType) bool {
+	for _, t := range m.Types {
+		if t == extensionType {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MintExtensions) decode(extension Extension) error {
+	data := extension.Data
+
+	switch extension.Type {
+	case ExtensionTransferFeeConfig:
+		if len(data) < 108 {
+			return fmt.Errorf("buffer too short")
+		}
+		m.TransferFeeConfig = &TransferFeeConfig{
+			ConfigAuthority:   optionalPublicKey(data[0:32]),
+			WithdrawAuthority: optionalPublicKey(data[32:64]),
+			WithheldAmount:    binary.LittleEndian.Uint64(data[64:72]),
+			OlderTransferFee:  decodeTransferFee(data[72:90]),
+			NewerTransferFee:  decodeTransferFee(data[90:108]),
+		}
+
+	case ExtensionMintCloseAuthority:
+		if len(data) < 32 {
+			return fmt.Errorf("buffer too short")
+		}
+		m.MintCloseAuthority = optionalPublicKey(data[0:32])
+
+	case ExtensionDefaultAccountState:
+		if len(data) < 1 {
+			return fmt.Errorf("buffer too short")
+		}
+		state := AccountState(data[0])
+		m.DefaultAccountState = &state
+
+	case ExtensionNonTransferable:
+		m.NonTransferable = true
+
+	case ExtensionInterestBearingConfig:
+		if len(data) < 52 {
+			return fmt.Errorf("buffer too short")
+		}
+		m.InterestBearingConfig = &InterestBearingConfig{
+			RateAuthority:           optionalPublicKey(data[0:32]),
+			InitializationTimestamp: int64(binary.LittleEndian.Uint64(data[32:40])),
+			PreUpdateAverageRate:    int16(binary.LittleEndian.Uint16(data[40:42])),
+			LastUpdateTimestamp:     int64(binary.LittleEndian.Uint64(data[42:50])),
+			CurrentRate:             int16(binary.LittleEndian.Uint16(data[50:52])),
+		}
+
+	case ExtensionPermanentDelegate:
+		if len(data) < 32 {
+			return fmt.Errorf("buffer too short")
+		}
+		m.PermanentDelegate = optionalPublicKey(data[0:32])
+
+	case ExtensionTransferHook:
+		if len(data) < 64 {
+			return fmt.Errorf("buffer too short")
+		}
+		m.TransferHook = &TransferHook{
+			Authority: optionalPublicKey(data[0:32]),
+			ProgramID: optionalPublicKey(data[32:64]),
+		}
+
+	case ExtensionMetadataPointer:
+		if len(data) < 64 {
+			return fmt.Errorf("buffer too short")
+		}
+		m.MetadataPointer = &MetadataPointer{
+			Authority:       optionalPublicKey(data[0:32]),
+			MetadataAddress: optionalPublicKey(data[32:64]),
+		}
+
+	case ExtensionTokenMetadata:
+		metadata, err := decodeTokenMetadata(data)
+		if err != nil {
+			return err
+		}
+		m.TokenMetadata = metadata
+	}
+
+	return nil
+}
+
+func decodeTransferFee(data []byte) TransferFee {
+	return TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(data[0:8]),
+		MaximumFee:  binary.LittleEndian.Uint64(data[8:16]),
+		BasisPoints: binary.LittleEndian.Uint16(data[16:18]),
+	}
+}
+
+// decodeTokenMetadata decodes the borsh encoded token metadata interface state
+func decodeTokenMetadata(data []byte) (*TokenMetadata, error) {
+	if len(data) < 64 {
+		return nil, fmt.Errorf("buffer too short")
+	}
+
+	metadata := &TokenMetadata{
+		UpdateAuthority: optionalPublicKey(data[0:32]),
+		Mint:            solana.PublicKeyFromBytes(data[32:64]),
+	}
+	offset := 64
+
+	readString := func() (string, error) {
+		if offset+4 > len(data) {
+			return "", fmt.Errorf("buffer too short")
+		}
+		length := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+		offset += 4
+		if offset+length > len(data) {
+			return "", fmt.Errorf("buffer too short")
+		}
+		value := string(data[offset : offset+length])
+		offset += length
+		return value, nil
+	}
+
+	var err error
+	if metadata.Name, err = readString(); err != nil {
+		return nil, err
+	}
+	if metadata.Symbol, err = readString(); err != nil {
+		return nil, err
+	}
+	if metadata.Uri, err = readString(); err != nil {
+		return nil, err
+	}
+
+	if offset+4 > len(data) {
+		return nil, fmt.Errorf("buffer too short")
+	}
+	count := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+	offset += 4
+
+	for i := 0; i < count; i++ {
+		key, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		value, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		metadata.AdditionalMetadata = append(metadata.AdditionalMetadata, [2]string{key, value})
+	}
+
+	return metadata, nil
+}
+
+// optionalPublicKey decodes an OptionalNonZeroPubkey, where all zeroes means none
+func optionalPublicKey(data []byte) *solana.PublicKey {
+	key := solana.PublicKeyFromBytes(data)
+	if key.IsZero() {
+		return nil
+	}
+	return &key
+}
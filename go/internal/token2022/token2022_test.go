@@ -0,0 +1,146 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// mintWithExtensions lays out a mint account followed by TLV entries
+func mintWithExtensions(extensions ...Extension) []byte {
+	data := make([]byte, AccountSize+1)
+	data[45] = 1 // initialized
+	data[accountTypeOffset] = byte(AccountTypeMint)
+
+	for _, extension := range extensions {
+		header := make([]byte, tlvHeaderSize)
+		binary.LittleEndian.PutUint16(header[0:2], uint16(extension.Type))
+		binary.LittleEndian.PutUint16(header[2:4], uint16(len(extension.Data)))
+		data = append(data, header...)
+		data = append(data, extension.Data...)
+	}
+
+	// Reserved space for a future extension
+	return append(data, make([]byte, 8)...)
+}
+
+func borshString(s string) []byte {
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(s)))
+	return append(out, s...)
+}
+
+func TestDecodeMintExtensions(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	delegate := solana.NewWallet().PublicKey()
+	hookProgram := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+
+	transferFee := make([]byte, 108)
+	copy(transferFee[0:32], authority[:])
+	binary.LittleEndian.PutUint64(transferFee[72:80], 100)   // older epoch
+	binary.LittleEndian.PutUint64(transferFee[80:88], 5_000) // older maximum fee
+	binary.LittleEndian.PutUint16(transferFee[88:90], 50)    // older bps
+	binary.LittleEndian.PutUint64(transferFee[90:98], 200)
+	binary.LittleEndian.PutUint64(transferFee[98:106], 1_000_000)
+	binary.LittleEndian.PutUint16(transferFee[106:108], 1_000)
+
+	hook := make([]byte, 64)
+	copy(hook[32:64], hookProgram[:])
+
+	rate := int16(-25)
+	interest := make([]byte, 52)
+	binary.LittleEndian.PutUint16(interest[50:52], uint16(rate))
+
+	metadata := append(append([]byte{}, authority[:]...), mint[:]...)
+	metadata = append(metadata, borshString("Token")...)
+	metadata = append(metadata, borshString("TKN")...)
+	metadata = append(metadata, borshString("https://example.com/token.json")...)
+	metadata = binary.LittleEndian.AppendUint32(metadata, 1)
+	metadata = append(metadata, borshString("website")...)
+	metadata = append(metadata, borshString("https://example.com")...)
+
+	pointer := make([]byte, 64)
+	copy(pointer[32:64], mint[:])
+
+	data := mintWithExtensions(
+		Extension{Type: ExtensionTransferFeeConfig, Data: transferFee},
+		Extension{Type: ExtensionPermanentDelegate, Data: delegate[:]},
+		Extension{Type: ExtensionTransferHook, Data: hook},
+		Extension{Type: ExtensionNonTransferable, Data: nil},
+		Extension{Type: ExtensionInterestBearingConfig, Data: interest},
+		Extension{Type: ExtensionDefaultAccountState, Data: []byte{byte(AccountStateFrozen)}},
+		Extension{Type: ExtensionMetadataPointer, Data: pointer},
+		Extension{Type: ExtensionTokenMetadata, Data: metadata},
+		Extension{Type: ExtensionPausable, Data: make([]byte, 33)},
+	)
+
+	extensions, err := DecodeMintExtensions(data)
+	if err != nil {
+		t.Fatalf("failed to decode extensions: %v", err)
+	}
+
+	if len(extensions.Types) != 9 || !extensions.Has(ExtensionPausable) {
+		t.Errorf("types = %v, want all 9 including pausable", extensions.Types)
+	}
+
+	fee := extensions.TransferFeeConfig
+	if fee == nil || fee.ConfigAuthority == nil || !fee.ConfigAuthority.Equals(authority) || fee.WithdrawAuthority != nil {
+		t.Fatalf("transfer fee config = %+v", fee)
+	}
+	if got := fee.FeeAt(150); got.BasisPoints != 50 || got.MaximumFee != 5_000 {
+		t.Errorf("fee at epoch 150 = %+v, want the older fee", got)
+	}
+	if got := fee.FeeAt(200); got.BasisPoints != 1_000 || got.MaximumFee != 1_000_000 {
+		t.Errorf("fee at epoch 200 = %+v, want the newer fee", got)
+	}
+
+	if extensions.PermanentDelegate == nil || !extensions.PermanentDelegate.Equals(delegate) {
+		t.Errorf("permanent delegate = %v, want %s", extensions.PermanentDelegate, delegate)
+	}
+	if extensions.TransferHook == nil || extensions.TransferHook.Authority != nil || !extensions.TransferHook.ProgramID.Equals(hookProgram) {
+		t.Errorf("transfer hook = %+v", extensions.TransferHook)
+	}
+	if !extensions.NonTransferable {
+		t.Error("non-transferable not detected")
+	}
+	if extensions.InterestBearingConfig == nil || extensions.InterestBearingConfig.CurrentRate != -25 {
+		t.Errorf("interest bearing config = %+v, want rate -25", extensions.InterestBearingConfig)
+	}
+	if extensions.DefaultAccountState == nil || *extensions.DefaultAccountState != AccountStateFrozen {
+		t.Errorf("default account state = %v, want frozen", extensions.DefaultAccountState)
+	}
+	if extensions.MetadataPointer == nil || !extensions.MetadataPointer.MetadataAddress.Equals(mint) {
+		t.Errorf("metadata pointer = %+v", extensions.MetadataPointer)
+	}
+
+	md := extensions.TokenMetadata
+	if md == nil || md.Name != "Token" || md.Symbol != "TKN" || md.Uri != "https://example.com/token.json" || !md.Mint.Equals(mint) {
+		t.Fatalf("token metadata = %+v", md)
+	}
+	if len(md.AdditionalMetadata) != 1 || md.AdditionalMetadata[0] != [2]string{"website", "https://example.com"} {
+		t.Errorf("additional metadata = %v", md.AdditionalMetadata)
+	}
+}
+
+func TestDecodeMintExtensionsWithoutExtensions(t *testing.T) {
+	extensions, err := DecodeMintExtensions(make([]byte, MintSize))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(extensions.Types) != 0 {
+		t.Errorf("types = %v, want none", extensions.Types)
+	}
+}
+
+func TestDecodeMintExtensionsOverrun(t *testing.T) {
+	data := mintWithExtensions()
+	data = data[:tlvStartOffset]
+	data = binary.LittleEndian.AppendUint16(data, uint16(ExtensionPermanentDelegate))
+	data = binary.LittleEndian.AppendUint16(data, 32)
+	data = append(data, make([]byte, 16)...)
+
+	if _, err := DecodeMintExtensions(data); err == nil {
+		t.Error("expected an error for an extension past the end of the data")
+	}
+}
@@ -30,7 +30,6 @@ var (
 // Event Discriminators
 var (
 	JUPITER_ROUTE_EVENT_DISCRIMINATOR = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 64, 198, 205, 232, 38, 8, 113, 226}
-	PUMPFUN_TRADE_EVENT_DISCRIMINATOR = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 219, 127, 211, 78, 230, 97, 238}
 	JUPITER_DCA_EVENT_DISCRIMINATOR   = [16]byte{
 		0xe4, 0x45, 0xa5, 0x2e, 0x51, 0xcb, 0x9a, 0x1d,
 		0xa6, 0xac, 0x61, 0x09, 0x4d, 0x4c, 0xbd, 0x6d,
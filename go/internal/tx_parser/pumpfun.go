@@ -1,13 +1,12 @@
 package tx_parser
 
 import (
-	"bytes"
 	"fmt"
 	"time"
 
-	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 	"github.com/mr-tron/base58"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
 )
 
 // PumpFunParser handles parsing PumpFun protocol swaps
@@ -19,16 +18,7 @@ func NewPumpFunParser() *PumpFunParser {
 }
 
 // PumpFunTradeEvent represents a single trade event in the PumpFun protocol
-type PumpFunTradeEvent struct {
-	Mint                 solana.PublicKey
-	SolAmount            uint64
-	TokenAmount          uint64
-	IsBuy                bool
-	User                 solana.PublicKey
-	Timestamp            int64
-	VirtualSolReserves   uint64
-	VirtualTokenReserves uint64
-}
+type PumpFunTradeEvent = pump.TradeEvent
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *PumpFunParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
@@ -72,19 +62,17 @@ func (p *PumpFunParser) parsePumpFunEvent(instruction solana.CompiledInstruction
 		return nil, fmt.Errorf("failed to decode instruction data: %w", err)
 	}
 
-	// Check event discriminator
-	if len(decodedBytes) < 16 || !bytes.Equal(decodedBytes[:16], PUMPFUN_TRADE_EVENT_DISCRIMINATOR[:]) {
-		return nil, fmt.Errorf("invalid PumpFun event discriminator")
+	event, err := pump.DecodeEvent(decodedBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PumpFun event: %w", err)
 	}
 
-	// Decode event data
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[16:])
-	var event PumpFunTradeEvent
-	if err := decoder.Decode(&event); err != nil {
-		return nil, fmt.Errorf("failed to decode PumpFun event: %w", err)
+	trade, ok := event.Impl.(*PumpFunTradeEvent)
+	if !ok {
+		return nil, fmt.Errorf("not a PumpFun trade event: %s", event.Name())
 	}
 
-	return &event, nil
+	return trade, nil
 }
 
 // buildSwapInfo creates the final SwapInfo from the PumpFun event
@@ -0,0 +1,137 @@
+package onchain_actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/audit_log"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
+)
+
+type Option func(*OnchainActionsTool)
+
+// WithAuditStore records every executed action to the store
+func WithAuditStore(store audit_log.Store) Option {
+	return func(t *OnchainActionsTool) {
+		t.auditStore = store
+	}
+}
+
+// WithPriceOracle values quotes with oracle instead of the default one
+func WithPriceOracle(oracle *price_oracle.Oracle) Option {
+	return func(t *OnchainActionsTool) {
+		t.priceOracle = oracle
+	}
+}
+
+type auditRecordKey struct{}
+
+// withAuditRecord attaches the record being built to the context so the
+// send path can fill in the transaction, simulation and status
+func withAuditRecord(ctx context.Context, record *audit_log.Record) context.Context {
+	return context.WithValue(ctx, auditRecordKey{}, record)
+}
+
+func auditRecordFromContext(ctx context.Context) *audit_log.Record {
+	record, _ := ctx.Value(auditRecordKey{}).(*audit_log.Record)
+	return record
+}
+
+// recordTransaction stores the signed transaction and its simulation on the
+// context's audit record, if any. Simulation failures don't stop the send.
+func (t *OnchainActionsTool) recordTransaction(ctx context.Context, tx *solana.Transaction) {
+	record := auditRecordFromContext(ctx)
+	if record == nil {
+		return
+	}
+
+	record.Transaction = tx.MustToBase64()
+
+	simulation, err := t.rpcClient.SimulateTransactionWithOpts(ctx, tx, &rpc.SimulateTransactionOpts{
+		Commitment: rpc.CommitmentProcessed,
+	})
+	if err != nil {
+		record.Simulation = &audit_log.Simulation{Err: err.Error()}
+		return
+	}
+
+	record.Simulation = &audit_log.Simulation{
+		Logs:          simulation.Value.Logs,
+		UnitsConsumed: simulation.Value.UnitsConsumed,
+	}
+	if simulation.Value.Err != nil {
+		record.Simulation.Err = fmt.Sprint(simulation.Value.Err)
+	}
+}
+
+// finishAudit completes the record from the action result and appends it to the store
+func (t *OnchainActionsTool) finishAudit(ctx context.Context, record *audit_log.Record, result json.RawMessage, actionErr error) error {
+	var output OnchainActionsOutput
+	if result != nil && json.Unmarshal(result, &output) == nil {
+		if record.Signature == "" {
+			record.Signature = output.Signature
+		}
+		if output.MintAddress != nil {
+			record.Mint = *output.MintAddress
+		}
+	}
+
+	if actionErr != nil {
+		record.Error = actionErr.Error()
+	} else if record.Signature != "" {
+		// Actions only return without error once their transaction confirmed
+		record.Status = audit_log.StatusConfirmed
+	}
+
+	if record.Status == audit_log.StatusConfirmed {
+		record.Fill = t.parseFill(ctx, record.Signature)
+	}
+
+	if err := t.auditStore.Append(ctx, *record); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// parseFill returns the swaps executed by a confirmed transaction
+func (t *OnchainActionsTool) parseFill(ctx context.Context, signature string) []audit_log.Fill {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return nil
+	}
+
+	maxSupportedTxVersion := uint64(0)
+	tx, err := t.rpcClient.GetTransaction(ctx, sig, &rpc.GetTransactionOpts{
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxSupportedTxVersion,
+	})
+	if err != nil {
+		return nil
+	}
+
+	parser, err := tx_parser.New(tx)
+	if err != nil {
+		return nil
+	}
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		return nil
+	}
+
+	fills := make([]audit_log.Fill, len(swaps))
+	for i, swap := range swaps {
+		fills[i] = audit_log.Fill{
+			Protocol:   string(swap.Protocol),
+			InputMint:  swap.TokenIn.Mint.String(),
+			InAmount:   swap.TokenIn.Amount,
+			OutputMint: swap.TokenOut.Mint.String(),
+			OutAmount:  swap.TokenOut.Amount,
+		}
+	}
+	return fills
+}
@@ -5,3 +5,6 @@ import "github.com/gagliardetto/solana-go"
 var (
 	WSOL_MINT = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
 )
+
+// Compute units requested for a native Raydium swap, including SOL wrapping
+const raydiumSwapComputeUnits = 300_000
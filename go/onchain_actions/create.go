@@ -14,21 +14,79 @@ type CreateTokenParams struct {
 	UserPrivateKey  solana.PrivateKey
 	BuyAmount       float64
 	SlippagePercent float64
+	// FeePayer optionally pays the transaction fees instead of the user
+	FeePayer *solana.PrivateKey
 }
 
+// CreateToken creates a pump.fun token, with an optional initial buy, and
+// sends it like every other action so it is audited
 func (o *OnchainActionsTool) CreateToken(ctx context.Context, params CreateTokenParams) (*solana.Signature, error) {
-	sig, err := pumpfun.CreateToken(ctx, pumpfun.CreateTokenRequest{
+	feePayer := params.UserPrivateKey
+	if params.FeePayer != nil {
+		feePayer = *params.FeePayer
+	}
+
+	tx, err := pumpfun.BuildCreateTokenTransaction(ctx, pumpfun.CreateTokenTransactionRequest{
 		RpcClient:       o.rpcClient,
 		TokenInfo:       params.TokenInfo,
-		Mint:            params.Mint,
-		UserPrivateKey:  params.UserPrivateKey,
+		Mint:            params.Mint.PublicKey(),
+		User:            params.UserPrivateKey.PublicKey(),
+		FeePayer:        feePayer.PublicKey(),
 		BuyAmount:       params.BuyAmount,
 		SlippagePercent: params.SlippagePercent,
+		GlobalCache:     o.globalCache,
 	})
-
 	if err != nil {
 		return nil, fmt.Errorf("failed to create token: %w", err)
 	}
 
+	if err := signTransaction(tx, params.UserPrivateKey, params.Mint.PrivateKey, feePayer); err != nil {
+		return nil, err
+	}
+
+	sig, err := o.sendTransacton(ctx, tx)
+	if err != nil {
+		return sig, fmt.Errorf("failed to create token: %w", err)
+	}
+
 	return sig, nil
 }
+
+type CreateTokenTransactionParams struct {
+	TokenInfo       pumpfun.CreateTokenInformation
+	Mint            *solana.Wallet
+	User            solana.PublicKey
+	BuyAmount       float64
+	SlippagePercent float64
+	// FeePayer optionally pays the transaction fees instead of the user
+	FeePayer solana.PublicKey
+}
+
+// BuildCreateTokenTransaction returns the create transaction signed only by
+// the new mint. It still needs the user's and fee payer's signatures.
+func (o *OnchainActionsTool) BuildCreateTokenTransaction(ctx context.Context, params CreateTokenTransactionParams) (*solana.Transaction, error) {
+	tx, err := pumpfun.BuildCreateTokenTransaction(ctx, pumpfun.CreateTokenTransactionRequest{
+		RpcClient:       o.rpcClient,
+		TokenInfo:       params.TokenInfo,
+		Mint:            params.Mint.PublicKey(),
+		User:            params.User,
+		FeePayer:        params.FeePayer,
+		BuyAmount:       params.BuyAmount,
+		SlippagePercent: params.SlippagePercent,
+		GlobalCache:     o.globalCache,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build create token transaction: %w", err)
+	}
+
+	if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(params.Mint.PublicKey()) {
+			return &params.Mint.PrivateKey
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction with mint: %w", err)
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,88 @@
+package onchain_actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+)
+
+type ClaimCreatorFeesParams struct {
+	Creator solana.PrivateKey
+	// FeePayer optionally pays the transaction fees instead of the creator
+	FeePayer *solana.PrivateKey
+}
+
+// creatorFeesNote is returned with every creator fee report
+const creatorFeesNote = "Only bonding curve creator fees are reported and claimed. Fees earned in PumpSwap pools after a token migrates accrue in a separate vault and are not included. Tokens minted with Token-2022 are not listed."
+
+// GetCreatorFees reports the pump.fun creator fees claimable by creator and
+// the tokens it created. Fees of all tokens accrue in a single vault, so the
+// claimable amount is not split per token. Fees of migrated tokens in their
+// PumpSwap pools are not included.
+func (o *OnchainActionsTool) GetCreatorFees(ctx context.Context, creator solana.PublicKey) (*CreatorFeesOutput, error) {
+	fees, err := pumpfun.GetCreatorFees(ctx, o.rpcClient, creator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get creator fees: %w", err)
+	}
+
+	tokens, err := pumpfun.GetCreatedTokens(ctx, o.rpcClient, creator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created tokens: %w", err)
+	}
+
+	output := &CreatorFeesOutput{
+		Creator:           creator.String(),
+		Vault:             fees.Vault.String(),
+		ClaimableLamports: fees.Claimable,
+		ClaimableSol:      float64(fees.Claimable) / float64(solana.LAMPORTS_PER_SOL),
+		Tokens:            make([]CreatedToken, 0, len(tokens)),
+		Note:              creatorFeesNote,
+	}
+	for _, token := range tokens {
+		output.Tokens = append(output.Tokens, CreatedToken{
+			Mint:         token.Mint.String(),
+			BondingCurve: token.BondingCurve.String(),
+			Complete:     token.Complete,
+		})
+	}
+
+	return output, nil
+}
+
+// ClaimCreatorFees moves the claimable pump.fun bonding curve creator fees to
+// the creator wallet. PumpSwap creator fees are not claimed.
+func (o *OnchainActionsTool) ClaimCreatorFees(ctx context.Context, params ClaimCreatorFeesParams) (*solana.Signature, error) {
+	feePayer := params.Creator
+	if params.FeePayer != nil {
+		feePayer = *params.FeePayer
+	}
+
+	tx, err := o.BuildClaimCreatorFeesTransaction(ctx, params.Creator.PublicKey(), feePayer.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := signTransaction(tx, params.Creator, feePayer); err != nil {
+		return nil, err
+	}
+
+	sig, err := o.sendTransacton(ctx, tx)
+	if err != nil {
+		return sig, fmt.Errorf("failed to claim creator fees: %w", err)
+	}
+
+	return sig, nil
+}
+
+// BuildClaimCreatorFeesTransaction returns the unsigned claim transaction,
+// to be signed by the creator and the fee payer
+func (o *OnchainActionsTool) BuildClaimCreatorFeesTransaction(ctx context.Context, creator, feePayer solana.PublicKey) (*solana.Transaction, error) {
+	tx, err := pumpfun.BuildCollectCreatorFeeTransaction(ctx, o.rpcClient, creator, feePayer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build claim creator fees transaction: %w", err)
+	}
+
+	return tx, nil
+}
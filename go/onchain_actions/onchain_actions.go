@@ -10,7 +10,9 @@ import (
 	"github.com/ilkamo/jupiter-go/jupiter"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/audit_log"
 	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
 	toolkit "github.com/soralabs/toolkit/go"
 )
 
@@ -22,18 +24,32 @@ type OnchainActionsTool struct {
 	rpcClient *rpc.Client
 
 	jupClient *jupiter.ClientWithResponses
+
+	auditStore audit_log.Store
+
+	priceOracle *price_oracle.Oracle
+
+	globalCache *pumpfun.GlobalAccountCache
 }
 
-func NewOnchainActionsTool(rpcClient *rpc.Client) (*OnchainActionsTool, error) {
+func NewOnchainActionsTool(rpcClient *rpc.Client, opts ...Option) (*OnchainActionsTool, error) {
 	jupClient, err := jupiter.NewClientWithResponses(jupiter.DefaultAPIURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Jupiter client: %w", err)
 	}
 
-	return &OnchainActionsTool{
-		rpcClient: rpcClient,
-		jupClient: jupClient,
-	}, nil
+	t := &OnchainActionsTool{
+		rpcClient:   rpcClient,
+		jupClient:   jupClient,
+		priceOracle: price_oracle.NewDefault(rpcClient),
+		globalCache: pumpfun.NewGlobalAccountCache(rpcClient),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
 }
 
 func (t *OnchainActionsTool) GetName() string {
@@ -41,7 +57,7 @@ func (t *OnchainActionsTool) GetName() string {
 }
 
 func (t *OnchainActionsTool) GetDescription() string {
-	return "Perform solana onchain actions: buy, sell, create, transfer."
+	return "Perform solana onchain actions: buy, sell, create, transfer, claim pump.fun creator fees. In unsigned mode transactions are returned for an external wallet to sign and sent back with submit."
 }
 
 func (t *OnchainActionsTool) GetSchema() toolkit.Schema {
@@ -52,18 +68,43 @@ func (t *OnchainActionsTool) GetSchema() toolkit.Schema {
             "properties": {
                 "action": {
                     "type": "string",
-                    "description": "The type of onchain action to perform",
+                    "description": "The type of onchain action to perform. quote estimates a buy or sell without a private key. creator_fees reports the pump.fun bonding curve creator fees claimable by params.owner and the SPL Token mints it created, without a private key. claim_creator_fees claims them to the creator wallet. Creator fees earned in PumpSwap pools after migration are neither reported nor claimed",
                     "enum": [
                         "buy",
                         "sell",
                         "create",
-						"transfer"
+                        "transfer",
+                        "quote",
+                        "submit",
+                        "creator_fees",
+                        "claim_creator_fees"
                     ]
                 },
+                "mode": {
+                    "type": "string",
+                    "description": "signed (default) signs and sends with the source key. unsigned returns the base64 transaction for params.owner with its required signers and a summary, without any private key",
+                    "enum": ["signed", "unsigned"]
+                },
                 "params": {
                     "type": "object",
                     "description": "Parameters specific to the action being performed",
                     "properties": {
+                        "fee_payer": {
+                            "type": "string",
+                            "description": "Optional private key of a wallet that pays the transaction fees instead of the source wallet"
+                        },
+                        "owner": {
+                            "type": "string",
+                            "description": "Public key of the wallet the transaction is built for, in unsigned mode, or the creator for creator_fees"
+                        },
+                        "fee_payer_address": {
+                            "type": "string",
+                            "description": "Optional public key of a wallet that pays the fees instead of the owner, in unsigned mode"
+                        },
+                        "signed_transaction": {
+                            "type": "string",
+                            "description": "Base64 transaction signed by an external wallet, when the action is submit"
+                        },
                         "destination": {
                             "type": "string",
                             "description": "Destination wallet or account address for transfers"
@@ -74,7 +115,16 @@ func (t *OnchainActionsTool) GetSchema() toolkit.Schema {
                         },
                         "amount": {
                             "type": "number",
-                            "description": "Amount of tokens/SOL (not in lamports) to transfer or interact with"
+                            "description": "Amount in whole tokens/SOL, scaled by the mint's decimals (not raw base units or lamports). For buy and quote with side buy this is SOL spent, for sell and quote with side sell this is tokens sold"
+                        },
+                        "side": {
+                            "type": "string",
+                            "description": "Direction of a quote: buy spends SOL for the token, sell spends the token for SOL",
+                            "enum": ["buy", "sell"]
+                        },
+                        "slippage_bps": {
+                            "type": "integer",
+                            "description": "Optional slippage tolerance in basis points for buy, sell and quote"
                         },
                         "token_name": {
                             "type": "string",
@@ -83,6 +133,17 @@ func (t *OnchainActionsTool) GetSchema() toolkit.Schema {
                         "token_symbol": {
                             "type": "string",
                             "description": "Symbol/ticker of the token to be created, when the action is create"
+                        },
+                        "memo": {
+                            "type": "string",
+                            "description": "Optional memo to attach to a transfer"
+                        },
+                        "references": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            },
+                            "description": "Optional reference public keys to attach to a transfer as read-only accounts"
                         }
                     }
                 }
@@ -100,7 +161,73 @@ func (t *OnchainActionsTool) Execute(ctx context.Context, params json.RawMessage
 		return nil, fmt.Errorf("failed to parse parameters: %w", err)
 	}
 
-	// Validate private key for all actions
+	// Reads and unsigned transactions never reach the chain and are not audited
+	if t.auditStore == nil || input.Action == ActionQuote || input.Action == ActionCreatorFees || input.Mode == ModeUnsigned {
+		return t.execute(ctx, input)
+	}
+
+	record, err := audit_log.NewRecord(string(input.Action), params)
+	if err != nil {
+		return nil, err
+	}
+	record.Mint = input.Params.TokenMint
+
+	result, err := t.execute(withAuditRecord(ctx, &record), input)
+	if auditErr := t.finishAudit(ctx, &record, result, err); auditErr != nil && err == nil {
+		return result, auditErr
+	}
+	return result, err
+}
+
+func (t *OnchainActionsTool) execute(ctx context.Context, input OnchainActionsInput) (json.RawMessage, error) {
+	// Quotes never need a private key
+	if input.Action == ActionQuote {
+		quoteParams, err := t.BuildQuoteParams(ctx, input.Params.Side, input.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		quote, err := t.Quote(ctx, quoteParams)
+		if err != nil {
+			return nil, err
+		}
+		t.valueQuote(ctx, quote)
+
+		return json.Marshal(quote)
+	}
+
+	// Creator fee reports only need the creator's address
+	if input.Action == ActionCreatorFees {
+		creator, err := creatorAddress(input.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		fees, err := t.GetCreatorFees(ctx, creator)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(fees)
+	}
+
+	// Submitted transactions are already signed by an external wallet
+	if input.Action == ActionSubmit {
+		sig, err := t.SubmitTransaction(ctx, input.Params.SignedTransaction)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(OnchainActionsOutput{
+			Signature: sig.String(),
+		})
+	}
+
+	if input.Mode == ModeUnsigned {
+		return t.executeUnsigned(ctx, input)
+	}
+
+	// Validate private key for all other actions
 	if input.Params.Source == "" {
 		return nil, fmt.Errorf("source/private key is required")
 	}
@@ -111,33 +238,38 @@ func (t *OnchainActionsTool) Execute(ctx context.Context, params json.RawMessage
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
-	var result interface{}
-
-	switch input.Action {
-	case ActionTransfer:
-		if input.Params.Destination == "" || input.Params.TokenMint == "" || input.Params.Amount <= 0 {
-			return nil, fmt.Errorf("invalid transfer parameters")
-		}
+	if record := auditRecordFromContext(ctx); record != nil {
+		record.Signer = privateKey.PublicKey().String()
+	}
 
-		destPubKey, err := solana.PublicKeyFromBase58(input.Params.Destination)
+	// Optional sponsor paying fees instead of the source wallet
+	var feePayer *solana.PrivateKey
+	if input.Params.FeePayer != "" {
+		key, err := solana.PrivateKeyFromBase58(input.Params.FeePayer)
 		if err != nil {
-			return nil, fmt.Errorf("invalid destination address: %w", err)
+			return nil, fmt.Errorf("failed to parse fee payer private key: %w", err)
 		}
+		feePayer = &key
+	}
 
-		tokenMint, err := solana.PublicKeyFromBase58(input.Params.TokenMint)
-		if err != nil {
-			return nil, fmt.Errorf("invalid token mint address: %w", err)
-		}
+	var result interface{}
 
-		// Convert SOL to lamports (1 SOL = 1e9 lamports)
-		var lamports uint64
-		if tokenMint.Equals(WSOL_MINT) {
-			lamports = uint64(input.Params.Amount * 1e9)
-		} else {
-			lamports = uint64(input.Params.Amount)
+	switch input.Action {
+	case ActionTransfer:
+		transfer, err := parseTransferParams(input.Params)
+		if err != nil {
+			return nil, err
 		}
 
-		sig, err := t.Transfer(ctx, privateKey, destPubKey, tokenMint, lamports)
+		sig, err := t.Transfer(ctx, TransferParams{
+			From:       privateKey,
+			To:         transfer.To,
+			TokenMint:  transfer.TokenMint,
+			Amount:     transfer.Amount,
+			Memo:       transfer.Memo,
+			References: transfer.References,
+			FeePayer:   feePayer,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create transfer transaction: %w", err)
 		}
@@ -147,24 +279,18 @@ func (t *OnchainActionsTool) Execute(ctx context.Context, params json.RawMessage
 		}
 
 	case ActionBuy, ActionSell:
-		if input.Params.TokenMint == "" || input.Params.Amount <= 0 {
-			return nil, fmt.Errorf("invalid swap parameters")
-		}
-
 		// Set up quote parameters
-		quoteParams := jupiter.GetQuoteParams{
-			Amount: jupiter.AmountParameter(input.Params.Amount),
+		quoteParams, err := t.BuildQuoteParams(ctx, Side(input.Action), input.Params)
+		if err != nil {
+			return nil, err
 		}
 
-		if input.Action == ActionBuy {
-			quoteParams.InputMint = "So11111111111111111111111111111111111111112" // SOL
-			quoteParams.OutputMint = input.Params.TokenMint
-		} else {
-			quoteParams.InputMint = input.Params.TokenMint
-			quoteParams.OutputMint = "So11111111111111111111111111111111111111112" // SOL
+		payer := privateKey
+		if feePayer != nil {
+			payer = *feePayer
 		}
 
-		sig, err := t.Swap(ctx, quoteParams, privateKey)
+		sig, err := t.SwapWithFeePayer(ctx, quoteParams, privateKey, payer)
 		if err != nil {
 			return nil, err
 		}
@@ -188,6 +314,7 @@ func (t *OnchainActionsTool) Execute(ctx context.Context, params json.RawMessage
 			UserPrivateKey:  privateKey,
 			BuyAmount:       input.Params.Amount,
 			SlippagePercent: 10, // Default slippage
+			FeePayer:        feePayer,
 		})
 		if err != nil {
 			return nil, err
@@ -200,9 +327,41 @@ func (t *OnchainActionsTool) Execute(ctx context.Context, params json.RawMessage
 			MintAddress: &mintAddress,
 		}
 
+	case ActionClaimCreatorFees:
+		sig, err := t.ClaimCreatorFees(ctx, ClaimCreatorFeesParams{
+			Creator:  privateKey,
+			FeePayer: feePayer,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result = OnchainActionsOutput{
+			Signature: sig.String(),
+		}
+
 	default:
 		return nil, fmt.Errorf("unsupported action: %s", input.Action)
 	}
 
 	return json.Marshal(result)
 }
+
+// creatorAddress reads the creator from params.owner, or from the source key
+func creatorAddress(params Params) (solana.PublicKey, error) {
+	if params.Owner != "" {
+		creator, err := solana.PublicKeyFromBase58(params.Owner)
+		if err != nil {
+			return solana.PublicKey{}, fmt.Errorf("invalid owner address: %w", err)
+		}
+		return creator, nil
+	}
+	if params.Source != "" {
+		privateKey, err := solana.PrivateKeyFromBase58(params.Source)
+		if err != nil {
+			return solana.PublicKey{}, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		return privateKey.PublicKey(), nil
+	}
+	return solana.PublicKey{}, fmt.Errorf("owner is required")
+}
@@ -2,14 +2,23 @@ package onchain_actions
 
 import (
 	"context"
+	"encoding/json"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/ilkamo/jupiter-go/jupiter"
 	"github.com/joho/godotenv"
+	"github.com/soralabs/solana-toolkit/go/audit_log"
 	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_simulator"
 )
 
 func TestNewOnchainActionsTool(t *testing.T) {
@@ -85,7 +94,14 @@ func TestTransfer(t *testing.T) {
 	toWallet := solana.NewWallet()
 
 	// Test transfer
-	tx, err := tool.Transfer(ctx, wallet, toWallet.PublicKey(), solana.MustPublicKeyFromBase58(os.Getenv("TOKEN_MINT")), 1000000)
+	tx, err := tool.Transfer(ctx, TransferParams{
+		From:       wallet,
+		To:         toWallet.PublicKey(),
+		TokenMint:  solana.MustPublicKeyFromBase58(os.Getenv("TOKEN_MINT")),
+		Amount:     1000000,
+		Memo:       "toolkit transfer test",
+		References: []solana.PublicKey{solana.NewWallet().PublicKey()},
+	})
 	if err != nil {
 		t.Errorf("Transfer error (expected during test): %v", err)
 	}
@@ -131,3 +147,411 @@ func TestCreateToken(t *testing.T) {
 	t.Log("Successfully created token with mint", mintWallet.PublicKey().String())
 	t.Log("Signature", sig.String())
 }
+
+func TestWithReferences(t *testing.T) {
+	from := solana.NewWallet().PublicKey()
+	to := solana.NewWallet().PublicKey()
+	reference := solana.NewWallet().PublicKey()
+
+	ix, err := withReferences(system.NewTransferInstruction(1000, from, to).Build(), []solana.PublicKey{reference})
+	if err != nil {
+		t.Fatalf("failed to add references: %v", err)
+	}
+
+	accounts := ix.Accounts()
+	if len(accounts) != 3 {
+		t.Fatalf("expected 3 accounts, got %d", len(accounts))
+	}
+
+	last := accounts[2]
+	if !last.PublicKey.Equals(reference) || last.IsSigner || last.IsWritable {
+		t.Errorf("expected read-only reference account, got %+v", last)
+	}
+	if !ix.ProgramID().Equals(system.ProgramID) {
+		t.Errorf("expected system program, got %s", ix.ProgramID())
+	}
+}
+
+func TestSignTransactionWithFeePayer(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	feePayer := solana.NewWallet().PrivateKey
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1000, owner.PublicKey(), solana.NewWallet().PublicKey()).Build()},
+		solana.Hash{},
+		solana.TransactionPayer(feePayer.PublicKey()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create transaction: %v", err)
+	}
+
+	if err := signTransaction(tx, owner, feePayer); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	if !tx.Message.AccountKeys[0].Equals(feePayer.PublicKey()) {
+		t.Errorf("expected fee payer first, got %s", tx.Message.AccountKeys[0])
+	}
+	if len(tx.Signatures) != 2 {
+		t.Fatalf("expected 2 signatures, got %d", len(tx.Signatures))
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("invalid signatures: %v", err)
+	}
+}
+
+func TestToRawAmount(t *testing.T) {
+	if raw := toRawAmount(1.005, 6); raw != 1_005_000 {
+		t.Errorf("expected 1005000, got %f", raw)
+	}
+	if raw := toRawAmount(0.1, 9); raw != 100_000_000 {
+		t.Errorf("expected 100000000, got %f", raw)
+	}
+}
+
+func TestCanFallBack(t *testing.T) {
+	ctx := context.Background()
+
+	noRoute := jupiterResponseError("GetQuoteWithResponse", 400, []byte(`{"error":"Could not find any route","errorCode":"COULD_NOT_FIND_ANY_ROUTE"}`))
+	if !canFallBack(ctx, noRoute) {
+		t.Error("expected fallback when Jupiter has no route")
+	}
+	if !canFallBack(ctx, jupiterResponseError("PostSwapWithResponse", 502, nil)) {
+		t.Error("expected fallback on a server error")
+	}
+	if canFallBack(ctx, jupiterResponseError("GetQuoteWithResponse", 400, []byte(`{"error":"Invalid amount"}`))) {
+		t.Error("fell back on a bad request")
+	}
+	if !canFallBack(ctx, jupiterResponseError("GetQuoteWithResponse", 429, []byte(`{"error":"Too many requests"}`))) {
+		t.Error("expected fallback when Jupiter rate limits us")
+	}
+	dialErr := &url.Error{Op: "Get", URL: "https://quote-api.jup.ag/v6/quote", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	if !canFallBack(ctx, jupiterCallError("GetQuoteWithResponse", dialErr)) {
+		t.Error("expected fallback when Jupiter can't be reached")
+	}
+	if canFallBack(ctx, jupiterCallError("GetQuoteWithResponse", context.DeadlineExceeded)) {
+		t.Error("fell back on a context error")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if canFallBack(cancelled, noRoute) {
+		t.Error("fell back after the context was cancelled")
+	}
+}
+
+func TestUnsignedTransactionOutput(t *testing.T) {
+	owner := solana.NewWallet().PrivateKey
+	mint := solana.NewWallet().PrivateKey
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewTransferInstruction(1000, owner.PublicKey(), solana.NewWallet().PublicKey()).Build(),
+			system.NewTransferInstruction(1000, mint.PublicKey(), solana.NewWallet().PublicKey()).Build(),
+		},
+		solana.Hash{},
+		solana.TransactionPayer(owner.PublicKey()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create transaction: %v", err)
+	}
+
+	// A freshly generated mint signs up front, only the owner is left
+	if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(mint.PublicKey()) {
+			return &mint
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to partially sign: %v", err)
+	}
+
+	output, err := newUnsignedTransactionOutput(tx, "test")
+	if err != nil {
+		t.Fatalf("failed to build output: %v", err)
+	}
+	if len(output.RequiredSigners) != 1 || output.RequiredSigners[0] != owner.PublicKey().String() {
+		t.Errorf("expected only the owner to sign, got %v", output.RequiredSigners)
+	}
+
+	tool, err := NewOnchainActionsTool(rpc.New("http://localhost:0"))
+	if err != nil {
+		t.Fatalf("failed to create onchain actions tool: %v", err)
+	}
+
+	// Submitting before the wallet signed is rejected without reaching the RPC
+	if _, err := tool.SubmitTransaction(context.Background(), output.Transaction); err == nil {
+		t.Fatal("expected error for a partially signed transaction")
+	}
+
+	// The wallet signs the decoded transaction and it verifies
+	signed, err := solana.TransactionFromBase64(output.Transaction)
+	if err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if _, err := signed.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(owner.PublicKey()) {
+			return &owner
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to sign as wallet: %v", err)
+	}
+	if err := signed.VerifySignatures(); err != nil {
+		t.Errorf("invalid signatures after wallet signed: %v", err)
+	}
+
+	// Unsigned mode never accepts a private key in place of the owner
+	if _, err := tool.Execute(context.Background(), []byte(`{
+		"action": "transfer",
+		"mode": "unsigned",
+		"params": {"source": "`+owner.String()+`", "destination": "`+mint.PublicKey().String()+`", "token_mint": "So11111111111111111111111111111111111111112", "amount": 1}
+	}`)); err == nil {
+		t.Error("expected owner to be required in unsigned mode")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	err := godotenv.Load()
+	if err != nil {
+		t.Fatalf("Error loading .env file: %v", err)
+	}
+
+	tool, err := NewOnchainActionsTool(rpc.New(os.Getenv("RPC_URL")))
+	if err != nil {
+		t.Fatalf("failed to create onchain actions tool: %v", err)
+	}
+
+	// No source is needed to quote
+	result, err := tool.Execute(context.Background(), []byte(`{
+		"action": "quote",
+		"params": {
+			"token_mint": "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+			"amount": 2,
+			"side": "buy",
+			"slippage_bps": 100
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Quote error: %v", err)
+	}
+
+	var quote QuoteOutput
+	if err := json.Unmarshal(result, &quote); err != nil {
+		t.Fatalf("failed to unmarshal quote: %v", err)
+	}
+
+	if quote.InAmount != "2000000000" {
+		t.Errorf("expected 2 SOL in lamports, got %s", quote.InAmount)
+	}
+	if len(quote.RoutePlan) == 0 {
+		t.Error("expected a route plan")
+	}
+
+	t.Logf("2 SOL buys %f USDC (min %f) via %s", quote.OutAmountUI, quote.MinOutAmountUI, quote.Source)
+}
+
+func TestBuildQuoteParamsScalesAmount(t *testing.T) {
+	sim := pumpfun_simulator.New()
+	_, mint, err := sim.CreateToken(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool, err := NewOnchainActionsTool(sim.Client())
+	if err != nil {
+		t.Fatalf("failed to create onchain actions tool: %v", err)
+	}
+
+	// Buys spend SOL, scaled by its 9 decimals
+	buy, err := tool.BuildQuoteParams(context.Background(), SideBuy, Params{TokenMint: mint.String(), Amount: 0.5})
+	if err != nil {
+		t.Fatalf("failed to build buy quote params: %v", err)
+	}
+	if buy.InputMint != WSOL_MINT.String() || buy.Amount != 500_000_000 {
+		t.Errorf("expected 0.5 SOL as 500000000 lamports of WSOL, got %v of %s", buy.Amount, buy.InputMint)
+	}
+
+	// Sells spend the token, scaled by the mint's 6 decimals
+	sell, err := tool.BuildQuoteParams(context.Background(), SideSell, Params{TokenMint: mint.String(), Amount: 1.005})
+	if err != nil {
+		t.Fatalf("failed to build sell quote params: %v", err)
+	}
+	if sell.InputMint != mint.String() || sell.Amount != 1_005_000 {
+		t.Errorf("expected 1.005 tokens as 1005000 base units, got %v of %s", sell.Amount, sell.InputMint)
+	}
+}
+
+func TestCreateTokenSimulated(t *testing.T) {
+	sim := pumpfun_simulator.New()
+	wallet := solana.NewWallet().PrivateKey
+	sim.Airdrop(wallet.PublicKey(), 10*solana.LAMPORTS_PER_SOL)
+
+	tool, err := NewOnchainActionsTool(sim.Client())
+	if err != nil {
+		t.Fatalf("failed to create onchain actions tool: %v", err)
+	}
+
+	mintWallet := solana.NewWallet()
+	sig, err := tool.CreateToken(context.Background(), CreateTokenParams{
+		TokenInfo: pumpfun.CreateTokenInformation{
+			Name:     "Test Token",
+			Symbol:   "TEST",
+			ImageURI: "https://example.com/image.png",
+		},
+		Mint:            mintWallet,
+		UserPrivateKey:  wallet,
+		BuyAmount:       0.1,
+		SlippagePercent: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	if _, ok := sim.Transaction(*sig); !ok {
+		t.Errorf("transaction %s was not processed", sig)
+	}
+	if _, ok := sim.BondingCurve(mintWallet.PublicKey()); !ok {
+		t.Error("bonding curve was not created")
+	}
+	if sim.TokenBalance(wallet.PublicKey(), mintWallet.PublicKey()) == 0 {
+		t.Error("initial buy received no tokens")
+	}
+}
+
+func TestUnsignedCreateSimulated(t *testing.T) {
+	sim := pumpfun_simulator.New()
+	wallet := solana.NewWallet().PrivateKey
+	sim.Airdrop(wallet.PublicKey(), 10*solana.LAMPORTS_PER_SOL)
+
+	tool, err := NewOnchainActionsTool(sim.Client())
+	if err != nil {
+		t.Fatalf("failed to create onchain actions tool: %v", err)
+	}
+
+	ctx := context.Background()
+	result, err := tool.Execute(ctx, []byte(`{
+		"action": "create",
+		"mode": "unsigned",
+		"params": {"owner": "`+wallet.PublicKey().String()+`", "token_name": "Test Token", "token_symbol": "TEST", "amount": 0.1}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to build unsigned create: %v", err)
+	}
+
+	var output UnsignedTransactionOutput
+	if err := json.Unmarshal(result, &output); err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+	if len(output.RequiredSigners) != 1 || output.RequiredSigners[0] != wallet.PublicKey().String() {
+		t.Fatalf("required signers = %v, want only the owner", output.RequiredSigners)
+	}
+
+	// Sign as the external wallet would and send it back
+	tx, err := solana.TransactionFromBase64(output.Transaction)
+	if err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if _, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(wallet.PublicKey()) {
+			return &wallet
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	signed, err := tx.ToBase64()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+
+	if _, err := tool.SubmitTransaction(ctx, signed); err != nil {
+		t.Fatalf("failed to submit: %v", err)
+	}
+
+	mint := solana.MustPublicKeyFromBase58(*output.MintAddress)
+	if sim.TokenBalance(wallet.PublicKey(), mint) == 0 {
+		t.Error("initial buy received no tokens")
+	}
+}
+
+func TestCreatorFeesSimulated(t *testing.T) {
+	sim := pumpfun_simulator.New(pumpfun_simulator.WithCreatorFee(30))
+	creator := solana.NewWallet().PrivateKey
+	sim.Airdrop(creator.PublicKey(), 10*solana.LAMPORTS_PER_SOL)
+
+	store := audit_log.NewJSONLStore(filepath.Join(t.TempDir(), "audit.jsonl"))
+	tool, err := NewOnchainActionsTool(sim.Client(), WithAuditStore(store))
+	if err != nil {
+		t.Fatalf("failed to create onchain actions tool: %v", err)
+	}
+
+	ctx := context.Background()
+	mints := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		mintWallet := solana.NewWallet()
+		if _, err := tool.CreateToken(ctx, CreateTokenParams{
+			TokenInfo:       pumpfun.CreateTokenInformation{Name: "Test Token", Symbol: "TEST"},
+			Mint:            mintWallet,
+			UserPrivateKey:  creator,
+			BuyAmount:       1,
+			SlippagePercent: 10,
+		}); err != nil {
+			t.Fatalf("failed to create token: %v", err)
+		}
+		mints[mintWallet.PublicKey().String()] = true
+	}
+
+	report := func() CreatorFeesOutput {
+		t.Helper()
+		result, err := tool.Execute(ctx, []byte(`{"action": "creator_fees", "params": {"owner": "`+creator.PublicKey().String()+`"}}`))
+		if err != nil {
+			t.Fatalf("failed to get creator fees: %v", err)
+		}
+		var output CreatorFeesOutput
+		if err := json.Unmarshal(result, &output); err != nil {
+			t.Fatalf("failed to decode output: %v", err)
+		}
+		return output
+	}
+
+	fees := report()
+	if fees.ClaimableLamports == 0 {
+		t.Fatal("no claimable creator fees after two buys")
+	}
+	if len(fees.Tokens) != 2 {
+		t.Fatalf("tokens = %+v, want the 2 created", fees.Tokens)
+	}
+	for _, token := range fees.Tokens {
+		if !mints[token.Mint] {
+			t.Errorf("unexpected token %s", token.Mint)
+		}
+	}
+	if !strings.Contains(fees.Note, "PumpSwap") {
+		t.Errorf("note = %q, want the PumpSwap limitation", fees.Note)
+	}
+
+	before := sim.Balance(creator.PublicKey())
+	if _, err := tool.Execute(ctx, []byte(`{"action": "claim_creator_fees", "params": {"source": "`+creator.String()+`"}}`)); err != nil {
+		t.Fatalf("failed to claim creator fees: %v", err)
+	}
+	if got, want := sim.Balance(creator.PublicKey()), before+fees.ClaimableLamports-pumpfun_simulator.LamportsPerSignature; got != want {
+		t.Errorf("creator balance = %d, want %d", got, want)
+	}
+
+	// The claim is audited with its transaction and simulation
+	records, err := store.Query(ctx, audit_log.Query{Limit: 1})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	if records[0].Action != "claim_creator_fees" || records[0].Transaction == "" || records[0].Simulation == nil || records[0].Simulation.Err != "" {
+		t.Errorf("audit record = %+v, want the claim with its transaction and simulation", records[0])
+	}
+
+	if after := report(); after.ClaimableLamports != 0 {
+		t.Errorf("claimable after claim = %d, want 0", after.ClaimableLamports)
+	}
+	if _, err := tool.Execute(ctx, []byte(`{"action": "claim_creator_fees", "params": {"source": "`+creator.String()+`"}}`)); err == nil {
+		t.Error("claim with nothing claimable succeeded")
+	}
+}
@@ -0,0 +1,213 @@
+package onchain_actions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/ilkamo/jupiter-go/jupiter"
+)
+
+// Quote estimates the result of a swap without building or signing a
+// transaction. Jupiter is asked first and the native Raydium pools are used
+// when Jupiter is down, rate limits us or has no route for the pair.
+func (t *OnchainActionsTool) Quote(ctx context.Context, quoteRequest jupiter.GetQuoteParams) (*QuoteOutput, error) {
+	output, err := t.quoteJupiter(ctx, quoteRequest)
+	if err != nil {
+		if !canFallBack(ctx, err) {
+			return nil, fmt.Errorf("failed to get quote: %w", err)
+		}
+		var raydiumErr error
+		output, raydiumErr = t.quoteRaydiumOutput(ctx, quoteRequest)
+		if raydiumErr != nil {
+			return nil, fmt.Errorf("failed to get quote: jupiter: %v, raydium: %w", err, raydiumErr)
+		}
+	}
+
+	inputMint, err := solana.PublicKeyFromBase58(output.InputMint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input mint in quote: %w", err)
+	}
+	outputMint, err := solana.PublicKeyFromBase58(output.OutputMint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output mint in quote: %w", err)
+	}
+
+	inputDecimals, err := t.getMintDecimals(ctx, inputMint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input mint decimals: %w", err)
+	}
+	outputDecimals, err := t.getMintDecimals(ctx, outputMint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output mint decimals: %w", err)
+	}
+
+	output.InAmountUI = toUIAmount(output.InAmount, inputDecimals)
+	output.OutAmountUI = toUIAmount(output.OutAmount, outputDecimals)
+	output.MinOutAmountUI = toUIAmount(output.MinOutAmount, outputDecimals)
+
+	return output, nil
+}
+
+// valueQuote values both sides of a quote in USD with the price oracle. A
+// quote with an unparsable mint is left without values.
+func (t *OnchainActionsTool) valueQuote(ctx context.Context, quote *QuoteOutput) {
+	inputMint, err := solana.PublicKeyFromBase58(quote.InputMint)
+	if err != nil {
+		return
+	}
+	outputMint, err := solana.PublicKeyFromBase58(quote.OutputMint)
+	if err != nil {
+		return
+	}
+	mints := []solana.PublicKey{inputMint, outputMint}
+	prices := t.priceOracle.Prices(ctx, mints)
+
+	if price, ok := prices[mints[0]]; ok {
+		quote.InValueUSD = quote.InAmountUI * price.USD
+	}
+	if price, ok := prices[mints[1]]; ok {
+		quote.OutValueUSD = quote.OutAmountUI * price.USD
+	}
+}
+
+func (t *OnchainActionsTool) quoteJupiter(ctx context.Context, quoteRequest jupiter.GetQuoteParams) (*QuoteOutput, error) {
+	quoteResponse, err := t.jupClient.GetQuoteWithResponse(ctx, &quoteRequest)
+	if err != nil {
+		return nil, jupiterCallError("GetQuoteWithResponse", err)
+	}
+
+	if quoteResponse.JSON200 == nil {
+		return nil, jupiterResponseError("GetQuoteWithResponse", quoteResponse.StatusCode(), quoteResponse.Body)
+	}
+	quote := quoteResponse.JSON200
+
+	priceImpact, err := strconv.ParseFloat(quote.PriceImpactPct, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price impact: %w", err)
+	}
+
+	routePlan := make([]QuoteRouteStep, len(quote.RoutePlan))
+	for i, step := range quote.RoutePlan {
+		routePlan[i] = QuoteRouteStep{
+			AmmKey:     step.SwapInfo.AmmKey,
+			Label:      step.SwapInfo.Label,
+			InputMint:  step.SwapInfo.InputMint,
+			OutputMint: step.SwapInfo.OutputMint,
+			InAmount:   step.SwapInfo.InAmount,
+			OutAmount:  step.SwapInfo.OutAmount,
+			FeeAmount:  step.SwapInfo.FeeAmount,
+			FeeMint:    step.SwapInfo.FeeMint,
+			Percent:    int(step.Percent),
+		}
+	}
+
+	var platformFee *QuoteFee
+	if quote.PlatformFee != nil && quote.PlatformFee.Amount != "" {
+		platformFee = &QuoteFee{Amount: quote.PlatformFee.Amount, Mint: quote.OutputMint}
+	}
+
+	return &QuoteOutput{
+		Source:         QuoteSourceJupiter,
+		InputMint:      quote.InputMint,
+		OutputMint:     quote.OutputMint,
+		InAmount:       quote.InAmount,
+		OutAmount:      quote.OutAmount,
+		MinOutAmount:   quote.OtherAmountThreshold,
+		SlippageBps:    int(quote.SlippageBps),
+		PriceImpactPct: priceImpact * 100,
+		RoutePlan:      routePlan,
+		PlatformFee:    platformFee,
+	}, nil
+}
+
+func (t *OnchainActionsTool) quoteRaydiumOutput(ctx context.Context, quoteRequest jupiter.GetQuoteParams) (*QuoteOutput, error) {
+	pool, quote, err := t.quoteRaydium(ctx, quoteRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	slippageBps := defaultSlippageBps
+	if quoteRequest.SlippageBps != nil {
+		slippageBps = *quoteRequest.SlippageBps
+	}
+
+	amountIn := strconv.FormatUint(quote.AmountIn, 10)
+	amountOut := strconv.FormatUint(quote.AmountOut, 10)
+
+	return &QuoteOutput{
+		Source:         QuoteSourceRaydium,
+		InputMint:      quote.InputMint.String(),
+		OutputMint:     quote.OutputMint.String(),
+		InAmount:       amountIn,
+		OutAmount:      amountOut,
+		MinOutAmount:   strconv.FormatUint(quote.MinAmountOut, 10),
+		SlippageBps:    slippageBps,
+		PriceImpactPct: quote.PriceImpactPct,
+		RoutePlan: []QuoteRouteStep{
+			{
+				AmmKey:     pool.Address.String(),
+				Label:      raydiumLabel(quote),
+				InputMint:  quote.InputMint.String(),
+				OutputMint: quote.OutputMint.String(),
+				InAmount:   amountIn,
+				OutAmount:  amountOut,
+				FeeAmount:  strconv.FormatUint(quote.Fee, 10),
+				FeeMint:    quote.InputMint.String(),
+				Percent:    100,
+			},
+		},
+	}, nil
+}
+
+// toUIAmount converts a raw token amount into a decimal amount
+func toUIAmount(amount string, decimals uint8) float64 {
+	raw, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return raw / math.Pow10(int(decimals))
+}
+
+// toRawAmount converts a decimal amount into base units, rounding to the
+// nearest unit so that amounts like 1.005 aren't truncated to 1.004999
+func toRawAmount(amount float64, decimals uint8) float64 {
+	return math.Round(amount * math.Pow10(int(decimals)))
+}
+
+// BuildQuoteParams turns tool parameters into a Jupiter quote request. The
+// amount is given in whole tokens (or SOL) of the input side and converted to
+// base units using the input mint's decimals.
+func (t *OnchainActionsTool) BuildQuoteParams(ctx context.Context, side Side, params Params) (jupiter.GetQuoteParams, error) {
+	if params.TokenMint == "" || params.Amount <= 0 {
+		return jupiter.GetQuoteParams{}, fmt.Errorf("invalid swap parameters")
+	}
+
+	tokenMint, err := solana.PublicKeyFromBase58(params.TokenMint)
+	if err != nil {
+		return jupiter.GetQuoteParams{}, fmt.Errorf("invalid token mint address: %w", err)
+	}
+
+	inputMint, outputMint := WSOL_MINT, tokenMint
+	switch side {
+	case SideBuy, "":
+	case SideSell:
+		inputMint, outputMint = tokenMint, WSOL_MINT
+	default:
+		return jupiter.GetQuoteParams{}, fmt.Errorf("invalid side: %s", side)
+	}
+
+	decimals, err := t.getMintDecimals(ctx, inputMint)
+	if err != nil {
+		return jupiter.GetQuoteParams{}, fmt.Errorf("failed to get input mint decimals: %w", err)
+	}
+
+	return jupiter.GetQuoteParams{
+		InputMint:   inputMint.String(),
+		OutputMint:  outputMint.String(),
+		Amount:      jupiter.AmountParameter(toRawAmount(params.Amount, decimals)),
+		SlippageBps: params.SlippageBps,
+	}, nil
+}
@@ -3,22 +3,79 @@ package onchain_actions
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/ilkamo/jupiter-go/jupiter"
 )
 
-// Swap creates and signs a swap transaction with priority fees
+// errJupiterUnavailable marks Jupiter failures that Raydium may still serve:
+// downtime, rate limits, server errors and pairs Jupiter has no route for
+var errJupiterUnavailable = errors.New("jupiter unavailable")
+
+// jupiterNoRouteCodes are the error codes Jupiter answers with when it
+// cannot route a pair
+var jupiterNoRouteCodes = map[string]bool{
+	"COULD_NOT_FIND_ANY_ROUTE": true,
+	"NO_ROUTES_FOUND":          true,
+	"TOKEN_NOT_TRADABLE":       true,
+}
+
+// Swap creates and signs a swap transaction with priority fees.
+// Jupiter is used first; if it is down, rate limits us or has no route for
+// the pair the swap is routed directly through Raydium.
 func (t *OnchainActionsTool) Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error) {
+	return t.SwapWithFeePayer(ctx, quoteRequest, signer, signer)
+}
+
+// SwapWithFeePayer swaps like Swap, with feePayer paying the transaction fees
+// and account rent so the signer only needs to hold the input token
+func (t *OnchainActionsTool) SwapWithFeePayer(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer, feePayer solana.PrivateKey) (*solana.Signature, error) {
+	tx, err := t.BuildSwapTransaction(ctx, quoteRequest, signer.PublicKey(), feePayer.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign the transaction
+	if err := signTransaction(tx, signer, feePayer); err != nil {
+		return nil, err
+	}
+
+	return t.sendTransacton(ctx, tx)
+}
+
+// BuildSwapTransaction returns the unsigned swap transaction for user, built
+// through Jupiter with a Raydium fallback. It must be signed by user and feePayer.
+func (t *OnchainActionsTool) BuildSwapTransaction(ctx context.Context, quoteRequest jupiter.GetQuoteParams, user, feePayer solana.PublicKey) (*solana.Transaction, error) {
+	tx, err := t.buildJupiterSwapTransaction(ctx, quoteRequest, user, feePayer)
+	if err != nil {
+		if !canFallBack(ctx, err) {
+			return nil, fmt.Errorf("failed to build swap transaction: %w", err)
+		}
+		var raydiumErr error
+		tx, raydiumErr = t.buildRaydiumSwapTransaction(ctx, quoteRequest, user, feePayer)
+		if raydiumErr != nil {
+			return nil, fmt.Errorf("failed to build swap transaction: jupiter: %v, raydium: %w", err, raydiumErr)
+		}
+	}
+
+	return tx, nil
+}
+
+// buildJupiterSwapTransaction quotes through Jupiter and returns the unsigned
+// swap transaction. The payer covers fees and rent when it differs from the user.
+func (t *OnchainActionsTool) buildJupiterSwapTransaction(ctx context.Context, quoteRequest jupiter.GetQuoteParams, user, payer solana.PublicKey) (*solana.Transaction, error) {
 	// Get quote using Jupiter client
 	quoteResponse, err := t.jupClient.GetQuoteWithResponse(ctx, &quoteRequest)
 	if err != nil {
-		return nil, err
+		return nil, jupiterCallError("GetQuoteWithResponse", err)
 	}
 
 	if quoteResponse.JSON200 == nil {
-		return nil, fmt.Errorf("invalid GetQuoteWithResponse response")
+		return nil, jupiterResponseError("GetQuoteWithResponse", quoteResponse.StatusCode(), quoteResponse.Body)
 	}
 
 	// Setup swap request parameters
@@ -29,19 +86,25 @@ func (t *OnchainActionsTool) Swap(ctx context.Context, quoteRequest jupiter.GetQ
 
 	dynamicComputeUnitLimit := true
 
-	// Get swap transaction
-	swapResponse, err := t.jupClient.PostSwapWithResponse(ctx, jupiter.PostSwapJSONRequestBody{
+	swapRequest := jupiter.PostSwapJSONRequestBody{
 		PrioritizationFeeLamports: &prioritizationFeeLamports,
 		QuoteResponse:             *quoteResponse.JSON200,
-		UserPublicKey:             signer.PublicKey().String(),
+		UserPublicKey:             user.String(),
 		DynamicComputeUnitLimit:   &dynamicComputeUnitLimit,
-	})
+	}
+	if !payer.Equals(user) {
+		payerAddress := payer.String()
+		swapRequest.Payer = &payerAddress
+	}
+
+	// Get swap transaction
+	swapResponse, err := t.jupClient.PostSwapWithResponse(ctx, swapRequest)
 	if err != nil {
-		return nil, err
+		return nil, jupiterCallError("PostSwapWithResponse", err)
 	}
 
 	if swapResponse.JSON200 == nil {
-		return nil, fmt.Errorf("invalid PostSwapWithResponse response")
+		return nil, jupiterResponseError("PostSwapWithResponse", swapResponse.StatusCode(), swapResponse.Body)
 	}
 
 	// Decode base64 transaction
@@ -56,13 +119,36 @@ func (t *OnchainActionsTool) Swap(ctx context.Context, quoteRequest jupiter.GetQ
 		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
 	}
 
-	// Sign the transaction
-	tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		if key.Equals(signer.PublicKey()) {
-			return &signer
-		}
-		return nil
-	})
+	return tx, nil
+}
 
-	return t.sendTransacton(ctx, tx)
+// jupiterResponseError describes a Jupiter response without a result,
+// wrapping errJupiterUnavailable when Raydium may serve the request instead
+func jupiterResponseError(call string, status int, body []byte) error {
+	var response struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"errorCode"`
+	}
+	_ = json.Unmarshal(body, &response)
+
+	if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests || jupiterNoRouteCodes[response.ErrorCode] {
+		return fmt.Errorf("%w: invalid %s response: status %d %s", errJupiterUnavailable, call, status, response.Error)
+	}
+	return fmt.Errorf("invalid %s response: status %d %s", call, status, response.Error)
+}
+
+// jupiterCallError wraps a Jupiter request that failed without a response,
+// such as a refused connection or a DNS failure, in errJupiterUnavailable.
+// Errors of the caller's context are returned as they are.
+func jupiterCallError(call string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %s failed: %w", errJupiterUnavailable, call, err)
+}
+
+// canFallBack reports whether a Jupiter error should be retried through
+// Raydium. A cancelled or expired context is never retried.
+func canFallBack(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && errors.Is(err, errJupiterUnavailable)
 }
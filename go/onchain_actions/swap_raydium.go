@@ -0,0 +1,111 @@
+package onchain_actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/ilkamo/jupiter-go/jupiter"
+
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+// Jupiter's default slippage, used when the quote request does not set one
+const defaultSlippageBps = 50
+
+// SwapRaydium swaps directly through the deepest Raydium AMM v4 or CPMM pool
+// for the pair, without going through Jupiter
+func (t *OnchainActionsTool) SwapRaydium(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error) {
+	tx, err := t.buildRaydiumSwapTransaction(ctx, quoteRequest, signer.PublicKey(), signer.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := signTransaction(tx, signer); err != nil {
+		return nil, err
+	}
+
+	return t.sendTransacton(ctx, tx)
+}
+
+// quoteRaydium finds the Raydium pool giving the best output for the quote request
+func (t *OnchainActionsTool) quoteRaydium(ctx context.Context, quoteRequest jupiter.GetQuoteParams) (*raydium.Pool, *raydium.Quote, error) {
+	inputMint, err := solana.PublicKeyFromBase58(quoteRequest.InputMint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid input mint: %w", err)
+	}
+	outputMint, err := solana.PublicKeyFromBase58(quoteRequest.OutputMint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid output mint: %w", err)
+	}
+	if quoteRequest.Amount <= 0 {
+		return nil, nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	slippageBps := uint64(defaultSlippageBps)
+	if quoteRequest.SlippageBps != nil {
+		slippageBps = uint64(*quoteRequest.SlippageBps)
+	}
+
+	pools, err := raydium.FindPools(ctx, t.rpcClient, inputMint, outputMint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return raydium.BestQuote(pools, inputMint, uint64(quoteRequest.Amount), slippageBps)
+}
+
+// buildRaydiumSwapTransaction returns the unsigned Raydium swap transaction for
+// the quote request, with payer covering fees and account rent
+func (t *OnchainActionsTool) buildRaydiumSwapTransaction(ctx context.Context, quoteRequest jupiter.GetQuoteParams, user, payer solana.PublicKey) (*solana.Transaction, error) {
+	pool, quote, err := t.quoteRaydium(ctx, quoteRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote raydium swap: %w", err)
+	}
+
+	swapInstructions, err := raydium.BuildSwapInstructions(raydium.SwapRequest{
+		Pool:         pool,
+		User:         user,
+		Payer:        payer,
+		InputMint:    quote.InputMint,
+		AmountIn:     quote.AmountIn,
+		MinAmountOut: quote.MinAmountOut,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build raydium swap instructions: %w", err)
+	}
+
+	instructions := []solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(raydiumSwapComputeUnits).Build(),
+	}
+	if cupInst, err := t.getComputeUnitPriceInstruction(ctx, solana.PublicKeySlice{user, pool.Address}); err == nil {
+		instructions = append(instructions, cupInst.Build())
+	}
+	instructions = append(instructions, swapInstructions...)
+
+	recent, err := t.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent blockhash: %w", err)
+	}
+
+	tx, err := solana.NewTransaction(
+		instructions,
+		recent.Value.Blockhash,
+		solana.TransactionPayer(payer),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// raydiumLabel names the pool type the way Jupiter labels Raydium routes
+func raydiumLabel(quote *raydium.Quote) string {
+	if quote.PoolType == raydium.PoolTypeCpmm {
+		return "Raydium CP"
+	}
+	return "Raydium"
+}
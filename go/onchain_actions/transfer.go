@@ -6,37 +6,109 @@ import (
 
 	"github.com/gagliardetto/solana-go"
 	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/memo"
 	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/programs/token"
 	"github.com/gagliardetto/solana-go/rpc"
 )
 
+type TransferParams struct {
+	From      solana.PrivateKey
+	To        solana.PublicKey
+	TokenMint solana.PublicKey
+	Amount    uint64
+
+	// Memo is an optional message recorded through the SPL Memo program
+	Memo string
+	// References are optional public keys added as read-only accounts to the
+	// transfer instruction, so the transaction can be found by reference later
+	References []solana.PublicKey
+
+	// FeePayer optionally pays the transaction fees and the destination
+	// account rent, so From doesn't need to hold SOL for token transfers
+	FeePayer *solana.PrivateKey
+}
+
+// TransferTransactionParams describes a transfer by public keys only, for
+// building the transaction without holding any key
+type TransferTransactionParams struct {
+	From       solana.PublicKey
+	To         solana.PublicKey
+	TokenMint  solana.PublicKey
+	Amount     uint64
+	Memo       string
+	References []solana.PublicKey
+	FeePayer   solana.PublicKey // pays fees and rent, defaults to From
+}
+
 // Transfer creates and signs a transfer transaction for either SOL or SPL tokens
-func (t *OnchainActionsTool) Transfer(
-	ctx context.Context,
-	from solana.PrivateKey,
-	tokenMint solana.PublicKey,
-	to solana.PublicKey,
-	amount uint64,
-) (*solana.Signature, error) {
+func (t *OnchainActionsTool) Transfer(ctx context.Context, params TransferParams) (*solana.Signature, error) {
+	from := params.From
+	feePayer := from
+	if params.FeePayer != nil {
+		feePayer = *params.FeePayer
+	}
+
+	tx, err := t.BuildTransferTransaction(ctx, TransferTransactionParams{
+		From:       from.PublicKey(),
+		To:         params.To,
+		TokenMint:  params.TokenMint,
+		Amount:     params.Amount,
+		Memo:       params.Memo,
+		References: params.References,
+		FeePayer:   feePayer.PublicKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign transaction
+	if err := signTransaction(tx, from, feePayer); err != nil {
+		return nil, err
+	}
+
+	return t.sendTransacton(ctx, tx)
+}
+
+// BuildTransferTransaction returns the unsigned transfer transaction. It must
+// be signed by From and the fee payer.
+func (t *OnchainActionsTool) BuildTransferTransaction(ctx context.Context, params TransferTransactionParams) (*solana.Transaction, error) {
+	from := params.From
+	feePayer := params.FeePayer
+	if feePayer.IsZero() {
+		feePayer = from
+	}
+
 	var instructions []solana.Instruction
 
-	if tokenMint.Equals(WSOL_MINT) {
+	// Memo goes first so that it is recorded alongside the transfer
+	if params.Memo != "" {
+		memoIx, err := memo.NewMemoInstruction(
+			[]byte(params.Memo),
+			from,
+		).ValidateAndBuild()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create memo instruction: %w", err)
+		}
+		instructions = append(instructions, memoIx)
+	}
+
+	var transferIx solana.Instruction
+	if params.TokenMint.Equals(WSOL_MINT) {
 		// Native SOL transfer
-		transferIx := system.NewTransferInstruction(
-			amount,
-			from.PublicKey(),
-			to,
+		transferIx = system.NewTransferInstruction(
+			params.Amount,
+			from,
+			params.To,
 		).Build()
-		instructions = append(instructions, transferIx)
 	} else {
 		// SPL token transfer
-		fromATA, _, err := solana.FindAssociatedTokenAddress(from.PublicKey(), tokenMint)
+		fromATA, _, err := solana.FindAssociatedTokenAddress(from, params.TokenMint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find source associated token account: %w", err)
 		}
 
-		toATA, _, err := solana.FindAssociatedTokenAddress(to, tokenMint)
+		toATA, _, err := solana.FindAssociatedTokenAddress(params.To, params.TokenMint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find destination associated token account: %w", err)
 		}
@@ -46,9 +118,9 @@ func (t *OnchainActionsTool) Transfer(
 		if err != nil || err == rpc.ErrNotFound {
 			// Create destination ATA if it doesn't exist
 			createATAIx, err := associatedtokenaccount.NewCreateInstruction(
-				from.PublicKey(),
-				to,
-				tokenMint,
+				feePayer,
+				params.To,
+				params.TokenMint,
 			).ValidateAndBuild()
 			if err != nil {
 				return nil, fmt.Errorf("failed to create ATA instruction: %w", err)
@@ -57,19 +129,27 @@ func (t *OnchainActionsTool) Transfer(
 		}
 
 		// Transfer SPL token
-		transferIx, err := token.NewTransferInstruction(
-			amount,
+		transferIx, err = token.NewTransferInstruction(
+			params.Amount,
 			fromATA,
 			toATA,
-			from.PublicKey(),
+			from,
 			[]solana.PublicKey{},
 		).ValidateAndBuild()
 		if err != nil {
 			return nil, fmt.Errorf("failed to create transfer instruction: %w", err)
 		}
-		instructions = append(instructions, transferIx)
 	}
 
+	if len(params.References) > 0 {
+		var err error
+		transferIx, err = withReferences(transferIx, params.References)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add references: %w", err)
+		}
+	}
+	instructions = append(instructions, transferIx)
+
 	// Get recent blockhash
 	recent, err := t.rpcClient.GetRecentBlockhash(ctx, rpc.CommitmentFinalized)
 	if err != nil {
@@ -80,22 +160,53 @@ func (t *OnchainActionsTool) Transfer(
 	tx, err := solana.NewTransaction(
 		instructions,
 		recent.Value.Blockhash,
-		solana.TransactionPayer(from.PublicKey()),
+		solana.TransactionPayer(feePayer),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	// Sign transaction
-	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		if key.Equals(from.PublicKey()) {
-			return &from
-		}
-		return nil
-	})
+	return tx, nil
+}
+
+// parseTransferParams validates the transfer fields of the tool params. From
+// and FeePayer are left for the caller to fill in.
+func parseTransferParams(params Params) (TransferTransactionParams, error) {
+	if params.Destination == "" || params.TokenMint == "" || params.Amount <= 0 {
+		return TransferTransactionParams{}, fmt.Errorf("invalid transfer parameters")
+	}
+
+	destPubKey, err := solana.PublicKeyFromBase58(params.Destination)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		return TransferTransactionParams{}, fmt.Errorf("invalid destination address: %w", err)
 	}
 
-	return t.sendTransacton(ctx, tx)
+	tokenMint, err := solana.PublicKeyFromBase58(params.TokenMint)
+	if err != nil {
+		return TransferTransactionParams{}, fmt.Errorf("invalid token mint address: %w", err)
+	}
+
+	// Convert SOL to lamports (1 SOL = 1e9 lamports)
+	var lamports uint64
+	if tokenMint.Equals(WSOL_MINT) {
+		lamports = uint64(params.Amount * 1e9)
+	} else {
+		lamports = uint64(params.Amount)
+	}
+
+	references := make([]solana.PublicKey, len(params.References))
+	for i, reference := range params.References {
+		references[i], err = solana.PublicKeyFromBase58(reference)
+		if err != nil {
+			return TransferTransactionParams{}, fmt.Errorf("invalid reference address: %w", err)
+		}
+	}
+
+	return TransferTransactionParams{
+		To:         destPubKey,
+		TokenMint:  tokenMint,
+		Amount:     lamports,
+		Memo:       params.Memo,
+		References: references,
+	}, nil
 }
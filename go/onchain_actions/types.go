@@ -7,21 +7,59 @@ const (
 	ActionSell     Action = "sell"
 	ActionTransfer Action = "transfer"
 	ActionCreate   Action = "create"
+	ActionQuote    Action = "quote"
+	ActionSubmit   Action = "submit"
+	// ActionCreatorFees reports claimable pump.fun creator fees without a key
+	ActionCreatorFees      Action = "creator_fees"
+	ActionClaimCreatorFees Action = "claim_creator_fees"
+)
+
+// Mode selects whether the toolkit signs and sends transactions itself or
+// returns them unsigned for an external wallet
+type Mode string
+
+const (
+	ModeSigned   Mode = "signed"
+	ModeUnsigned Mode = "unsigned"
 )
 
 type Params struct {
-	Source      string  `json:"source"`
-	Destination string  `json:"destination"`
-	TokenMint   string  `json:"token_mint"`
-	Amount      float64 `json:"amount"`
+	Source   string `json:"source"`
+	FeePayer string `json:"fee_payer"`
+	// Owner and FeePayerAddress replace Source and FeePayer in unsigned mode
+	Owner           string  `json:"owner"`
+	FeePayerAddress string  `json:"fee_payer_address"`
+	Destination     string  `json:"destination"`
+	TokenMint       string  `json:"token_mint"`
+	Amount          float64 `json:"amount"`
+
+	// Swap params
+	Side        Side `json:"side"`
+	SlippageBps *int `json:"slippage_bps"`
+
+	// Transfer params
+	Memo       string   `json:"memo"`
+	References []string `json:"references"`
 
 	// Create params
 	TokenName   string `json:"token_name"`
 	TokenSymbol string `json:"token_symbol"`
+
+	// Submit params
+	SignedTransaction string `json:"signed_transaction"`
 }
 
+// Side selects the direction of a quote: buy spends SOL, sell spends the token
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
 type OnchainActionsInput struct {
 	Action Action `json:"action"`
+	Mode   Mode   `json:"mode"`
 	Params Params `json:"params"`
 }
 
@@ -29,3 +67,74 @@ type OnchainActionsOutput struct {
 	Signature   string  `json:"signature"`
 	MintAddress *string `json:"mint"`
 }
+
+// CreatorFeesOutput reports a creator's claimable pump.fun fees. The fees of
+// all tokens accrue in one vault, so ClaimableLamports covers every token
+// still on its bonding curve. Note says what the report leaves out.
+type CreatorFeesOutput struct {
+	Creator           string         `json:"creator"`
+	Vault             string         `json:"vault"`
+	ClaimableLamports uint64         `json:"claimable_lamports"`
+	ClaimableSol      float64        `json:"claimable_sol"`
+	Tokens            []CreatedToken `json:"tokens"`
+	Note              string         `json:"note"`
+}
+
+type CreatedToken struct {
+	Mint         string `json:"mint"`
+	BondingCurve string `json:"bonding_curve"`
+	Complete     bool   `json:"complete"`
+}
+
+// UnsignedTransactionOutput is returned by unsigned mode. Transaction is the
+// base64 wire transaction, RequiredSigners are the addresses that still need to sign.
+type UnsignedTransactionOutput struct {
+	Transaction     string   `json:"transaction"`
+	RequiredSigners []string `json:"required_signers"`
+	Summary         string   `json:"summary"`
+	MintAddress     *string  `json:"mint,omitempty"`
+}
+
+type QuoteSource string
+
+const (
+	QuoteSourceJupiter QuoteSource = "jupiter"
+	QuoteSourceRaydium QuoteSource = "raydium"
+)
+
+type QuoteOutput struct {
+	Source         QuoteSource      `json:"source"`
+	InputMint      string           `json:"input_mint"`
+	OutputMint     string           `json:"output_mint"`
+	InAmount       string           `json:"in_amount"`
+	InAmountUI     float64          `json:"in_amount_ui"`
+	OutAmount      string           `json:"out_amount"`
+	OutAmountUI    float64          `json:"out_amount_ui"`
+	MinOutAmount   string           `json:"min_out_amount"`
+	MinOutAmountUI float64          `json:"min_out_amount_ui"`
+	SlippageBps    int              `json:"slippage_bps"`
+	PriceImpactPct float64          `json:"price_impact_pct"`
+	RoutePlan      []QuoteRouteStep `json:"route_plan"`
+	PlatformFee    *QuoteFee        `json:"platform_fee,omitempty"`
+	// InValueUSD and OutValueUSD value the amounts at the oracle price, zero
+	// when the oracle has no price for the mint
+	InValueUSD  float64 `json:"in_value_usd,omitempty"`
+	OutValueUSD float64 `json:"out_value_usd,omitempty"`
+}
+
+type QuoteRouteStep struct {
+	AmmKey     string `json:"amm_key"`
+	Label      string `json:"label"`
+	InputMint  string `json:"input_mint"`
+	OutputMint string `json:"output_mint"`
+	InAmount   string `json:"in_amount"`
+	OutAmount  string `json:"out_amount"`
+	FeeAmount  string `json:"fee_amount"`
+	FeeMint    string `json:"fee_mint"`
+	Percent    int    `json:"percent"`
+}
+
+type QuoteFee struct {
+	Amount string `json:"amount"`
+	Mint   string `json:"mint"`
+}
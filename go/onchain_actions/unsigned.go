@@ -0,0 +1,192 @@
+package onchain_actions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+)
+
+// executeUnsigned builds the action's transaction for params.owner without
+// touching any private key
+func (t *OnchainActionsTool) executeUnsigned(ctx context.Context, input OnchainActionsInput) (json.RawMessage, error) {
+	if input.Params.Owner == "" {
+		return nil, fmt.Errorf("owner is required in unsigned mode")
+	}
+	owner, err := solana.PublicKeyFromBase58(input.Params.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner address: %w", err)
+	}
+
+	feePayer := owner
+	if input.Params.FeePayerAddress != "" {
+		feePayer, err = solana.PublicKeyFromBase58(input.Params.FeePayerAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee payer address: %w", err)
+		}
+	}
+
+	var (
+		tx          *solana.Transaction
+		summary     string
+		mintAddress *string
+	)
+
+	switch input.Action {
+	case ActionTransfer:
+		transfer, err := parseTransferParams(input.Params)
+		if err != nil {
+			return nil, err
+		}
+		transfer.From = owner
+		transfer.FeePayer = feePayer
+
+		tx, err = t.BuildTransferTransaction(ctx, transfer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transfer transaction: %w", err)
+		}
+
+		if transfer.TokenMint.Equals(WSOL_MINT) {
+			summary = fmt.Sprintf("Transfer %v SOL from %s to %s", input.Params.Amount, owner, transfer.To)
+		} else {
+			summary = fmt.Sprintf("Transfer %d of token %s from %s to %s", transfer.Amount, transfer.TokenMint, owner, transfer.To)
+		}
+
+	case ActionBuy, ActionSell:
+		quoteParams, err := t.BuildQuoteParams(ctx, Side(input.Action), input.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err = t.BuildSwapTransaction(ctx, quoteParams, owner, feePayer)
+		if err != nil {
+			return nil, err
+		}
+
+		slippageBps := defaultSlippageBps
+		if quoteParams.SlippageBps != nil {
+			slippageBps = *quoteParams.SlippageBps
+		}
+		if input.Action == ActionBuy {
+			summary = fmt.Sprintf("Buy token %s with %v SOL from %s, max slippage %d bps", input.Params.TokenMint, input.Params.Amount, owner, slippageBps)
+		} else {
+			summary = fmt.Sprintf("Sell %v of token %s for SOL from %s, max slippage %d bps", input.Params.Amount, input.Params.TokenMint, owner, slippageBps)
+		}
+
+	case ActionCreate:
+		if input.Params.TokenName == "" || input.Params.TokenSymbol == "" {
+			return nil, fmt.Errorf("invalid create parameters")
+		}
+
+		mintWallet := solana.NewWallet()
+		tx, err = t.BuildCreateTokenTransaction(ctx, CreateTokenTransactionParams{
+			TokenInfo: pumpfun.CreateTokenInformation{
+				Name:   input.Params.TokenName,
+				Symbol: input.Params.TokenSymbol,
+			},
+			Mint:            mintWallet,
+			User:            owner,
+			BuyAmount:       input.Params.Amount,
+			SlippagePercent: 10, // Default slippage
+			FeePayer:        feePayer,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		mint := mintWallet.PublicKey().String()
+		mintAddress = &mint
+
+		summary = fmt.Sprintf("Create pump.fun token %s (%s) with mint %s from %s", input.Params.TokenName, input.Params.TokenSymbol, mint, owner)
+		if input.Params.Amount > 0 {
+			summary += fmt.Sprintf(" and buy with %v SOL", input.Params.Amount)
+		}
+
+	case ActionClaimCreatorFees:
+		tx, err = t.BuildClaimCreatorFeesTransaction(ctx, owner, feePayer)
+		if err != nil {
+			return nil, err
+		}
+
+		summary = fmt.Sprintf("Claim pump.fun creator fees of %s", owner)
+
+	default:
+		return nil, fmt.Errorf("unsupported action in unsigned mode: %s", input.Action)
+	}
+
+	if !feePayer.Equals(owner) {
+		summary += fmt.Sprintf(", fees paid by %s", feePayer)
+	}
+
+	output, err := newUnsignedTransactionOutput(tx, summary)
+	if err != nil {
+		return nil, err
+	}
+	output.MintAddress = mintAddress
+
+	return json.Marshal(output)
+}
+
+// newUnsignedTransactionOutput encodes the transaction with empty signature
+// slots for every signer that hasn't signed yet
+func newUnsignedTransactionOutput(tx *solana.Transaction, summary string) (*UnsignedTransactionOutput, error) {
+	signers := tx.Message.Signers()
+	if len(tx.Signatures) == 0 {
+		tx.Signatures = make([]solana.Signature, len(signers))
+	}
+	if len(tx.Signatures) != len(signers) {
+		return nil, fmt.Errorf("invalid signatures length, expected %d, actual %d", len(signers), len(tx.Signatures))
+	}
+
+	requiredSigners := []string{}
+	for i, signer := range signers {
+		if tx.Signatures[i].IsZero() {
+			requiredSigners = append(requiredSigners, signer.String())
+		}
+	}
+
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	return &UnsignedTransactionOutput{
+		Transaction:     base64.StdEncoding.EncodeToString(data),
+		RequiredSigners: requiredSigners,
+		Summary:         summary,
+	}, nil
+}
+
+// SubmitTransaction sends a base64 transaction signed by an external wallet
+// and waits for it to confirm
+func (t *OnchainActionsTool) SubmitTransaction(ctx context.Context, signedTransaction string) (*solana.Signature, error) {
+	if signedTransaction == "" {
+		return nil, fmt.Errorf("signed transaction is required")
+	}
+
+	txBytes, err := base64.StdEncoding.DecodeString(signedTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 transaction: %w", err)
+	}
+
+	tx, err := solana.TransactionFromBytes(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+
+	if len(tx.Signatures) == 0 {
+		return nil, fmt.Errorf("transaction has no signatures")
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		return nil, fmt.Errorf("transaction is not fully signed: %w", err)
+	}
+
+	if record := auditRecordFromContext(ctx); record != nil {
+		record.Signer = tx.Message.Signers()[0].String()
+	}
+
+	return t.sendTransacton(ctx, tx)
+}
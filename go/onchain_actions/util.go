@@ -6,15 +6,25 @@ import (
 	"time"
 
 	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/soralabs/solana-toolkit/go/audit_log"
 )
 
 // sendTransacton sends a signed transaction to the Solana network
 func (t *OnchainActionsTool) sendTransacton(ctx context.Context, signedTx *solana.Transaction) (*solana.Signature, error) {
+	t.recordTransaction(ctx, signedTx)
+
 	sig, err := t.rpcClient.SendTransaction(ctx, signedTx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
+	record := auditRecordFromContext(ctx)
+	if record != nil {
+		record.Signature = sig.String()
+		record.Status = audit_log.StatusUnconfirmed
+	}
+
 	// Wait for confirmation with retries
 	deadline := time.Now().Add(30 * time.Second)
 
@@ -26,9 +36,15 @@ func (t *OnchainActionsTool) sendTransacton(ctx context.Context, signedTx *solan
 
 		if status.Value[0] != nil {
 			if status.Value[0].Err != nil {
+				if record != nil {
+					record.Status = audit_log.StatusFailed
+				}
 				return &sig, fmt.Errorf("transaction failed: %v", status.Value[0].Err)
 			}
 			if status.Value[0].Confirmations != nil && *status.Value[0].Confirmations > 0 {
+				if record != nil {
+					record.Status = audit_log.StatusConfirmed
+				}
 				return &sig, nil
 			}
 		}
@@ -38,3 +54,74 @@ func (t *OnchainActionsTool) sendTransacton(ctx context.Context, signedTx *solan
 
 	return &sig, fmt.Errorf("transaction confirmation timeout")
 }
+
+// signTransaction signs the transaction with every key it requires from keys
+func signTransaction(tx *solana.Transaction, keys ...solana.PrivateKey) error {
+	_, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		for i := range keys {
+			if keys[i].PublicKey().Equals(key) {
+				return &keys[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return nil
+}
+
+// withReferences rebuilds an instruction with the given public keys appended as
+// read-only, non-signer accounts
+func withReferences(ix solana.Instruction, references []solana.PublicKey) (solana.Instruction, error) {
+	data, err := ix.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instruction data: %w", err)
+	}
+
+	accounts := append(solana.AccountMetaSlice{}, ix.Accounts()...)
+	for _, reference := range references {
+		accounts = append(accounts, solana.Meta(reference))
+	}
+
+	return solana.NewInstruction(ix.ProgramID(), accounts, data), nil
+}
+
+// getComputeUnitPriceInstruction prices compute units at the mean recent
+// prioritization fee paid for the given writable accounts
+func (t *OnchainActionsTool) getComputeUnitPriceInstruction(ctx context.Context, accounts solana.PublicKeySlice) (*computebudget.SetComputeUnitPrice, error) {
+	out, err := t.rpcClient.GetRecentPrioritizationFees(ctx, accounts)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no recent prioritization fees")
+	}
+
+	var mean uint64
+	for _, fee := range out {
+		mean += fee.PrioritizationFee
+	}
+	mean /= uint64(len(out))
+
+	return computebudget.NewSetComputeUnitPriceInstruction(mean), nil
+}
+
+// getMintDecimals reads the decimals of a mint account
+func (t *OnchainActionsTool) getMintDecimals(ctx context.Context, mint solana.PublicKey) (uint8, error) {
+	if mint.Equals(WSOL_MINT) {
+		return 9, nil
+	}
+
+	account, err := t.rpcClient.GetAccountInfo(ctx, mint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mint account: %w", err)
+	}
+
+	data := account.Value.Data.GetBinary()
+	if len(data) < 45 {
+		return 0, fmt.Errorf("invalid mint account data")
+	}
+
+	return data[44], nil
+}
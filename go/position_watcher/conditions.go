@@ -0,0 +1,56 @@
+package position_watcher
+
+import "fmt"
+
+// Validate checks that the condition has the parameters its type needs
+func (c Condition) Validate() error {
+	switch c.Type {
+	case ConditionPriceAbove, ConditionPriceBelow:
+		if c.Price <= 0 {
+			return fmt.Errorf("%s requires a positive price", c.Type)
+		}
+	case ConditionPercentFromEntry:
+		if c.Percent == 0 || c.Percent <= -100 {
+			return fmt.Errorf("%s requires a non-zero percent above -100", c.Type)
+		}
+	case ConditionTrailingStop:
+		if c.Percent <= 0 || c.Percent >= 100 {
+			return fmt.Errorf("%s requires a percent between 0 and 100", c.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported condition: %s", c.Type)
+	}
+	return nil
+}
+
+// observe records a new price on the order and reports whether its
+// condition is met
+func (o *Order) observe(price float64) bool {
+	o.LastPrice = price
+	if price > o.HighPrice {
+		o.HighPrice = price
+	}
+
+	switch o.Condition.Type {
+	case ConditionPriceAbove:
+		return price >= o.Condition.Price
+	case ConditionPriceBelow:
+		return price <= o.Condition.Price
+	case ConditionPercentFromEntry:
+		if o.EntryPrice <= 0 {
+			return false
+		}
+		change := (price - o.EntryPrice) / o.EntryPrice * 100
+		if o.Condition.Percent > 0 {
+			return change >= o.Condition.Percent
+		}
+		return change <= o.Condition.Percent
+	case ConditionTrailingStop:
+		if o.HighPrice <= 0 {
+			return false
+		}
+		drawdown := (o.HighPrice - price) / o.HighPrice * 100
+		return drawdown >= o.Condition.Percent
+	}
+	return false
+}
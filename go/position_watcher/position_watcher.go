@@ -0,0 +1,176 @@
+package position_watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	toolkit "github.com/soralabs/toolkit/go"
+)
+
+// PositionWatcherTool exposes a running Watcher to agents so they can place,
+// cancel and list take-profit and stop-loss orders
+type PositionWatcherTool struct {
+	toolkit.Tool
+
+	watcher *Watcher
+}
+
+func NewPositionWatcherTool(watcher *Watcher) (*PositionWatcherTool, error) {
+	if watcher == nil {
+		return nil, fmt.Errorf("watcher is required")
+	}
+
+	return &PositionWatcherTool{
+		watcher: watcher,
+	}, nil
+}
+
+func (t *PositionWatcherTool) GetName() string {
+	return "position_watcher"
+}
+
+func (t *PositionWatcherTool) GetDescription() string {
+	return "Place, cancel and list take-profit, stop-loss and trailing-stop sell orders on token positions."
+}
+
+func (t *PositionWatcherTool) GetSchema() toolkit.Schema {
+	return toolkit.Schema{
+		Parameters: json.RawMessage(`{
+            "type": "object",
+            "required": ["action"],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "description": "The order action to perform",
+                    "enum": ["place", "cancel", "list"]
+                },
+                "id": {
+                    "type": "string",
+                    "description": "Order id, when the action is cancel"
+                },
+                "source": {
+                    "type": "string",
+                    "description": "Private key of the wallet, when the action is place and the watcher has no signer for it yet. It is held in memory only"
+                },
+                "wallet": {
+                    "type": "string",
+                    "description": "Wallet address that holds the position, when the action is place with a registered signer. Optional filter for list"
+                },
+                "token_mint": {
+                    "type": "string",
+                    "description": "Token mint address of the position, when the action is place"
+                },
+                "condition": {
+                    "type": "string",
+                    "description": "When to sell: price_above or price_below a price in SOL, percent_from_entry (e.g. -30 to sell after a 30% drop, 50 to take profit at +50%), or trailing_stop a percent below the highest price",
+                    "enum": ["price_above", "price_below", "percent_from_entry", "trailing_stop"]
+                },
+                "price": {
+                    "type": "number",
+                    "description": "Price level in SOL per token for price_above and price_below"
+                },
+                "percent": {
+                    "type": "number",
+                    "description": "Percent move for percent_from_entry and trailing_stop"
+                },
+                "amount": {
+                    "type": "number",
+                    "description": "Number of tokens to sell when triggered, omit to sell the whole position"
+                },
+                "entry_price": {
+                    "type": "number",
+                    "description": "Entry price in SOL per token, defaults to the current price"
+                },
+                "slippage_bps": {
+                    "type": "integer",
+                    "description": "Slippage tolerance in basis points for the sell"
+                }
+            }
+        }`),
+	}
+}
+
+func (t *PositionWatcherTool) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	var input PositionWatcherInput
+	if err := json.Unmarshal(params, &input); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	switch input.Action {
+	case "place":
+		// A source key lets the watcher sign for the wallet, it is never persisted
+		var wallet solana.PublicKey
+		if input.Source != "" {
+			privateKey, err := solana.PrivateKeyFromBase58(input.Source)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse private key: %w", err)
+			}
+			t.watcher.AddSigner(privateKey)
+			wallet = privateKey.PublicKey()
+		} else {
+			var err error
+			wallet, err = solana.PublicKeyFromBase58(input.Wallet)
+			if err != nil {
+				return nil, fmt.Errorf("invalid wallet address: %w", err)
+			}
+		}
+		// Without a signer the order would trigger and never sell
+		if !t.watcher.HasSigner(wallet) {
+			return nil, fmt.Errorf("no signer registered for wallet %s, provide its source key", wallet)
+		}
+
+		mint, err := solana.PublicKeyFromBase58(input.TokenMint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token mint address: %w", err)
+		}
+
+		order, err := t.watcher.Place(ctx, Order{
+			Wallet: wallet,
+			Mint:   mint,
+			Condition: Condition{
+				Type:    ConditionType(input.Condition),
+				Price:   input.Price,
+				Percent: input.Percent,
+			},
+			Amount:      input.Amount,
+			EntryPrice:  input.EntryPrice,
+			SlippageBps: input.SlippageBps,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to place order: %w", err)
+		}
+
+		return json.Marshal(PositionWatcherOutput{Orders: []Order{*order}})
+
+	case "cancel":
+		if err := t.watcher.Cancel(input.ID); err != nil {
+			return nil, fmt.Errorf("failed to cancel order: %w", err)
+		}
+
+		var cancelled []Order
+		for _, order := range t.watcher.Orders(nil) {
+			if order.ID == input.ID {
+				cancelled = append(cancelled, order)
+			}
+		}
+
+		return json.Marshal(PositionWatcherOutput{Orders: cancelled})
+
+	case "list":
+		var wallet *solana.PublicKey
+		if input.Wallet != "" {
+			pubkey, err := solana.PublicKeyFromBase58(input.Wallet)
+			if err != nil {
+				return nil, fmt.Errorf("invalid wallet address: %w", err)
+			}
+			wallet = &pubkey
+		}
+
+		return json.Marshal(PositionWatcherOutput{Orders: t.watcher.Orders(wallet)})
+
+	default:
+		return nil, fmt.Errorf("unsupported action: %s", input.Action)
+	}
+}
@@ -0,0 +1,307 @@
+package position_watcher
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/ilkamo/jupiter-go/jupiter"
+)
+
+type fakePrices map[solana.PublicKey]float64
+
+func (p fakePrices) GetPrice(ctx context.Context, mint solana.PublicKey) (float64, error) {
+	return p[mint], nil
+}
+
+type fakeSeller struct {
+	requests []jupiter.GetQuoteParams
+	// swap, when set, replaces the successful default
+	swap func() (*solana.Signature, error)
+}
+
+func (s *fakeSeller) Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error) {
+	s.requests = append(s.requests, quoteRequest)
+	if s.swap != nil {
+		return s.swap()
+	}
+	return &solana.Signature{1}, nil
+}
+
+func newTestWatcher(t *testing.T, path string, prices fakePrices, seller *fakeSeller, opts ...Option) *Watcher {
+	w, err := New(nil, seller, prices, path, opts...)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	w.tokenBalance = func(ctx context.Context, wallet, mint solana.PublicKey) (uint64, uint8, error) {
+		return 5_000_000, 6, nil
+	}
+	return w
+}
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  Order
+		prices []float64
+		want   []bool
+	}{
+		{
+			name:   "price above",
+			order:  Order{Condition: Condition{Type: ConditionPriceAbove, Price: 2}},
+			prices: []float64{1, 2},
+			want:   []bool{false, true},
+		},
+		{
+			name:   "price below",
+			order:  Order{Condition: Condition{Type: ConditionPriceBelow, Price: 0.5}},
+			prices: []float64{1, 0.4},
+			want:   []bool{false, true},
+		},
+		{
+			name:   "stop loss from entry",
+			order:  Order{EntryPrice: 1, Condition: Condition{Type: ConditionPercentFromEntry, Percent: -30}},
+			prices: []float64{0.8, 0.7},
+			want:   []bool{false, true},
+		},
+		{
+			name:   "take profit from entry",
+			order:  Order{EntryPrice: 1, Condition: Condition{Type: ConditionPercentFromEntry, Percent: 50}},
+			prices: []float64{1.4, 1.5},
+			want:   []bool{false, true},
+		},
+		{
+			name:   "trailing stop follows the high",
+			order:  Order{HighPrice: 1, Condition: Condition{Type: ConditionTrailingStop, Percent: 20}},
+			prices: []float64{2, 1.7, 1.5},
+			want:   []bool{false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.order.Condition.Validate(); err != nil {
+				t.Fatalf("invalid condition: %v", err)
+			}
+			for i, price := range tt.prices {
+				if got := tt.order.observe(price); got != tt.want[i] {
+					t.Errorf("price %f: expected %v, got %v", price, tt.want[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestWatcherTriggersAndPersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "orders.json")
+
+	signer := solana.NewWallet().PrivateKey
+	mint := solana.NewWallet().PublicKey()
+	prices := fakePrices{mint: 1}
+	seller := &fakeSeller{}
+
+	w := newTestWatcher(t, path, prices, seller)
+	order, err := w.Place(ctx, Order{
+		Wallet:    signer.PublicKey(),
+		Mint:      mint,
+		Amount:    2,
+		Condition: Condition{Type: ConditionPercentFromEntry, Percent: -30},
+	})
+	if err != nil {
+		t.Fatalf("failed to place order: %v", err)
+	}
+
+	// Without a signer the order triggers but stays active for a retry
+	prices[mint] = 0.6
+	w.Poll(ctx)
+	if got := w.Orders(nil)[0]; got.Status != OrderStatusActive || got.LastError == "" {
+		t.Fatalf("expected active order with error, got %s %q", got.Status, got.LastError)
+	}
+
+	// Orders survive a restart, signers don't
+	w = newTestWatcher(t, path, prices, seller, WithSigners(signer))
+	w.Poll(ctx)
+
+	got := w.Orders(&order.Wallet)
+	if len(got) != 1 || got[0].Status != OrderStatusFilled {
+		t.Fatalf("expected one filled order, got %+v", got)
+	}
+	if len(seller.requests) != 1 {
+		t.Fatalf("expected one sell, got %d", len(seller.requests))
+	}
+
+	request := seller.requests[0]
+	if request.InputMint != mint.String() || request.OutputMint != solana.SolMint.String() {
+		t.Errorf("unexpected sell route %s -> %s", request.InputMint, request.OutputMint)
+	}
+	if request.Amount != 2_000_000 {
+		t.Errorf("expected 2 tokens in base units, got %d", request.Amount)
+	}
+
+	// Filled orders are not sold again
+	w.Poll(ctx)
+	if len(seller.requests) != 1 {
+		t.Errorf("expected no further sells, got %d", len(seller.requests))
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx := context.Background()
+	mint := solana.NewWallet().PublicKey()
+	seller := &fakeSeller{}
+
+	w := newTestWatcher(t, filepath.Join(t.TempDir(), "orders.json"), fakePrices{mint: 1}, seller)
+	order, err := w.Place(ctx, Order{
+		Wallet:    solana.NewWallet().PublicKey(),
+		Mint:      mint,
+		Condition: Condition{Type: ConditionPriceAbove, Price: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("failed to place order: %v", err)
+	}
+
+	if err := w.Cancel(order.ID); err != nil {
+		t.Fatalf("failed to cancel order: %v", err)
+	}
+	w.Poll(ctx)
+
+	if len(seller.requests) != 0 {
+		t.Errorf("expected cancelled order not to sell")
+	}
+	if err := w.Cancel(order.ID); err == nil {
+		t.Error("expected error cancelling a cancelled order")
+	}
+}
+
+func TestCancelDuringFailingSell(t *testing.T) {
+	ctx := context.Background()
+	signer := solana.NewWallet().PrivateKey
+	mint := solana.NewWallet().PublicKey()
+	seller := &fakeSeller{}
+
+	w := newTestWatcher(t, filepath.Join(t.TempDir(), "orders.json"), fakePrices{mint: 1}, seller, WithSigners(signer))
+	order, err := w.Place(ctx, Order{
+		Wallet:    signer.PublicKey(),
+		Mint:      mint,
+		Condition: Condition{Type: ConditionPriceAbove, Price: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("failed to place order: %v", err)
+	}
+
+	seller.swap = func() (*solana.Signature, error) {
+		if err := w.Cancel(order.ID); err != nil {
+			t.Errorf("failed to cancel triggered order: %v", err)
+		}
+		return nil, errors.New("no route")
+	}
+	w.Poll(ctx)
+
+	got := w.Orders(nil)[0]
+	if got.Status != OrderStatusCancelled || got.LastError == "" {
+		t.Fatalf("expected cancelled order with error, got %s %q", got.Status, got.LastError)
+	}
+
+	w.Poll(ctx)
+	if len(seller.requests) != 1 {
+		t.Errorf("expected cancelled order not to be retried, got %d sells", len(seller.requests))
+	}
+}
+
+func TestReconcileTriggered(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "orders.json")
+	mint := solana.NewWallet().PublicKey()
+	prices := fakePrices{mint: 1}
+	seller := &fakeSeller{}
+
+	// Orders left triggered by a crash: before sending, after a sell landed,
+	// after a sell failed, and after sells the cluster never saw
+	w := newTestWatcher(t, path, prices, seller)
+	landed, failed, lost := solana.Signature{1}, solana.Signature{2}, solana.Signature{3}
+	recent, expired := time.Now().UTC(), time.Now().UTC().Add(-time.Hour)
+	orders := map[string]Order{
+		"unsent":  {},
+		"landed":  {Signature: landed.String(), TriggeredAt: &recent},
+		"failed":  {Signature: failed.String(), TriggeredAt: &recent},
+		"pending": {Signature: lost.String(), TriggeredAt: &recent},
+		"expired": {Signature: lost.String(), TriggeredAt: &expired},
+	}
+	for name, order := range orders {
+		order.ID = name
+		order.Wallet = solana.NewWallet().PublicKey()
+		order.Mint = mint
+		order.Status = OrderStatusTriggered
+		order.Condition = Condition{Type: ConditionPriceAbove, Price: 10}
+		w.orders[name] = &order
+	}
+	if err := w.save(); err != nil {
+		t.Fatalf("failed to save orders: %v", err)
+	}
+
+	w = newTestWatcher(t, path, prices, seller)
+	w.signatureStatus = func(ctx context.Context, sig solana.Signature) (*rpc.SignatureStatusesResult, error) {
+		switch sig {
+		case landed:
+			return &rpc.SignatureStatusesResult{ConfirmationStatus: rpc.ConfirmationStatusFinalized}, nil
+		case failed:
+			return &rpc.SignatureStatusesResult{Err: "InstructionError"}, nil
+		}
+		return nil, nil
+	}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatalf("failed to poll: %v", err)
+	}
+
+	want := map[string]OrderStatus{
+		"unsent":  OrderStatusActive,
+		"landed":  OrderStatusFilled,
+		"failed":  OrderStatusActive,
+		"pending": OrderStatusTriggered,
+		"expired": OrderStatusActive,
+	}
+	for _, order := range w.Orders(nil) {
+		if order.Status != want[order.ID] {
+			t.Errorf("%s order is %s, want %s", order.ID, order.Status, want[order.ID])
+		}
+	}
+	if len(seller.requests) != 0 {
+		t.Errorf("expected no sells, got %d", len(seller.requests))
+	}
+
+	// A stuck order can be cancelled
+	if err := w.Cancel("pending"); err != nil {
+		t.Errorf("failed to cancel triggered order: %v", err)
+	}
+}
+
+func TestInvalidInterval(t *testing.T) {
+	if _, err := New(nil, &fakeSeller{}, fakePrices{}, filepath.Join(t.TempDir(), "orders.json"), WithInterval(0)); err == nil {
+		t.Error("expected error for a zero poll interval")
+	}
+}
+
+func TestPlaceRequiresSigner(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	w := newTestWatcher(t, filepath.Join(t.TempDir(), "orders.json"), fakePrices{mint: 1}, &fakeSeller{})
+	tool, err := NewPositionWatcherTool(w)
+	if err != nil {
+		t.Fatalf("failed to create tool: %v", err)
+	}
+
+	params := []byte(`{"action": "place", "wallet": "` + solana.NewWallet().PublicKey().String() + `", "token_mint": "` + mint.String() + `", "condition": "price_above", "price": 2}`)
+	if _, err := tool.Execute(context.Background(), params); err == nil {
+		t.Error("placed an order no signer can sell")
+	}
+
+	signer := solana.NewWallet().PrivateKey
+	params = []byte(`{"action": "place", "source": "` + signer.String() + `", "token_mint": "` + mint.String() + `", "condition": "price_above", "price": 2}`)
+	if _, err := tool.Execute(context.Background(), params); err != nil {
+		t.Errorf("failed to place order with a source key: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package position_watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/onchain_actions"
+)
+
+// PriceSource returns the current price of a token in SOL per whole token
+type PriceSource interface {
+	GetPrice(ctx context.Context, mint solana.PublicKey) (float64, error)
+}
+
+// QuotePriceSource prices tokens by quoting a small SOL buy, so prices come
+// from the same routes the watcher sells through
+type QuotePriceSource struct {
+	tool     *onchain_actions.OnchainActionsTool
+	probeSol float64
+}
+
+// NewQuotePriceSource creates a price source that quotes buying probeSol SOL
+// worth of the token. A probe of 0 defaults to 0.01 SOL.
+func NewQuotePriceSource(tool *onchain_actions.OnchainActionsTool, probeSol float64) *QuotePriceSource {
+	if probeSol <= 0 {
+		probeSol = 0.01
+	}
+	return &QuotePriceSource{tool: tool, probeSol: probeSol}
+}
+
+func (s *QuotePriceSource) GetPrice(ctx context.Context, mint solana.PublicKey) (float64, error) {
+	quoteParams, err := s.tool.BuildQuoteParams(ctx, onchain_actions.SideBuy, onchain_actions.Params{
+		TokenMint: mint.String(),
+		Amount:    s.probeSol,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	quote, err := s.tool.Quote(ctx, quoteParams)
+	if err != nil {
+		return 0, err
+	}
+
+	if quote.OutAmountUI <= 0 {
+		return 0, fmt.Errorf("quote for %s returned no tokens", mint)
+	}
+
+	return s.probeSol / quote.OutAmountUI, nil
+}
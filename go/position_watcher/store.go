@@ -0,0 +1,64 @@
+package position_watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStore persists orders as a JSON file so they survive restarts
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads all orders, returning none if the file does not exist yet
+func (s *FileStore) Load() ([]Order, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read orders file: %w", err)
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, fmt.Errorf("failed to parse orders file: %w", err)
+	}
+
+	return orders, nil
+}
+
+// Save replaces the file atomically so a crash never leaves it half written
+func (s *FileStore) Save(orders []Order) error {
+	data, err := json.MarshalIndent(orders, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal orders: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary orders file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write orders file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write orders file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace orders file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package position_watcher
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+type ConditionType string
+
+const (
+	// ConditionPriceAbove triggers when the price rises to or above Price
+	ConditionPriceAbove ConditionType = "price_above"
+	// ConditionPriceBelow triggers when the price falls to or below Price
+	ConditionPriceBelow ConditionType = "price_below"
+	// ConditionPercentFromEntry triggers when the price has moved Percent from
+	// the entry price. Positive values take profit, negative values stop losses.
+	ConditionPercentFromEntry ConditionType = "percent_from_entry"
+	// ConditionTrailingStop triggers when the price falls Percent below the
+	// highest price seen since the order was placed
+	ConditionTrailingStop ConditionType = "trailing_stop"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusActive    OrderStatus = "active"
+	OrderStatusTriggered OrderStatus = "triggered"
+	OrderStatusFilled    OrderStatus = "filled"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+type Condition struct {
+	Type ConditionType `json:"type"`
+
+	// Price is the level in SOL per token for price_above and price_below
+	Price float64 `json:"price,omitempty"`
+	// Percent is the move for percent_from_entry and trailing_stop, e.g. -30 or 30
+	Percent float64 `json:"percent,omitempty"`
+}
+
+// Order is a conditional sell of a wallet's position in a mint. Orders never
+// hold private keys; signers are registered with the watcher in memory.
+type Order struct {
+	ID     string           `json:"id"`
+	Wallet solana.PublicKey `json:"wallet"`
+	Mint   solana.PublicKey `json:"mint"`
+
+	Condition Condition `json:"condition"`
+
+	// Amount is the number of tokens to sell, zero sells the whole balance
+	Amount      float64 `json:"amount"`
+	SlippageBps int     `json:"slippage_bps"`
+
+	// EntryPrice and HighPrice are in SOL per token
+	EntryPrice float64 `json:"entry_price"`
+	HighPrice  float64 `json:"high_price"`
+	LastPrice  float64 `json:"last_price"`
+
+	Status      OrderStatus `json:"status"`
+	Signature   string      `json:"signature,omitempty"`
+	LastError   string      `json:"last_error,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	TriggeredAt *time.Time  `json:"triggered_at,omitempty"`
+	FilledAt    *time.Time  `json:"filled_at,omitempty"`
+}
+
+type PositionWatcherInput struct {
+	Action      string  `json:"action"`
+	ID          string  `json:"id"`
+	Source      string  `json:"source"`
+	Wallet      string  `json:"wallet"`
+	TokenMint   string  `json:"token_mint"`
+	Condition   string  `json:"condition"`
+	Price       float64 `json:"price"`
+	Percent     float64 `json:"percent"`
+	Amount      float64 `json:"amount"`
+	EntryPrice  float64 `json:"entry_price"`
+	SlippageBps int     `json:"slippage_bps"`
+}
+
+type PositionWatcherOutput struct {
+	Orders []Order `json:"orders"`
+}
@@ -0,0 +1,502 @@
+package position_watcher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/ilkamo/jupiter-go/jupiter"
+)
+
+// Seller executes the sell once an order triggers. OnchainActionsTool satisfies it.
+type Seller interface {
+	Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error)
+}
+
+// Watcher holds conditional sell orders per wallet and mint, polls prices
+// and sells through the Seller when a condition is met
+type Watcher struct {
+	mu sync.Mutex
+
+	rpcClient *rpc.Client
+	seller    Seller
+	prices    PriceSource
+	store     *FileStore
+
+	orders  map[string]*Order
+	signers map[solana.PublicKey]solana.PrivateKey
+
+	interval           time.Duration
+	defaultSlippageBps int
+
+	// tokenBalance returns the raw balance and decimals of a wallet's token account
+	tokenBalance func(ctx context.Context, wallet, mint solana.PublicKey) (uint64, uint8, error)
+	// signatureStatus returns the status of a sell, nil if the cluster has none
+	signatureStatus func(ctx context.Context, sig solana.Signature) (*rpc.SignatureStatusesResult, error)
+}
+
+// sendExpiry is how long after triggering a sell that the cluster has no
+// record of is given up on. Its blockhash has expired by then so it can no
+// longer land.
+const sendExpiry = 2 * time.Minute
+
+type Option func(*Watcher)
+
+// WithInterval sets how often prices are polled
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithDefaultSlippageBps sets the slippage used by orders that don't set one
+func WithDefaultSlippageBps(slippageBps int) Option {
+	return func(w *Watcher) {
+		w.defaultSlippageBps = slippageBps
+	}
+}
+
+// WithSigners registers the keys allowed to sign triggered orders
+func WithSigners(keys ...solana.PrivateKey) Option {
+	return func(w *Watcher) {
+		for _, key := range keys {
+			w.signers[key.PublicKey()] = key
+		}
+	}
+}
+
+// New creates a watcher whose orders are persisted to path. Orders left
+// triggered by a previous run are reconciled on the first poll.
+func New(rpcClient *rpc.Client, seller Seller, prices PriceSource, path string, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		rpcClient:          rpcClient,
+		seller:             seller,
+		prices:             prices,
+		store:              NewFileStore(path),
+		orders:             make(map[string]*Order),
+		signers:            make(map[solana.PublicKey]solana.PrivateKey),
+		interval:           10 * time.Second,
+		defaultSlippageBps: 100,
+	}
+	w.tokenBalance = w.getTokenBalance
+	w.signatureStatus = w.getSignatureStatus
+
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.interval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+
+	orders, err := w.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		w.orders[orders[i].ID] = &orders[i]
+	}
+
+	return w, nil
+}
+
+// AddSigner registers a key that may sign sells for its wallet. Keys are only
+// held in memory and must be registered again after a restart.
+func (w *Watcher) AddSigner(key solana.PrivateKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.signers[key.PublicKey()] = key
+}
+
+// HasSigner reports whether triggered orders of wallet can be signed
+func (w *Watcher) HasSigner(wallet solana.PublicKey) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.signers[wallet]
+	return ok
+}
+
+// Place validates and stores a new active order. If the order has no entry
+// price the current price is used.
+func (w *Watcher) Place(ctx context.Context, order Order) (*Order, error) {
+	if err := order.Condition.Validate(); err != nil {
+		return nil, err
+	}
+	if order.Wallet.IsZero() || order.Mint.IsZero() {
+		return nil, fmt.Errorf("wallet and mint are required")
+	}
+	if order.Amount < 0 {
+		return nil, fmt.Errorf("amount cannot be negative")
+	}
+
+	price, err := w.prices.GetPrice(ctx, order.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current price: %w", err)
+	}
+
+	id, err := newOrderID()
+	if err != nil {
+		return nil, err
+	}
+
+	order.ID = id
+	order.Status = OrderStatusActive
+	order.CreatedAt = time.Now().UTC()
+	order.LastPrice = price
+	order.HighPrice = price
+	if order.EntryPrice <= 0 {
+		order.EntryPrice = price
+	}
+	if order.SlippageBps <= 0 {
+		order.SlippageBps = w.defaultSlippageBps
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.orders[order.ID] = &order
+	if err := w.save(); err != nil {
+		delete(w.orders, order.ID)
+		return nil, err
+	}
+
+	placed := order
+	return &placed, nil
+}
+
+// Cancel stops an active order from triggering, or a triggered order from
+// being retried. A sell that was already sent may still land.
+func (w *Watcher) Cancel(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	order, ok := w.orders[id]
+	if !ok {
+		return fmt.Errorf("order %s not found", id)
+	}
+	if order.Status != OrderStatusActive && order.Status != OrderStatusTriggered {
+		return fmt.Errorf("order %s is %s", id, order.Status)
+	}
+
+	previous := order.Status
+	order.Status = OrderStatusCancelled
+	if err := w.save(); err != nil {
+		order.Status = previous
+		return err
+	}
+	return nil
+}
+
+// Orders returns all orders, optionally limited to one wallet, oldest first
+func (w *Watcher) Orders(wallet *solana.PublicKey) []Order {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	orders := make([]Order, 0, len(w.orders))
+	for _, order := range w.orders {
+		if wallet != nil && !order.Wallet.Equals(*wallet) {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+
+	return orders
+}
+
+// Run polls prices until the context is cancelled or orders can no longer
+// be persisted
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll reconciles triggered orders, fetches one price per watched mint,
+// updates every active order and sells the ones whose condition is met. It
+// fails only when orders can't be persisted.
+func (w *Watcher) Poll(ctx context.Context) error {
+	if err := w.reconcile(ctx); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	mints := make(map[solana.PublicKey]bool)
+	for _, order := range w.orders {
+		if order.Status == OrderStatusActive {
+			mints[order.Mint] = true
+		}
+	}
+	w.mu.Unlock()
+
+	prices := make(map[solana.PublicKey]float64)
+	for mint := range mints {
+		price, err := w.prices.GetPrice(ctx, mint)
+		if err != nil {
+			continue
+		}
+		prices[mint] = price
+	}
+
+	w.mu.Lock()
+	var triggered []*Order
+	now := time.Now().UTC()
+	for _, order := range w.orders {
+		price, ok := prices[order.Mint]
+		if order.Status != OrderStatusActive || !ok {
+			continue
+		}
+		if order.observe(price) {
+			order.Status = OrderStatusTriggered
+			order.TriggeredAt = &now
+			triggered = append(triggered, order)
+		}
+	}
+	if err := w.save(); err != nil {
+		// Don't sell what a restart wouldn't know was sold
+		for _, order := range triggered {
+			order.Status = OrderStatusActive
+			order.TriggeredAt = nil
+		}
+		w.mu.Unlock()
+		return err
+	}
+	w.mu.Unlock()
+
+	var errs []error
+	for _, order := range triggered {
+		if err := w.execute(ctx, order); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reconcile settles triggered orders left by a previous poll or run. Orders
+// without a signature never sent their sell and go back to active. Sent
+// sells are checked on chain: landed ones fill, failed or expired ones go
+// back to active.
+func (w *Watcher) reconcile(ctx context.Context) error {
+	w.mu.Lock()
+	var pending []*Order
+	for _, order := range w.orders {
+		if order.Status == OrderStatusTriggered {
+			pending = append(pending, order)
+		}
+	}
+	w.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	type result struct {
+		status *rpc.SignatureStatusesResult
+		err    error
+	}
+	results := make(map[string]result, len(pending))
+	for _, order := range pending {
+		if order.Signature == "" {
+			continue
+		}
+		sig, err := solana.SignatureFromBase58(order.Signature)
+		if err != nil {
+			results[order.ID] = result{err: fmt.Errorf("invalid signature: %w", err)}
+			continue
+		}
+		status, err := w.signatureStatus(ctx, sig)
+		results[order.ID] = result{status: status, err: err}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, order := range pending {
+		if order.Status != OrderStatusTriggered {
+			continue
+		}
+		if order.Signature == "" {
+			order.Status = OrderStatusActive
+			order.TriggeredAt = nil
+			continue
+		}
+
+		result := results[order.ID]
+		switch {
+		case result.err != nil:
+			// Unknown, check again on the next poll
+			order.LastError = result.err.Error()
+		case result.status == nil:
+			if order.TriggeredAt != nil && now.Sub(*order.TriggeredAt) > sendExpiry {
+				order.Status = OrderStatusActive
+				order.TriggeredAt = nil
+				order.LastError = fmt.Sprintf("sell %s never landed", order.Signature)
+				order.Signature = ""
+			}
+		case result.status.Err != nil:
+			order.Status = OrderStatusActive
+			order.TriggeredAt = nil
+			order.LastError = fmt.Sprintf("sell %s failed: %v", order.Signature, result.status.Err)
+			order.Signature = ""
+		case result.status.ConfirmationStatus == rpc.ConfirmationStatusConfirmed ||
+			result.status.ConfirmationStatus == rpc.ConfirmationStatusFinalized:
+			order.Status = OrderStatusFilled
+			order.FilledAt = &now
+			order.LastError = ""
+		}
+	}
+
+	return w.save()
+}
+
+// execute sells a triggered order. Orders that fail before a transaction is
+// sent go back to active so the next poll retries them, unless they were
+// cancelled in the meantime.
+func (w *Watcher) execute(ctx context.Context, order *Order) error {
+	w.mu.Lock()
+	signer, hasSigner := w.signers[order.Wallet]
+	snapshot := *order
+	w.mu.Unlock()
+
+	var (
+		sig *solana.Signature
+		err error
+	)
+	if !hasSigner {
+		err = fmt.Errorf("no signer registered for wallet %s", snapshot.Wallet)
+	} else {
+		var amount uint64
+		amount, err = w.sellAmount(ctx, snapshot)
+		if err == nil {
+			slippageBps := snapshot.SlippageBps
+			sig, err = w.seller.Swap(ctx, jupiter.GetQuoteParams{
+				InputMint:   snapshot.Mint.String(),
+				OutputMint:  solana.SolMint.String(),
+				Amount:      jupiter.AmountParameter(amount),
+				SlippageBps: &slippageBps,
+			}, signer)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if sig != nil {
+		order.Signature = sig.String()
+	}
+	switch {
+	case err == nil:
+		now := time.Now().UTC()
+		order.Status = OrderStatusFilled
+		order.FilledAt = &now
+		order.LastError = ""
+	case sig != nil:
+		// The transaction was sent but not confirmed, don't risk selling
+		// twice. reconcile settles it once the cluster knows its status.
+		order.LastError = err.Error()
+	case order.Status == OrderStatusCancelled:
+		// Cancelled while the sell was being built, don't revive it
+		order.LastError = err.Error()
+	default:
+		order.Status = OrderStatusActive
+		order.TriggeredAt = nil
+		order.LastError = err.Error()
+	}
+	return w.save()
+}
+
+// sellAmount converts the order amount to base units, capped at the balance
+func (w *Watcher) sellAmount(ctx context.Context, order Order) (uint64, error) {
+	balance, decimals, err := w.tokenBalance(ctx, order.Wallet, order.Mint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token balance: %w", err)
+	}
+	if balance == 0 {
+		return 0, fmt.Errorf("wallet %s holds no %s", order.Wallet, order.Mint)
+	}
+
+	if order.Amount == 0 {
+		return balance, nil
+	}
+
+	amount := uint64(math.Round(order.Amount * math.Pow10(int(decimals))))
+	if amount > balance {
+		amount = balance
+	}
+	return amount, nil
+}
+
+func (w *Watcher) getTokenBalance(ctx context.Context, wallet, mint solana.PublicKey) (uint64, uint8, error) {
+	ata, _, err := solana.FindAssociatedTokenAddress(wallet, mint)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	balance, err := w.rpcClient.GetTokenAccountBalance(ctx, ata, rpc.CommitmentConfirmed)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	amount, err := strconv.ParseUint(balance.Value.Amount, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid token balance: %w", err)
+	}
+
+	return amount, balance.Value.Decimals, nil
+}
+
+func (w *Watcher) getSignatureStatus(ctx context.Context, sig solana.Signature) (*rpc.SignatureStatusesResult, error) {
+	statuses, err := w.rpcClient.GetSignatureStatuses(ctx, true, sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sell status: %w", err)
+	}
+	if len(statuses.Value) == 0 {
+		return nil, nil
+	}
+	return statuses.Value[0], nil
+}
+
+// save persists all orders, callers must hold w.mu
+func (w *Watcher) save() error {
+	orders := make([]Order, 0, len(w.orders))
+	for _, order := range w.orders {
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+
+	return w.store.Save(orders)
+}
+
+func newOrderID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate order id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
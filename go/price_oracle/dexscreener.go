@@ -0,0 +1,42 @@
+package price_oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/dexscreener"
+)
+
+// DexscreenerSource prices tokens from their deepest Solana pair on
+// Dexscreener, in USD and in SOL when the pair quotes in SOL
+type DexscreenerSource struct{}
+
+func NewDexscreenerSource() *DexscreenerSource {
+	return &DexscreenerSource{}
+}
+
+func (s *DexscreenerSource) Name() string {
+	return "dexscreener"
+}
+
+func (s *DexscreenerSource) Quote(ctx context.Context, mint solana.PublicKey) (*Quote, error) {
+	pairs, err := dexscreener.GetPairInformation(ctx, mint.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pair information: %w", err)
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no dexscreener pairs for %s", mint)
+	}
+
+	pair := pairs[0]
+	quote := &Quote{UpdatedAt: time.Now()}
+	usd, native := pair.TokenPrice(mint.String())
+	quote.USD = usd
+	if pairedToken, _ := pair.PairedToken(mint.String()); pairedToken == solana.SolMint.String() {
+		quote.SOL = native
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,59 @@
+package price_oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultJupiterPriceURL is the keyless Jupiter price API
+const DefaultJupiterPriceURL = "https://lite-api.jup.ag/price/v3"
+
+// JupiterSource prices tokens in USD with the Jupiter price API, which
+// derives prices from the routes Jupiter trades through
+type JupiterSource struct {
+	url    string
+	client *resty.Client
+}
+
+func NewJupiterSource(url string) *JupiterSource {
+	if url == "" {
+		url = DefaultJupiterPriceURL
+	}
+	return &JupiterSource{url: url, client: resty.New()}
+}
+
+type jupiterPrice struct {
+	USDPrice float64 `json:"usdPrice"`
+}
+
+func (s *JupiterSource) Name() string {
+	return "jupiter"
+}
+
+func (s *JupiterSource) Quote(ctx context.Context, mint solana.PublicKey) (*Quote, error) {
+	response, err := s.client.R().SetContext(ctx).SetQueryParam("ids", mint.String()).Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jupiter price: %w", err)
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("failed to get jupiter price: %s", response.Status())
+	}
+
+	var prices map[string]jupiterPrice
+	if err := json.Unmarshal(response.Body(), &prices); err != nil {
+		return nil, fmt.Errorf("failed to parse jupiter price: %w", err)
+	}
+
+	// Mints Jupiter can't price are missing from the response
+	price, ok := prices[mint.String()]
+	if !ok {
+		return nil, fmt.Errorf("no jupiter price for %s", mint)
+	}
+
+	return &Quote{USD: price.USDPrice, UpdatedAt: time.Now()}, nil
+}
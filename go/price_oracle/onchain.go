@@ -0,0 +1,64 @@
+package price_oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/pool_state"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+// OnchainSource prices tokens in SOL from pool reserves: the pump.fun bonding
+// curve while it trades, else the deepest Raydium AMM v4 or CPMM pool
+// against SOL. It needs no API, but finding Raydium pools scans program
+// accounts.
+type OnchainSource struct {
+	rpcClient *rpc.Client
+}
+
+func NewOnchainSource(rpcClient *rpc.Client) *OnchainSource {
+	return &OnchainSource{rpcClient: rpcClient}
+}
+
+func (s *OnchainSource) Name() string {
+	return "onchain"
+}
+
+func (s *OnchainSource) Quote(ctx context.Context, mint solana.PublicKey) (*Quote, error) {
+	if mint.Equals(solana.SolMint) {
+		return nil, ErrUnsupported
+	}
+
+	curve, err := pumpfun.GetBondingCurve(ctx, s.rpcClient, mint)
+	switch {
+	case err == nil && !curve.Complete:
+		return &Quote{SOL: curve.PriceSol(), UpdatedAt: time.Now()}, nil
+	case err != nil && !errors.Is(err, rpc.ErrNotFound):
+		return nil, err
+	}
+
+	// The curve completed or never existed, the token trades on Raydium
+	pools, err := raydium.FindPools(ctx, s.rpcClient, mint, solana.SolMint)
+	if err != nil {
+		return nil, err
+	}
+	state := pool_state.FromRaydiumPool(pools[0])
+
+	price, err := state.SpotPrice()
+	if err != nil {
+		return nil, err
+	}
+	// The spot price is of MintA in MintB, SOL may be either
+	if state.MintA.Equals(solana.SolMint) {
+		if price == 0 {
+			return nil, fmt.Errorf("raydium pool %s has no %s reserve", state.Address, mint)
+		}
+		price = 1 / price
+	}
+	return &Quote{SOL: price, UpdatedAt: time.Now()}, nil
+}
@@ -0,0 +1,310 @@
+package price_oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	defaultMaxAge       = 2 * time.Minute
+	defaultMaxDeviation = 0.05
+	defaultCacheTTL     = 15 * time.Second
+	defaultTimeout      = 10 * time.Second
+
+	// maxConcurrentPrices bounds the mints Prices resolves at once
+	maxConcurrentPrices = 8
+	maxCachedPrices     = 10_000
+)
+
+// Oracle resolves token prices in USD and SOL from several sources. Quotes
+// in only one currency are converted with the SOL/USD price, which the
+// oracle resolves the same way.
+type Oracle struct {
+	config Config
+
+	mu    sync.Mutex
+	cache map[solana.PublicKey]cachedPrice
+}
+
+type cachedPrice struct {
+	price   *Price
+	expires time.Time
+}
+
+func New(config Config) (*Oracle, error) {
+	if len(config.Sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required")
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultMaxAge
+	}
+	if config.MaxDeviation <= 0 {
+		config.MaxDeviation = defaultMaxDeviation
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaultCacheTTL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Oracle{
+		config: config,
+		cache:  make(map[solana.PublicKey]cachedPrice),
+	}, nil
+}
+
+// NewDefault creates an oracle over Pyth, Jupiter and Dexscreener, in that
+// order of priority, falling back to on-chain pools, whose lookup can scan
+// program accounts
+func NewDefault(rpcClient *rpc.Client) *Oracle {
+	oracle, _ := New(Config{
+		Sources: []Source{
+			NewPythSource(rpcClient, PythSOLUSDAccount),
+			NewJupiterSource(DefaultJupiterPriceURL),
+			NewDexscreenerSource(),
+		},
+		Fallbacks: []Source{
+			NewOnchainSource(rpcClient),
+		},
+	})
+	return oracle
+}
+
+// Price resolves the price of one whole token, from the cache when it was
+// resolved within the cache TTL
+func (o *Oracle) Price(ctx context.Context, mint solana.PublicKey) (*Price, error) {
+	o.mu.Lock()
+	cached, ok := o.cache[mint]
+	o.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.price, nil
+	}
+
+	price, err := o.resolve(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.cache) >= maxCachedPrices {
+		now := time.Now()
+		for mint, cached := range o.cache {
+			if now.After(cached.expires) {
+				delete(o.cache, mint)
+			}
+		}
+	}
+	o.cache[mint] = cachedPrice{price: price, expires: time.Now().Add(o.config.CacheTTL)}
+
+	return price, nil
+}
+
+// Prices resolves several mints concurrently. Mints without a price are
+// left out of the result.
+func (o *Oracle) Prices(ctx context.Context, mints []solana.PublicKey) map[solana.PublicKey]*Price {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		prices = make(map[solana.PublicKey]*Price, len(mints))
+		slots  = make(chan struct{}, maxConcurrentPrices)
+	)
+	for _, mint := range mints {
+		wg.Add(1)
+		go func(mint solana.PublicKey) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			price, err := o.Price(ctx, mint)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			prices[mint] = price
+			mu.Unlock()
+		}(mint)
+	}
+	wg.Wait()
+
+	return prices
+}
+
+func (o *Oracle) resolve(ctx context.Context, mint solana.PublicKey) (*Price, error) {
+	quotes, flags := o.collect(ctx, mint, o.config.Sources)
+	for _, fallback := range o.config.Fallbacks {
+		if o.hasFresh(quotes) {
+			break
+		}
+		fallbackQuotes, fallbackFlags := o.collect(ctx, mint, []Source{fallback})
+		quotes = append(quotes, fallbackQuotes...)
+		flags = append(flags, fallbackFlags...)
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no price for %s: %s", mint, strings.Join(flags, "; "))
+	}
+
+	if mint.Equals(solana.SolMint) {
+		for i := range quotes {
+			quotes[i].SOL = 1
+		}
+	} else if needsConversion(quotes) {
+		solPrice, err := o.Price(ctx, solana.SolMint)
+		if err != nil {
+			flags = append(flags, fmt.Sprintf("no SOL/USD price to convert quotes: %v", err))
+		} else if solPrice.USD > 0 {
+			for i := range quotes {
+				if quotes[i].USD == 0 {
+					quotes[i].USD = quotes[i].SOL * solPrice.USD
+				}
+				if quotes[i].SOL == 0 {
+					quotes[i].SOL = quotes[i].USD / solPrice.USD
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	for i := range quotes {
+		quotes[i].Stale = now.Sub(quotes[i].UpdatedAt) > o.config.MaxAge
+	}
+	flags = append(flags, markOutliers(quotes, o.config.MaxDeviation)...)
+
+	chosen := quotes[choose(quotes)]
+	if chosen.Stale {
+		flags = append(flags, fmt.Sprintf("every source is stale, %s is %s old", chosen.Source, now.Sub(chosen.UpdatedAt).Round(time.Second)))
+	}
+
+	return &Price{
+		Mint:      mint.String(),
+		USD:       chosen.USD,
+		SOL:       chosen.SOL,
+		Source:    chosen.Source,
+		UpdatedAt: chosen.UpdatedAt,
+		Stale:     chosen.Stale,
+		Flags:     flags,
+		Quotes:    quotes,
+	}, nil
+}
+
+// hasFresh reports whether a quote is younger than the max age
+func (o *Oracle) hasFresh(quotes []Quote) bool {
+	for _, quote := range quotes {
+		if time.Since(quote.UpdatedAt) <= o.config.MaxAge {
+			return true
+		}
+	}
+	return false
+}
+
+// collect asks the sources at once and returns their quotes in priority
+// order, with a flag for each source that failed
+func (o *Oracle) collect(ctx context.Context, mint solana.PublicKey, sources []Source) ([]Quote, []string) {
+	type result struct {
+		quote *Quote
+		err   error
+	}
+
+	results := make([]result, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			sourceCtx, cancel := context.WithTimeout(ctx, o.config.Timeout)
+			defer cancel()
+
+			quote, err := source.Quote(sourceCtx, mint)
+			results[i] = result{quote: quote, err: err}
+		}(i, source)
+	}
+	wg.Wait()
+
+	quotes := make([]Quote, 0, len(results))
+	flags := make([]string, 0)
+	for i, result := range results {
+		name := sources[i].Name()
+		switch {
+		case errors.Is(result.err, ErrUnsupported):
+		case result.err != nil:
+			flags = append(flags, fmt.Sprintf("%s failed: %v", name, result.err))
+		case result.quote == nil || (result.quote.USD <= 0 && result.quote.SOL <= 0):
+			flags = append(flags, fmt.Sprintf("%s has no price", name))
+		default:
+			quote := *result.quote
+			quote.Source = name
+			quotes = append(quotes, quote)
+		}
+	}
+
+	return quotes, flags
+}
+
+func needsConversion(quotes []Quote) bool {
+	for _, quote := range quotes {
+		if quote.USD == 0 || quote.SOL == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// markOutliers sets the deviation of every quote from the median USD price
+// and flags those further than maxDeviation. Two sources that disagree are
+// both flagged, there is no majority to tell which is right.
+func markOutliers(quotes []Quote, maxDeviation float64) []string {
+	usd := make([]float64, 0, len(quotes))
+	for _, quote := range quotes {
+		if quote.USD > 0 {
+			usd = append(usd, quote.USD)
+		}
+	}
+	if len(usd) < 2 {
+		return nil
+	}
+
+	sort.Float64s(usd)
+	median := usd[len(usd)/2]
+	if len(usd)%2 == 0 {
+		median = (usd[len(usd)/2-1] + usd[len(usd)/2]) / 2
+	}
+
+	var flags []string
+	for i := range quotes {
+		if quotes[i].USD <= 0 {
+			continue
+		}
+		quotes[i].Deviation = math.Abs(quotes[i].USD-median) / median
+		if quotes[i].Deviation > maxDeviation {
+			quotes[i].Outlier = true
+			flags = append(flags, fmt.Sprintf("%s is %.1f%% from the median price", quotes[i].Source, quotes[i].Deviation*100))
+		}
+	}
+	return flags
+}
+
+// choose picks the first fresh quote that agrees with the others, else the
+// first fresh quote, else the first quote
+func choose(quotes []Quote) int {
+	for i, quote := range quotes {
+		if !quote.Stale && !quote.Outlier {
+			return i
+		}
+	}
+	for i, quote := range quotes {
+		if !quote.Stale {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,244 @@
+package price_oracle
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_simulator"
+)
+
+// fixedSource returns the same quote, or error, for every mint it knows
+type fixedSource struct {
+	name   string
+	quotes map[solana.PublicKey]Quote
+	err    error
+	calls  int
+}
+
+func (s *fixedSource) Name() string {
+	return s.name
+}
+
+func (s *fixedSource) Quote(ctx context.Context, mint solana.PublicKey) (*Quote, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	quote, ok := s.quotes[mint]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return &quote, nil
+}
+
+func TestOracle(t *testing.T) {
+	ctx := context.Background()
+	mint := solana.NewWallet().PublicKey()
+	now := time.Now()
+
+	pyth := &fixedSource{name: "pyth", quotes: map[solana.PublicKey]Quote{
+		solana.SolMint: {USD: 200, UpdatedAt: now},
+	}}
+	jupiter := &fixedSource{name: "jupiter", quotes: map[solana.PublicKey]Quote{
+		solana.SolMint: {USD: 201, UpdatedAt: now},
+		mint:           {USD: 2.6, UpdatedAt: now},
+	}}
+	dexscreener := &fixedSource{name: "dexscreener", quotes: map[solana.PublicKey]Quote{
+		mint: {USD: 2, SOL: 0.01, UpdatedAt: now},
+	}}
+	onchain := &fixedSource{name: "onchain", quotes: map[solana.PublicKey]Quote{
+		mint: {SOL: 0.0101, UpdatedAt: now},
+	}}
+	broken := &fixedSource{name: "broken", err: errors.New("unavailable")}
+
+	oracle, err := New(Config{Sources: []Source{pyth, jupiter, dexscreener, onchain, broken}})
+	if err != nil {
+		t.Fatalf("failed to create oracle: %v", err)
+	}
+
+	solPrice, err := oracle.Price(ctx, solana.SolMint)
+	if err != nil {
+		t.Fatalf("failed to price SOL: %v", err)
+	}
+	if solPrice.Source != "pyth" || solPrice.USD != 200 || solPrice.SOL != 1 {
+		t.Errorf("SOL price = %+v, want 200 USD from pyth", solPrice)
+	}
+
+	// Jupiter comes first but is 30% above the others
+	price, err := oracle.Price(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to price mint: %v", err)
+	}
+	if price.Source != "dexscreener" || price.USD != 2 || price.SOL != 0.01 {
+		t.Errorf("price = %+v, want 2 USD from dexscreener", price)
+	}
+	if len(price.Quotes) != 3 || !price.Quotes[0].Outlier || price.Quotes[1].Outlier {
+		t.Errorf("quotes = %+v, want jupiter flagged as the only outlier", price.Quotes)
+	}
+	// The on-chain SOL price is converted with the SOL/USD price
+	if onchainUSD := price.Quotes[2].USD; math.Abs(onchainUSD-2.02) > 1e-9 {
+		t.Errorf("on-chain USD price = %f, want 2.02", onchainUSD)
+	}
+	if len(price.Flags) != 2 {
+		t.Errorf("flags = %v, want the broken source and the outlier", price.Flags)
+	}
+
+	// Prices are cached
+	calls := dexscreener.calls
+	if _, err := oracle.Price(ctx, mint); err != nil || dexscreener.calls != calls {
+		t.Errorf("cached price asked the sources again")
+	}
+
+	if _, err := oracle.Price(ctx, solana.NewWallet().PublicKey()); err == nil {
+		t.Error("priced a mint no source knows")
+	}
+}
+
+func TestOracleStale(t *testing.T) {
+	ctx := context.Background()
+	mint := solana.NewWallet().PublicKey()
+
+	first := &fixedSource{name: "first", quotes: map[solana.PublicKey]Quote{
+		mint: {USD: 1, SOL: 0.005, UpdatedAt: time.Now().Add(-time.Hour)},
+	}}
+	second := &fixedSource{name: "second", quotes: map[solana.PublicKey]Quote{
+		mint: {USD: 1.01, SOL: 0.005, UpdatedAt: time.Now()},
+	}}
+
+	oracle, _ := New(Config{Sources: []Source{first, second}, MaxAge: time.Minute})
+	price, err := oracle.Price(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to price mint: %v", err)
+	}
+	if price.Source != "second" || price.Stale {
+		t.Errorf("price = %+v, want the fresh quote", price)
+	}
+
+	oracle, _ = New(Config{Sources: []Source{first}, MaxAge: time.Minute})
+	price, err = oracle.Price(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to price mint: %v", err)
+	}
+	if !price.Stale || len(price.Flags) != 1 {
+		t.Errorf("price = %+v, want a stale price with a flag", price)
+	}
+}
+
+func TestOracleFallback(t *testing.T) {
+	ctx := context.Background()
+	mint := solana.NewWallet().PublicKey()
+	now := time.Now()
+
+	primary := &fixedSource{name: "primary", quotes: map[solana.PublicKey]Quote{
+		mint: {USD: 1, SOL: 0.005, UpdatedAt: now},
+	}}
+	first := &fixedSource{name: "first", err: errors.New("unavailable")}
+	second := &fixedSource{name: "second", quotes: map[solana.PublicKey]Quote{
+		mint: {USD: 1.01, SOL: 0.005, UpdatedAt: now},
+	}}
+	third := &fixedSource{name: "third", quotes: map[solana.PublicKey]Quote{
+		mint: {USD: 1.02, SOL: 0.005, UpdatedAt: now},
+	}}
+
+	oracle, _ := New(Config{Sources: []Source{primary}, Fallbacks: []Source{first, second, third}})
+	price, err := oracle.Price(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to price mint: %v", err)
+	}
+	if price.Source != "primary" || first.calls+second.calls+third.calls != 0 {
+		t.Errorf("price = %+v, want the primary price without asking the fallbacks", price)
+	}
+
+	// Fallbacks are asked in order until one has a fresh price
+	primary.quotes[mint] = Quote{USD: 1, SOL: 0.005, UpdatedAt: now.Add(-time.Hour)}
+	oracle, _ = New(Config{Sources: []Source{primary}, Fallbacks: []Source{first, second, third}})
+	price, err = oracle.Price(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to price mint: %v", err)
+	}
+	if price.Source != "second" || first.calls != 1 || second.calls != 1 || third.calls != 0 {
+		t.Errorf("price = %+v, want the second fallback after the first failed", price)
+	}
+	if len(price.Quotes) != 2 || len(price.Flags) != 1 {
+		t.Errorf("price = %+v, want the stale and fallback quotes with the failure flagged", price)
+	}
+}
+
+func TestJupiterSource(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ids") == mint.String() {
+			w.Write([]byte(`{"` + mint.String() + `": {"usdPrice": 1.25, "blockId": 1, "decimals": 6}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	source := NewJupiterSource(server.URL)
+	quote, err := source.Quote(context.Background(), mint)
+	if err != nil || quote.USD != 1.25 {
+		t.Errorf("quote = %+v, %v, want 1.25 USD", quote, err)
+	}
+	if _, err := source.Quote(context.Background(), solana.NewWallet().PublicKey()); err == nil {
+		t.Error("quoted a mint missing from the response")
+	}
+}
+
+func TestDecodePriceUpdate(t *testing.T) {
+	for _, verification := range [][]byte{{0, 3}, {1}} {
+		data := append([]byte{}, priceUpdateV2Discriminator...)
+		data = append(data, make([]byte, 32)...)
+		data = append(data, verification...)
+		data = append(data, pythSOLUSDFeedID...)
+		data = binary.LittleEndian.AppendUint64(data, 15_012_345_678)
+		data = binary.LittleEndian.AppendUint64(data, 1_000)
+		data = binary.LittleEndian.AppendUint32(data, uint32(0xFFFFFFF8)) // -8
+		data = binary.LittleEndian.AppendUint64(data, 1_700_000_000)
+
+		price, err := decodePriceUpdate(data)
+		if err != nil {
+			t.Fatalf("failed to decode price update: %v", err)
+		}
+		if price.Price != 15_012_345_678 || price.Exponent != -8 || price.PublishTime != 1_700_000_000 {
+			t.Errorf("price = %+v", price)
+		}
+	}
+
+	if _, err := decodePriceUpdate(make([]byte, 100)); err == nil {
+		t.Error("decoded an account without the discriminator")
+	}
+}
+
+func TestOnchainSourceSimulated(t *testing.T) {
+	ctx := context.Background()
+
+	sim := pumpfun_simulator.New()
+	_, mint, err := sim.CreateToken(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := NewOnchainSource(sim.Client()).Quote(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to quote: %v", err)
+	}
+
+	curve, _ := sim.BondingCurve(mint)
+	want := (&pumpfun.BondingCurve{BondingCurve: curve}).PriceSol()
+	if quote.SOL != want || quote.USD != 0 {
+		t.Errorf("quote = %+v, want %g SOL", quote, want)
+	}
+
+	if _, err := NewOnchainSource(sim.Client()).Quote(ctx, solana.SolMint); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SOL quote = %v, want ErrUnsupported", err)
+	}
+}
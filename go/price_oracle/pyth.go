@@ -0,0 +1,110 @@
+package price_oracle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	// PythReceiverProgramID owns the price update accounts of Pyth's push oracle
+	PythReceiverProgramID = solana.MustPublicKeyFromBase58("rec5EKMGg6MxZYaMdyBfgwp4d5rB9T1VQH5pJv5LtFJ")
+	// PythSOLUSDAccount is the sponsored SOL/USD price feed account, shard 0
+	PythSOLUSDAccount = solana.MustPublicKeyFromBase58("7UVimffxr9ow1uXYxsr4LHAcV58mLzhmwaeKvJ1pjLiE")
+
+	pythSOLUSDFeedID, _ = hex.DecodeString("ef0d8b6fda2ceba41da15d4095d1da392a0d2f8ed0c6c7bc0f4cfac8c280b56d")
+
+	priceUpdateV2Discriminator = anchorAccountDiscriminator("PriceUpdateV2")
+)
+
+// pythPrice is the price message of a PriceUpdateV2 account
+type pythPrice struct {
+	FeedID      []byte
+	Price       int64
+	Conf        uint64
+	Exponent    int32
+	PublishTime int64
+}
+
+// PythSource prices SOL in USD from a Pyth push oracle price feed account
+type PythSource struct {
+	rpcClient *rpc.Client
+	account   solana.PublicKey
+}
+
+// NewPythSource reads the SOL/USD price from account, a PriceUpdateV2
+// account such as PythSOLUSDAccount
+func NewPythSource(rpcClient *rpc.Client, account solana.PublicKey) *PythSource {
+	return &PythSource{rpcClient: rpcClient, account: account}
+}
+
+func (s *PythSource) Name() string {
+	return "pyth"
+}
+
+func (s *PythSource) Quote(ctx context.Context, mint solana.PublicKey) (*Quote, error) {
+	if !mint.Equals(solana.SolMint) {
+		return nil, ErrUnsupported
+	}
+
+	account, err := s.rpcClient.GetAccountInfo(ctx, s.account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pyth price account: %w", err)
+	}
+	if !account.Value.Owner.Equals(PythReceiverProgramID) {
+		return nil, fmt.Errorf("account %s is not a pyth price update", s.account)
+	}
+
+	price, err := decodePriceUpdate(account.Value.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(price.FeedID, pythSOLUSDFeedID) {
+		return nil, fmt.Errorf("account %s is not the SOL/USD feed", s.account)
+	}
+
+	return &Quote{
+		USD:       float64(price.Price) * math.Pow10(int(price.Exponent)),
+		UpdatedAt: time.Unix(price.PublishTime, 0),
+	}, nil
+}
+
+// decodePriceUpdate decodes the price message of a PriceUpdateV2 account:
+// discriminator, write authority, verification level, then the message
+func decodePriceUpdate(data []byte) (*pythPrice, error) {
+	if len(data) < 8+32+1 || !bytes.Equal(data[:8], priceUpdateV2Discriminator) {
+		return nil, fmt.Errorf("invalid pyth price update")
+	}
+
+	// Partial verification carries the number of signatures, Full nothing
+	offset := 8 + 32 + 1
+	if data[8+32] == 0 {
+		offset++
+	}
+
+	if len(data) < offset+32+8+8+4+8 {
+		return nil, fmt.Errorf("invalid pyth price update")
+	}
+	message := data[offset:]
+
+	return &pythPrice{
+		FeedID:      message[0:32],
+		Price:       int64(binary.LittleEndian.Uint64(message[32:40])),
+		Conf:        binary.LittleEndian.Uint64(message[40:48]),
+		Exponent:    int32(binary.LittleEndian.Uint32(message[48:52])),
+		PublishTime: int64(binary.LittleEndian.Uint64(message[52:60])),
+	}, nil
+}
+
+func anchorAccountDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("account:" + name))
+	return sum[:8]
+}
@@ -0,0 +1,66 @@
+package price_oracle
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ErrUnsupported is returned by sources that cannot price a mint at all, such
+// as Pyth for anything but SOL. The oracle leaves them out of the quotes.
+var ErrUnsupported = errors.New("mint not supported by source")
+
+// Source prices tokens from one place
+type Source interface {
+	Name() string
+	// Quote returns the price of one whole token in USD, SOL or both. A zero
+	// price is one the source doesn't know.
+	Quote(ctx context.Context, mint solana.PublicKey) (*Quote, error)
+}
+
+type Config struct {
+	// Sources in priority order. They are asked at once, and the price comes
+	// from the first fresh source that agrees with the others.
+	Sources []Source
+	// Fallbacks are asked in order, one at a time, only when no source has a
+	// fresh price. They suit sources too slow or costly for every lookup.
+	Fallbacks []Source
+
+	// MaxAge marks quotes older than this as stale, defaults to 2m
+	MaxAge time.Duration
+	// MaxDeviation flags quotes this fraction away from the median USD price
+	// as outliers, defaults to 0.05
+	MaxDeviation float64
+	// CacheTTL is how long a price is reused, defaults to 15s
+	CacheTTL time.Duration
+	// Timeout bounds each source, defaults to 10s
+	Timeout time.Duration
+}
+
+// Quote is the price of a token from one source. Sources without a
+// timestamp are as of when they were asked.
+type Quote struct {
+	Source    string    `json:"source"`
+	USD       float64   `json:"usd"`
+	SOL       float64   `json:"sol"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Stale     bool      `json:"stale"`
+	Outlier   bool      `json:"outlier"`
+	// Deviation is the distance from the median USD price, as a fraction
+	Deviation float64 `json:"deviation"`
+}
+
+// Price is the oracle's price for a token with the quotes it was chosen from.
+// Flags explain stale prices, outliers and failed sources.
+type Price struct {
+	Mint      string    `json:"mint"`
+	USD       float64   `json:"usd"`
+	SOL       float64   `json:"sol"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Stale     bool      `json:"stale"`
+	Flags     []string  `json:"flags"`
+	Quotes    []Quote   `json:"quotes"`
+}
@@ -0,0 +1,108 @@
+package pumpfun_sniper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+const (
+	programDataPrefix = "Program data: "
+	invokeSuffix      = " invoke ["
+)
+
+// DecodeLogs returns the events logged by the pump.fun
+// program in one transaction, in log order. Data logged by other programs is
+// ignored. A create event's InitialBuy is set when the creator bought the
+// same mint later in the transaction.
+func DecodeLogs(signature solana.Signature, slot uint64, logs []string) []Event {
+	var (
+		events []Event
+		stack  []string
+	)
+
+	for _, line := range logs {
+		switch {
+		case strings.HasPrefix(line, "Program ") && strings.Contains(line, invokeSuffix):
+			stack = append(stack, strings.TrimPrefix(line[:strings.Index(line, invokeSuffix)], "Program "))
+		case isProgramResult(line):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.HasPrefix(line, programDataPrefix):
+			if len(stack) == 0 || stack[len(stack)-1] != pumpfun.ProgramID.String() {
+				continue
+			}
+			event, err := decodeEvent(strings.TrimPrefix(line, programDataPrefix))
+			if err != nil {
+				continue
+			}
+			event.Signature = signature
+			event.Slot = slot
+			events = append(events, *event)
+		}
+	}
+
+	for i := range events {
+		if events[i].Type != EventCreate {
+			continue
+		}
+		for _, later := range events[i+1:] {
+			if later.Type == EventTrade && later.Trade.IsBuy &&
+				later.Trade.Mint.Equals(events[i].Create.Mint) &&
+				later.Trade.User.Equals(events[i].Create.User) {
+				events[i].InitialBuy = later.Trade
+				break
+			}
+		}
+	}
+
+	return events
+}
+
+// isProgramResult reports whether line ends a program invocation, that is
+// "Program <id> success" or "Program <id> failed: <reason>". Logs written by
+// a program ("Program log: ...") never match, whatever they say.
+func isProgramResult(line string) bool {
+	if !strings.HasPrefix(line, "Program ") {
+		return false
+	}
+	fields := strings.SplitN(strings.TrimPrefix(line, "Program "), " ", 3)
+	if len(fields) < 2 {
+		return false
+	}
+	if _, err := solana.PublicKeyFromBase58(fields[0]); err != nil {
+		return false
+	}
+	return (fields[1] == "success" && len(fields) == 2) || fields[1] == "failed:"
+}
+
+// decodeEvent decodes the base64 payload of a "Program data:" log line
+func decodeEvent(data string) (*Event, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event data: %w", err)
+	}
+
+	event, err := pump.DecodeEvent(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch impl := event.Impl.(type) {
+	case *CreateEvent:
+		return &Event{Type: EventCreate, Create: impl}, nil
+	case *TradeEvent:
+		return &Event{Type: EventTrade, Trade: impl}, nil
+	case *CompleteEvent:
+		return &Event{Type: EventComplete, Complete: impl}, nil
+	case *SetParamsEvent:
+		return &Event{Type: EventSetParams, SetParams: impl}, nil
+	default:
+		return nil, fmt.Errorf("unsupported event %s", event.Name())
+	}
+}
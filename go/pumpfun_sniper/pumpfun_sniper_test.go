@@ -0,0 +1,162 @@
+package pumpfun_sniper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/ilkamo/jupiter-go/jupiter"
+)
+
+var (
+	testMint    = solana.MustPublicKeyFromBase58("9wFFyRfZBsuAha4YcuxcXLKwMxJR43S7fPfQLusDBzvT")
+	testCreator = solana.MustPublicKeyFromBase58("7YttLkHDoNj9wyDur5pM1ejNaAvT9X4eqaYcHQqtj2G5")
+)
+
+type fakeSwapper struct {
+	requests []jupiter.GetQuoteParams
+}
+
+func (s *fakeSwapper) Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error) {
+	s.requests = append(s.requests, quoteRequest)
+	return &solana.Signature{1}, nil
+}
+
+func replayEvents(t *testing.T) []Event {
+	stream := NewStream(16)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- stream.ReplayFile(context.Background(), "testdata/logs.jsonl")
+	}()
+
+	var events []Event
+	for event := range stream.Events() {
+		events = append(events, event)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("failed to replay logs: %v", err)
+	}
+	return events
+}
+
+func TestReplay(t *testing.T) {
+	events := replayEvents(t)
+
+	// The spoofed create logged by another program is ignored
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(events))
+	}
+
+	create := events[0]
+	if create.Type != EventCreate || create.Slot != 300000001 {
+		t.Fatalf("unexpected first event: %+v", create)
+	}
+	if create.Create.Name != "Moon Cat" || create.Create.Symbol != "MCAT" || !create.Create.Mint.Equals(testMint) || !create.Create.User.Equals(testCreator) {
+		t.Errorf("unexpected create event: %+v", create.Create)
+	}
+	if create.InitialBuy == nil || create.InitialBuy.SolAmount != 1_500_000_000 {
+		t.Errorf("expected creator buy of 1.5 SOL, got %+v", create.InitialBuy)
+	}
+
+	if events[1].Type != EventTrade || !events[1].Trade.IsBuy || events[1].Signature != create.Signature {
+		t.Errorf("unexpected buy event: %+v", events[1])
+	}
+	if events[2].Type != EventTrade || events[2].Trade.IsBuy || events[2].Trade.TokenAmount != 6_000_000_000_000 {
+		t.Errorf("unexpected sell event: %+v", events[2])
+	}
+	if events[3].Type != EventCreate || events[3].InitialBuy != nil {
+		t.Errorf("unexpected create without buy: %+v", events[3])
+	}
+}
+
+func TestDecodeLogsIgnoresProgramLogs(t *testing.T) {
+	pump := "Program 6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P"
+	token := "Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	logs := []string{
+		pump + " invoke [1]",
+		token + " invoke [2]",
+		"Program log: transfer failed: retrying",
+		"Program log: success",
+		token + " success",
+		"Program data: G3KpTd7rY3YIAAAATW9vbiBDYXQEAAAATUNBVB4AAABodHRwczovL2lwZnMuaW8vaXBmcy9RbU1vb25DYXSEwvsYrtYZ9UZjJlPvBgKfAqhkvzgphnGBuyDfHXFcMCphuxGKWQi40nbjVgZIu9cBeaiE8FjOehVeQ8T9JzEtYVHLHD6dIP2ESjqBzg3ol1/wB7+/ylGwd9LS7wSZ2A4=",
+		pump + " success",
+	}
+	events := DecodeLogs(solana.Signature{}, 1, logs)
+	if len(events) != 1 || events[0].Type != EventCreate {
+		t.Fatalf("expected the create logged by pump.fun, got %+v", events)
+	}
+
+	// A failed inner program still ends its invocation
+	logs[2] = token + " failed: custom program error: 0x1"
+	logs = append(logs[:3], logs[5:]...)
+	if events := DecodeLogs(solana.Signature{}, 1, logs); len(events) != 1 {
+		t.Fatalf("expected the create after a failed inner program, got %+v", events)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	events := replayEvents(t)
+	moonCat, rug := events[0], events[3]
+
+	tests := []struct {
+		name   string
+		filter Filter
+		event  Event
+		want   bool
+	}{
+		{"empty filter", Filter{}, moonCat, true},
+		{"trade event", Filter{}, events[1], false},
+		{"name contains", Filter{NameContains: []string{"cat"}}, moonCat, true},
+		{"name does not contain", Filter{NameContains: []string{"cat"}}, rug, false},
+		{"name excluded", Filter{NameExcludes: []string{"RUG"}}, rug, false},
+		{"creator allowed", Filter{Creators: []solana.PublicKey{testCreator}}, moonCat, true},
+		{"creator not allowed", Filter{Creators: []solana.PublicKey{testCreator}}, rug, false},
+		{"creator denied", Filter{DenyCreators: []solana.PublicKey{testCreator}}, moonCat, false},
+		{"initial buy in range", Filter{MinInitialBuySol: 1, MaxInitialBuySol: 2}, moonCat, true},
+		{"initial buy too large", Filter{MaxInitialBuySol: 1}, moonCat, false},
+		{"no initial buy", Filter{MinInitialBuySol: 0.1}, rug, false},
+		{"reserves in range", Filter{MinSolReserves: 31, MaxSolReserves: 32}, moonCat, true},
+		{"reserves too low", Filter{MinSolReserves: 40}, moonCat, false},
+		{"reserves unknown", Filter{MaxSolReserves: 40}, rug, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := tt.filter.Match(tt.event); got != tt.want {
+				t.Errorf("Match() = %v (%s), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniper(t *testing.T) {
+	swapper := &fakeSwapper{}
+	sniper, err := NewSniper(swapper, solana.NewWallet().PrivateKey, SniperConfig{
+		Filter:  Filter{MinInitialBuySol: 1},
+		BuySol:  0.05,
+		MaxBuys: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sniper: %v", err)
+	}
+
+	stream := NewStream(16)
+	go stream.ReplayFile(context.Background(), "testdata/logs.jsonl")
+	if err := sniper.Run(context.Background(), stream.Events()); err != nil {
+		t.Fatalf("sniper failed: %v", err)
+	}
+
+	if len(swapper.requests) != 1 {
+		t.Fatalf("expected 1 buy, got %d", len(swapper.requests))
+	}
+	request := swapper.requests[0]
+	if request.OutputMint != testMint.String() || request.InputMint != solana.SolMint.String() || request.Amount != 50_000_000 {
+		t.Errorf("unexpected buy request: %+v", request)
+	}
+
+	// A mint is only bought once
+	events := replayEvents(t)
+	if snipe := sniper.Handle(context.Background(), events[0]); snipe != nil {
+		t.Errorf("expected repeated create to be ignored, got %+v", snipe)
+	}
+}
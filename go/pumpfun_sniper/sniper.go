@@ -0,0 +1,225 @@
+package pumpfun_sniper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/ilkamo/jupiter-go/jupiter"
+)
+
+// Swapper executes the auto-buys. OnchainActionsTool satisfies it.
+type Swapper interface {
+	Swap(ctx context.Context, quoteRequest jupiter.GetQuoteParams, signer solana.PrivateKey) (*solana.Signature, error)
+}
+
+// Filter selects which launches are bought. Empty fields don't filter.
+type Filter struct {
+	// NameContains passes tokens whose name or symbol contains any of these, case-insensitive
+	NameContains []string
+	// NameExcludes rejects tokens whose name or symbol contains any of these, case-insensitive
+	NameExcludes []string
+
+	Creators     []solana.PublicKey
+	DenyCreators []solana.PublicKey
+
+	// Initial buy bounds in SOL, launches without a creator buy count as 0
+	MinInitialBuySol float64
+	MaxInitialBuySol float64
+
+	// Virtual SOL reserve bounds after the creator buy. Reserves are only
+	// known when the launch includes a creator buy, otherwise these reject.
+	MinSolReserves float64
+	MaxSolReserves float64
+}
+
+// Match reports whether a create event passes the filter, with the reason when it doesn't
+func (f Filter) Match(event Event) (bool, string) {
+	if event.Type != EventCreate || event.Create == nil {
+		return false, "not a create event"
+	}
+	create := event.Create
+
+	text := strings.ToLower(create.Name + " " + create.Symbol)
+	if len(f.NameContains) > 0 && !containsAny(text, f.NameContains) {
+		return false, "name does not match"
+	}
+	if containsAny(text, f.NameExcludes) {
+		return false, "name is excluded"
+	}
+
+	if len(f.Creators) > 0 && !hasKey(f.Creators, create.User) {
+		return false, "creator is not allowed"
+	}
+	if hasKey(f.DenyCreators, create.User) {
+		return false, "creator is denied"
+	}
+
+	var initialBuySol float64
+	if event.InitialBuy != nil {
+		initialBuySol = float64(event.InitialBuy.SolAmount) / 1e9
+	}
+	if initialBuySol < f.MinInitialBuySol {
+		return false, fmt.Sprintf("initial buy %.4f SOL is below minimum", initialBuySol)
+	}
+	if f.MaxInitialBuySol > 0 && initialBuySol > f.MaxInitialBuySol {
+		return false, fmt.Sprintf("initial buy %.4f SOL is above maximum", initialBuySol)
+	}
+
+	if f.MinSolReserves > 0 || f.MaxSolReserves > 0 {
+		if event.InitialBuy == nil {
+			return false, "sol reserves are unknown"
+		}
+		reserves := float64(event.InitialBuy.VirtualSolReserves) / 1e9
+		if reserves < f.MinSolReserves {
+			return false, fmt.Sprintf("sol reserves %.4f are below minimum", reserves)
+		}
+		if f.MaxSolReserves > 0 && reserves > f.MaxSolReserves {
+			return false, fmt.Sprintf("sol reserves %.4f are above maximum", reserves)
+		}
+	}
+
+	return true, ""
+}
+
+type SniperConfig struct {
+	Filter Filter
+
+	// BuySol is spent on every matching launch
+	BuySol      float64
+	SlippageBps int
+	// MaxBuys stops buying after this many attempts, zero means no limit
+	MaxBuys int
+}
+
+// Snipe reports one auto-buy attempt
+type Snipe struct {
+	Mint      solana.PublicKey `json:"mint"`
+	Name      string           `json:"name"`
+	Symbol    string           `json:"symbol"`
+	Creator   solana.PublicKey `json:"creator"`
+	Lamports  uint64           `json:"lamports"`
+	Signature string           `json:"signature,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// Sniper buys newly created pump.fun tokens that pass its filter
+type Sniper struct {
+	mu sync.Mutex
+
+	swapper Swapper
+	signer  solana.PrivateKey
+	config  SniperConfig
+
+	bought  map[solana.PublicKey]bool
+	onSnipe func(Snipe)
+}
+
+type SniperOption func(*Sniper)
+
+// WithOnSnipe sets a callback that receives every buy attempt
+func WithOnSnipe(fn func(Snipe)) SniperOption {
+	return func(s *Sniper) {
+		s.onSnipe = fn
+	}
+}
+
+// NewSniper creates a sniper that buys from the signer's wallet
+func NewSniper(swapper Swapper, signer solana.PrivateKey, config SniperConfig, opts ...SniperOption) (*Sniper, error) {
+	if config.BuySol <= 0 {
+		return nil, fmt.Errorf("buy amount must be positive")
+	}
+	if config.SlippageBps <= 0 {
+		config.SlippageBps = 500
+	}
+
+	s := &Sniper{
+		swapper: swapper,
+		signer:  signer,
+		config:  config,
+		bought:  make(map[solana.PublicKey]bool),
+		onSnipe: func(Snipe) {},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// Run handles events until the channel is closed or the context is cancelled
+func (s *Sniper) Run(ctx context.Context, events <-chan Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			s.Handle(ctx, event)
+		}
+	}
+}
+
+// Handle buys the token of a matching create event. It returns nil when the
+// event is filtered out, the mint was already bought or MaxBuys is reached.
+func (s *Sniper) Handle(ctx context.Context, event Event) *Snipe {
+	if ok, _ := s.config.Filter.Match(event); !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.bought[event.Create.Mint] || (s.config.MaxBuys > 0 && len(s.bought) >= s.config.MaxBuys) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.bought[event.Create.Mint] = true
+	s.mu.Unlock()
+
+	snipe := Snipe{
+		Mint:     event.Create.Mint,
+		Name:     event.Create.Name,
+		Symbol:   event.Create.Symbol,
+		Creator:  event.Create.User,
+		Lamports: uint64(s.config.BuySol * 1e9),
+	}
+
+	slippageBps := s.config.SlippageBps
+	sig, err := s.swapper.Swap(ctx, jupiter.GetQuoteParams{
+		InputMint:   solana.SolMint.String(),
+		OutputMint:  event.Create.Mint.String(),
+		Amount:      jupiter.AmountParameter(snipe.Lamports),
+		SlippageBps: &slippageBps,
+	}, s.signer)
+	if sig != nil {
+		snipe.Signature = sig.String()
+	}
+	if err != nil {
+		snipe.Error = err.Error()
+	}
+
+	s.onSnipe(snipe)
+	return &snipe
+}
+
+func containsAny(text string, needles []string) bool {
+	for _, needle := range needles {
+		if needle != "" && strings.Contains(text, strings.ToLower(needle)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasKey(keys []solana.PublicKey, key solana.PublicKey) bool {
+	for _, k := range keys {
+		if k.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package pumpfun_sniper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+)
+
+// Stream decodes pump.fun program logs into a channel of events. A stream is
+// fed once, by Run or one of the replay methods, which close the channel when
+// they return.
+type Stream struct {
+	events chan Event
+}
+
+// NewStream creates a stream whose channel buffers up to buffer events
+func NewStream(buffer int) *Stream {
+	return &Stream{
+		events: make(chan Event, buffer),
+	}
+}
+
+// Events returns the channel of decoded events
+func (s *Stream) Events() <-chan Event {
+	return s.events
+}
+
+// Run subscribes to pump.fun program logs over the websocket and publishes
+// events until the context is cancelled or the subscription fails
+func (s *Stream) Run(ctx context.Context, wsURL string) error {
+	defer close(s.events)
+
+	client, err := ws.Connect(ctx, wsURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect websocket: %w", err)
+	}
+	defer client.Close()
+
+	sub, err := client.LogsSubscribeMentions(pumpfun.ProgramID, rpc.CommitmentProcessed)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to pump.fun logs: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		result, err := sub.Recv(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("log subscription failed: %w", err)
+		}
+		if result.Value.Err != nil {
+			continue
+		}
+
+		events := DecodeLogs(result.Value.Signature, result.Context.Slot, result.Value.Logs)
+		if err := s.publish(ctx, events); err != nil {
+			return err
+		}
+	}
+}
+
+// Replay publishes the events of recorded log notifications without any network
+func (s *Stream) Replay(ctx context.Context, records []LogRecord) error {
+	defer close(s.events)
+
+	for _, record := range records {
+		if err := s.replay(ctx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayFile replays a JSONL file with one LogRecord per line
+func (s *Stream) ReplayFile(ctx context.Context, path string) error {
+	defer close(s.events)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record LogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("failed to parse log record: %w", err)
+		}
+		if err := s.replay(ctx, record); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+	return nil
+}
+
+func (s *Stream) replay(ctx context.Context, record LogRecord) error {
+	var signature solana.Signature
+	if record.Signature != "" {
+		var err error
+		signature, err = solana.SignatureFromBase58(record.Signature)
+		if err != nil {
+			return fmt.Errorf("invalid signature %s: %w", record.Signature, err)
+		}
+	}
+
+	return s.publish(ctx, DecodeLogs(signature, record.Slot, record.Logs))
+}
+
+func (s *Stream) publish(ctx context.Context, events []Event) error {
+	for _, event := range events {
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package pumpfun_sniper
+
+import (
+	"github.com/gagliardetto/solana-go"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+)
+
+type EventType string
+
+const (
+	EventCreate    EventType = "create"
+	EventTrade     EventType = "trade"
+	EventComplete  EventType = "complete"
+	EventSetParams EventType = "set_params"
+)
+
+// CreateEvent is emitted by the pump.fun program when a token is launched
+type CreateEvent = pump.CreateEvent
+
+// TradeEvent is emitted by the pump.fun program on every buy and sell
+type TradeEvent = pump.TradeEvent
+
+// CompleteEvent is emitted by the pump.fun program when a bonding curve completes
+type CompleteEvent = pump.CompleteEvent
+
+// SetParamsEvent is emitted by the pump.fun program when its global parameters change
+type SetParamsEvent = pump.SetParamsEvent
+
+// Event is one decoded pump.fun program event. Exactly one of Create, Trade,
+// Complete and SetParams is set, matching Type.
+type Event struct {
+	Type      EventType
+	Signature solana.Signature
+	Slot      uint64
+
+	Create    *CreateEvent
+	Trade     *TradeEvent
+	Complete  *CompleteEvent
+	SetParams *SetParamsEvent
+
+	// InitialBuy is the creator's buy in the launch transaction, if any. Only set on create events.
+	InitialBuy *TradeEvent
+}
+
+// LogRecord is a single logs notification, as received from logsSubscribe
+// or recorded for replay
+type LogRecord struct {
+	Signature string   `json:"signature"`
+	Slot      uint64   `json:"slot"`
+	Logs      []string `json:"logs"`
+}
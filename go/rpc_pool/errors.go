@@ -0,0 +1,46 @@
+package rpc_pool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// JSON-RPC error codes worth retrying on another endpoint
+const (
+	rpcCodeTooManyRequests = 429
+	rpcCodeNodeUnhealthy   = -32005
+)
+
+// isRetryable reports whether a failed call may succeed on another endpoint:
+// rate limits, server errors, unhealthy nodes and transport failures.
+// Application errors such as invalid params or a failed preflight, and
+// client-side errors such as a result that doesn't decode, are final.
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code == 429 || httpErr.Code >= 500
+	}
+
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == rpcCodeTooManyRequests || rpcErr.Code == rpcCodeNodeUnhealthy
+	}
+
+	// Transport failures: timeouts, refused or reset connections and bodies
+	// cut short
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,75 @@
+package rpc_pool
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Run checks endpoint health every HealthCheckInterval until the context is cancelled
+func (p *Pool) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth asks every endpoint for its slot. Endpoints that fail or lag
+// more than MaxSlotLag behind the highest slot are marked unhealthy until
+// the next check. Rate limited endpoints without budget keep their state.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	type check struct {
+		slot    uint64
+		err     error
+		skipped bool
+	}
+
+	checks := make([]check, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		if e.limiter != nil && !e.limiter.Allow() {
+			checks[i].skipped = true
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			checks[i].err = e.client.CallForInto(ctx, &checks[i].slot, "getSlot", nil)
+		}(i, e)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for _, c := range checks {
+		if !c.skipped && c.err == nil && c.slot > highest {
+			highest = c.slot
+		}
+	}
+
+	for i, e := range p.endpoints {
+		c := checks[i]
+		if c.skipped {
+			continue
+		}
+
+		e.mu.Lock()
+		if c.err != nil {
+			e.healthy = false
+			e.lastError = c.err.Error()
+		} else {
+			e.slot = c.slot
+			e.lag = highest - c.slot
+			e.healthy = e.lag <= p.config.MaxSlotLag
+		}
+		e.mu.Unlock()
+	}
+}
@@ -0,0 +1,376 @@
+package rpc_pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"golang.org/x/time/rate"
+)
+
+// writeMethods change chain state and are never hedged. They still fail
+// over, resending a signed transaction is idempotent.
+var writeMethods = map[string]bool{
+	"sendTransaction": true,
+	"requestAirdrop":  true,
+}
+
+type endpoint struct {
+	config  Endpoint
+	client  jsonrpc.RPCClient
+	limiter *rate.Limiter
+
+	mu        sync.Mutex
+	healthy   bool
+	slot      uint64
+	lag       uint64
+	coolUntil time.Time
+	lastError string
+	requests  uint64
+	failures  uint64
+}
+
+func (e *endpoint) available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.healthy && !now.Before(e.coolUntil)
+}
+
+func (e *endpoint) succeed() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+}
+
+func (e *endpoint) fail(err error, cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.failures++
+	e.lastError = err.Error()
+	e.coolUntil = time.Now().Add(cooldown)
+}
+
+// Pool spreads JSON-RPC calls across several endpoints. It implements
+// rpc.JSONRPCClient, so any tool taking an *rpc.Client can use Client().
+type Pool struct {
+	config    Config
+	endpoints []*endpoint
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+var _ rpc.JSONRPCClient = (*Pool)(nil)
+
+// New creates a pool over the configured endpoints. Every endpoint starts
+// healthy, call Run to keep health and slot lag up to date.
+func New(config Config) (*Pool, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = 15 * time.Second
+	}
+	if config.MaxSlotLag == 0 {
+		config.MaxSlotLag = 50
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 10 * time.Second
+	}
+	if config.MaxAttempts <= 0 || config.MaxAttempts > len(config.Endpoints) {
+		config.MaxAttempts = len(config.Endpoints)
+	}
+
+	p := &Pool{
+		config: config,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for _, cfg := range config.Endpoints {
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("endpoint url is required")
+		}
+		if cfg.Weight <= 0 {
+			cfg.Weight = 1
+		}
+
+		e := &endpoint{
+			config: cfg,
+			client: jsonrpc.NewClientWithOpts(cfg.URL, &jsonrpc.RPCClientOpts{
+				HTTPClient:    &http.Client{},
+				CustomHeaders: cfg.Headers,
+			}),
+			healthy: true,
+		}
+		if cfg.RateLimit > 0 {
+			burst := cfg.Burst
+			if burst <= 0 {
+				burst = 1
+			}
+			e.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), burst)
+		}
+
+		p.endpoints = append(p.endpoints, e)
+	}
+
+	return p, nil
+}
+
+// NewFromURLs creates a pool of equally weighted, unlimited endpoints
+func NewFromURLs(urls ...string) (*Pool, error) {
+	endpoints := make([]Endpoint, len(urls))
+	for i, url := range urls {
+		endpoints[i] = Endpoint{URL: url}
+	}
+	return New(Config{Endpoints: endpoints})
+}
+
+// Client returns an rpc.Client that sends every call through the pool
+func (p *Pool) Client() *rpc.Client {
+	return rpc.NewWithCustomRPCClient(p)
+}
+
+func (p *Pool) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	if p.config.HedgeDelay > 0 && !writeMethods[method] && p.config.MaxAttempts > 1 {
+		return p.hedge(ctx, out, method, params)
+	}
+
+	return p.failover(ctx, func(ctx context.Context, e *endpoint) error {
+		return e.client.CallForInto(ctx, out, method, params)
+	})
+}
+
+// CallWithCallback fails over but is never hedged, the callback may have side effects
+func (p *Pool) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return p.failover(ctx, func(ctx context.Context, e *endpoint) error {
+		return e.client.CallWithCallback(ctx, method, params, callback)
+	})
+}
+
+func (p *Pool) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	var responses jsonrpc.RPCResponses
+	err := p.failover(ctx, func(ctx context.Context, e *endpoint) error {
+		var err error
+		responses, err = e.client.CallBatch(ctx, requests)
+		return err
+	})
+	return responses, err
+}
+
+// Close releases idle connections of every endpoint
+func (p *Pool) Close() error {
+	for _, e := range p.endpoints {
+		if closer, ok := e.client.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	return nil
+}
+
+// Status returns a snapshot of every endpoint, in configuration order
+func (p *Pool) Status() []EndpointStatus {
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		e.mu.Lock()
+		statuses[i] = EndpointStatus{
+			URL:       e.config.URL,
+			Healthy:   e.healthy,
+			Slot:      e.slot,
+			SlotLag:   e.lag,
+			CoolUntil: e.coolUntil,
+			LastError: e.lastError,
+			Requests:  e.requests,
+			Failures:  e.failures,
+		}
+		e.mu.Unlock()
+	}
+	return statuses
+}
+
+// failover tries endpoints one at a time until one answers or returns an
+// error that isn't worth retrying
+func (p *Pool) failover(ctx context.Context, call func(ctx context.Context, e *endpoint) error) error {
+	candidates := p.order()
+
+	var lastErr error
+	for attempt := 0; attempt < p.config.MaxAttempts && len(candidates) > 0; attempt++ {
+		var (
+			e   *endpoint
+			err error
+		)
+		e, candidates, err = p.acquire(ctx, candidates)
+		if err != nil {
+			return err
+		}
+
+		err = call(ctx, e)
+		if !isRetryable(err) {
+			e.succeed()
+			return err
+		}
+
+		e.fail(err, p.config.Cooldown)
+		lastErr = err
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("all rpc endpoints failed: %w", lastErr)
+}
+
+type hedgeResult struct {
+	endpoint *endpoint
+	raw      json.RawMessage
+	err      error
+}
+
+// hedge sends a read call to the first endpoint and to the next one each
+// time HedgeDelay passes without an answer or an attempt fails. The first
+// answer wins and the other attempts are cancelled.
+func (p *Pool) hedge(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	candidates := p.order()
+	results := make(chan hedgeResult, p.config.MaxAttempts)
+
+	var (
+		attempts int
+		inflight int
+	)
+	launch := func() error {
+		e, rest, err := p.acquire(ctx, candidates)
+		if err != nil {
+			return err
+		}
+		candidates = rest
+		attempts++
+		inflight++
+
+		go func() {
+			var raw json.RawMessage
+			err := e.client.CallForInto(ctx, &raw, method, params)
+			results <- hedgeResult{endpoint: e, raw: raw, err: err}
+		}()
+		return nil
+	}
+	canLaunch := func() bool {
+		return attempts < p.config.MaxAttempts && len(candidates) > 0
+	}
+
+	if err := launch(); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(p.config.HedgeDelay)
+	defer timer.Stop()
+
+	var lastErr error
+	for inflight > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-timer.C:
+			if canLaunch() {
+				if err := launch(); err != nil {
+					return err
+				}
+				timer.Reset(p.config.HedgeDelay)
+			}
+
+		case result := <-results:
+			inflight--
+			if !isRetryable(result.err) {
+				result.endpoint.succeed()
+				if result.err != nil {
+					return result.err
+				}
+				return json.Unmarshal(result.raw, out)
+			}
+
+			result.endpoint.fail(result.err, p.config.Cooldown)
+			lastErr = result.err
+			if canLaunch() {
+				if err := launch(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("all rpc endpoints failed: %w", lastErr)
+}
+
+// acquire returns the first candidate with rate limit budget left and the
+// remaining candidates. When every candidate is limited it waits for the first.
+func (p *Pool) acquire(ctx context.Context, candidates []*endpoint) (*endpoint, []*endpoint, error) {
+	for i, e := range candidates {
+		if e.limiter == nil || e.limiter.Allow() {
+			rest := append(append([]*endpoint{}, candidates[:i]...), candidates[i+1:]...)
+			return e, rest, nil
+		}
+	}
+
+	e := candidates[0]
+	if err := e.limiter.Wait(ctx); err != nil {
+		return nil, nil, fmt.Errorf("rate limit wait failed: %w", err)
+	}
+	return e, candidates[1:], nil
+}
+
+// order returns the available endpoints in weighted random order, followed
+// by the unavailable ones as a last resort
+func (p *Pool) order() []*endpoint {
+	now := time.Now()
+
+	var available, unavailable []*endpoint
+	for _, e := range p.endpoints {
+		if e.available(now) {
+			available = append(available, e)
+		} else {
+			unavailable = append(unavailable, e)
+		}
+	}
+
+	return append(p.weightedShuffle(available), p.weightedShuffle(unavailable)...)
+}
+
+func (p *Pool) weightedShuffle(endpoints []*endpoint) []*endpoint {
+	remaining := append([]*endpoint{}, endpoints...)
+	ordered := make([]*endpoint, 0, len(endpoints))
+
+	p.randMu.Lock()
+	defer p.randMu.Unlock()
+
+	for len(remaining) > 0 {
+		total := 0
+		for _, e := range remaining {
+			total += e.config.Weight
+		}
+
+		pick := p.rand.Intn(total)
+		for i, e := range remaining {
+			pick -= e.config.Weight
+			if pick < 0 {
+				ordered = append(ordered, e)
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return ordered
+}
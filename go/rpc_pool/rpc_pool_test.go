@@ -0,0 +1,236 @@
+package rpc_pool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// fakeNode answers getSlot with slot after delay, or fails with status
+type fakeNode struct {
+	slot   uint64
+	delay  time.Duration
+	status int
+	// rpcErrorCode answers with a JSON-RPC error instead of a result
+	rpcErrorCode int
+
+	calls atomic.Int64
+}
+
+func (n *fakeNode) serve(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.calls.Add(1)
+
+		var request struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if n.delay > 0 {
+			select {
+			case <-time.After(n.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if n.status != 0 {
+			w.WriteHeader(n.status)
+			w.Write([]byte("unavailable"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if n.rpcErrorCode != 0 {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":%d,"message":"error"}}`, request.ID, n.rpcErrorCode)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%d}`, request.ID, n.slot)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestPool(t *testing.T, config Config, nodes ...*fakeNode) *Pool {
+	for _, node := range nodes {
+		config.Endpoints = append(config.Endpoints, Endpoint{URL: node.serve(t).URL})
+	}
+
+	pool, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func TestFailover(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			bad := &fakeNode{status: status}
+			good := &fakeNode{slot: 42}
+			pool := newTestPool(t, Config{}, bad, good)
+
+			// Every call succeeds regardless of which endpoint is tried first
+			for i := 0; i < 5; i++ {
+				slot, err := pool.Client().GetSlot(context.Background(), rpc.CommitmentConfirmed)
+				if err != nil {
+					t.Fatalf("GetSlot failed: %v", err)
+				}
+				if slot != 42 {
+					t.Fatalf("slot = %d, want 42", slot)
+				}
+			}
+
+			// After failing once the bad endpoint cools down and isn't tried again
+			if calls := bad.calls.Load(); calls > 1 {
+				t.Errorf("bad endpoint called %d times, want at most 1", calls)
+			}
+		})
+	}
+}
+
+func TestRetryableRPCError(t *testing.T) {
+	behind := &fakeNode{rpcErrorCode: rpcCodeNodeUnhealthy}
+	good := &fakeNode{slot: 7}
+	pool := newTestPool(t, Config{}, behind, good)
+
+	if _, err := pool.Client().GetSlot(context.Background(), rpc.CommitmentConfirmed); err != nil {
+		t.Fatalf("expected failover on unhealthy node, got %v", err)
+	}
+}
+
+func TestNonRetryableError(t *testing.T) {
+	invalid := &fakeNode{rpcErrorCode: -32602}
+	pool := newTestPool(t, Config{}, invalid, &fakeNode{rpcErrorCode: -32602})
+
+	if _, err := pool.Client().GetSlot(context.Background(), rpc.CommitmentConfirmed); err == nil {
+		t.Fatal("expected invalid params error")
+	}
+
+	var calls int64
+	for _, status := range pool.Status() {
+		calls += int64(status.Requests)
+		if status.Failures != 0 {
+			t.Errorf("invalid params should not count as an endpoint failure")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestAllEndpointsFail(t *testing.T) {
+	pool := newTestPool(t, Config{}, &fakeNode{status: 500}, &fakeNode{status: 503})
+
+	if _, err := pool.Client().GetSlot(context.Background(), rpc.CommitmentConfirmed); err == nil {
+		t.Fatal("expected error when every endpoint fails")
+	}
+}
+
+func TestHedge(t *testing.T) {
+	slow := &fakeNode{slot: 1, delay: 2 * time.Second}
+	fast := &fakeNode{slot: 2}
+	pool := newTestPool(t, Config{HedgeDelay: 20 * time.Millisecond}, slow, fast)
+
+	start := time.Now()
+	slot, err := pool.Client().GetSlot(context.Background(), rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("GetSlot failed: %v", err)
+	}
+	if slot != 2 {
+		t.Errorf("slot = %d, want the fast endpoint's 2", slot)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("hedged call took %s", elapsed)
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	ahead := &fakeNode{slot: 1000}
+	lagging := &fakeNode{slot: 900}
+	down := &fakeNode{status: 500}
+	pool := newTestPool(t, Config{MaxSlotLag: 50}, ahead, lagging, down)
+
+	pool.CheckHealth(context.Background())
+
+	statuses := pool.Status()
+	if !statuses[0].Healthy || statuses[0].SlotLag != 0 {
+		t.Errorf("unexpected status for leading endpoint: %+v", statuses[0])
+	}
+	if statuses[1].Healthy || statuses[1].SlotLag != 100 {
+		t.Errorf("unexpected status for lagging endpoint: %+v", statuses[1])
+	}
+	if statuses[2].Healthy {
+		t.Errorf("failing endpoint should be unhealthy: %+v", statuses[2])
+	}
+
+	// Healthy endpoints are always ordered first
+	for i := 0; i < 10; i++ {
+		if order := pool.order(); order[0].config.URL != statuses[0].URL {
+			t.Fatalf("expected the healthy endpoint first, got %s", order[0].config.URL)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	pool, err := New(Config{Endpoints: []Endpoint{
+		{URL: "http://limited", RateLimit: 1, Burst: 1},
+		{URL: "http://unlimited"},
+	}})
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	limited, unlimited := pool.endpoints[0], pool.endpoints[1]
+
+	e, _, err := pool.acquire(context.Background(), []*endpoint{limited, unlimited})
+	if err != nil || e != limited {
+		t.Fatalf("expected the limited endpoint while it has budget")
+	}
+
+	e, _, err = pool.acquire(context.Background(), []*endpoint{limited, unlimited})
+	if err != nil || e != unlimited {
+		t.Fatalf("expected to skip the exhausted endpoint")
+	}
+
+	// With only limited endpoints left acquire waits for budget
+	start := time.Now()
+	e, _, err = pool.acquire(context.Background(), []*endpoint{limited})
+	if err != nil || e != limited {
+		t.Fatalf("expected to wait for the limited endpoint: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("acquire returned after %s, expected to wait for the limiter", elapsed)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{fmt.Errorf("rpc call getSlot(): %w", io.ErrUnexpectedEOF), true},
+		{fmt.Errorf("rpc call getSlot(): %w", syscall.ECONNRESET), true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{fmt.Errorf("failed to decode result: %w", &json.UnmarshalTypeError{Value: "string"}), false},
+		{errors.New("invalid public key"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
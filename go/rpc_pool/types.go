@@ -0,0 +1,43 @@
+package rpc_pool
+
+import "time"
+
+// Endpoint is one RPC node in the pool
+type Endpoint struct {
+	URL string
+	// Weight is the relative share of requests sent to this endpoint, defaults to 1
+	Weight int
+	// RateLimit is the maximum requests per second, zero means unlimited
+	RateLimit float64
+	// Burst is the number of requests allowed above RateLimit at once, defaults to 1
+	Burst   int
+	Headers map[string]string
+}
+
+type Config struct {
+	Endpoints []Endpoint
+
+	// HealthCheckInterval is how often Run checks every endpoint, defaults to 15s
+	HealthCheckInterval time.Duration
+	// MaxSlotLag marks endpoints this many slots behind the highest one as unhealthy, defaults to 50
+	MaxSlotLag uint64
+	// Cooldown is how long an endpoint is skipped after a 429, 5xx or network error, defaults to 10s
+	Cooldown time.Duration
+	// HedgeDelay sends read calls to the next endpoint if the first hasn't
+	// answered within the delay. Zero disables hedging.
+	HedgeDelay time.Duration
+	// MaxAttempts is the number of endpoints tried per call, defaults to all of them
+	MaxAttempts int
+}
+
+// EndpointStatus is a snapshot of an endpoint's health
+type EndpointStatus struct {
+	URL       string    `json:"url"`
+	Healthy   bool      `json:"healthy"`
+	Slot      uint64    `json:"slot"`
+	SlotLag   uint64    `json:"slot_lag"`
+	CoolUntil time.Time `json:"cool_until,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	Requests  uint64    `json:"requests"`
+	Failures  uint64    `json:"failures"`
+}
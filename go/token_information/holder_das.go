@@ -0,0 +1,106 @@
+package token_information
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// DefaultDASPageSize is the most token accounts a DAS page may hold
+const DefaultDASPageSize = 1000
+
+// DASHolderProvider pages through the token accounts of a mint with the DAS
+// getTokenAccounts method, served by RPC providers such as Helius. It covers
+// Token-2022 and avoids the getProgramAccounts scan.
+type DASHolderProvider struct {
+	rpcClient *rpc.Client
+	pageSize  int
+}
+
+// NewDASHolderProvider pages with DefaultDASPageSize when pageSize is zero
+func NewDASHolderProvider(rpcClient *rpc.Client, pageSize int) *DASHolderProvider {
+	if pageSize <= 0 {
+		pageSize = DefaultDASPageSize
+	}
+	return &DASHolderProvider{rpcClient: rpcClient, pageSize: pageSize}
+}
+
+type dasTokenAccountsParams struct {
+	Mint  string `json:"mint"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+}
+
+type dasTokenAccountsResult struct {
+	Total         int `json:"total"`
+	TokenAccounts []struct {
+		Address solana.PublicKey `json:"address"`
+		Mint    solana.PublicKey `json:"mint"`
+		Owner   solana.PublicKey `json:"owner"`
+		Amount  uint64           `json:"amount"`
+	} `json:"token_accounts"`
+}
+
+func (p *DASHolderProvider) HolderCount(ctx context.Context, mint solana.PublicKey) (int, error) {
+	balances, err := p.TokenAccounts(ctx, mint)
+	if err != nil {
+		return 0, err
+	}
+	return countHolders(balances), nil
+}
+
+// TokenAccounts returns every token account of the mint, one page at a time
+func (p *DASHolderProvider) TokenAccounts(ctx context.Context, mint solana.PublicKey) ([]TokenAccountBalance, error) {
+	var balances []TokenAccountBalance
+	for page := 1; ; page++ {
+		result, err := p.getPage(ctx, mint, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, account := range result.TokenAccounts {
+			balances = append(balances, TokenAccountBalance{
+				Mint:    account.Mint,
+				Account: account.Address,
+				Owner:   account.Owner,
+				Amount:  account.Amount,
+			})
+		}
+
+		if len(result.TokenAccounts) < p.pageSize {
+			return balances, nil
+		}
+	}
+}
+
+// getPage calls getTokenAccounts. DAS methods take named parameters, which
+// the client only sends for requests built with jsonrpc.NewRequest.
+func (p *DASHolderProvider) getPage(ctx context.Context, mint solana.PublicKey, page int) (*dasTokenAccountsResult, error) {
+	request := jsonrpc.NewRequest("getTokenAccounts", dasTokenAccountsParams{
+		Mint:  mint.String(),
+		Page:  page,
+		Limit: p.pageSize,
+	})
+
+	responses, err := p.rpcClient.RPCCallBatch(ctx, jsonrpc.RPCRequests{request})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts page %d: %w", page, err)
+	}
+	if len(responses) != 1 {
+		return nil, fmt.Errorf("failed to get token accounts page %d: got %d responses", page, len(responses))
+	}
+	if responses[0].Error != nil {
+		rpcErr := responses[0].Error
+		return nil, fmt.Errorf("failed to get token accounts page %d: %s (code %d)", page, rpcErr.Message, rpcErr.Code)
+	}
+
+	var result dasTokenAccountsResult
+	if err := responses[0].GetObject(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode token accounts page %d: %w", page, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,154 @@
+package token_information
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrMintNotIndexed is returned for mints the indexer was never seeded with
+var ErrMintNotIndexed = errors.New("mint is not indexed")
+
+// HolderIndexer keeps holder counts in memory, updated incrementally from
+// the token balances of parsed transactions. Answers need no RPC call, but
+// are only complete when every transaction touching the mint since it was
+// seeded has been applied.
+type HolderIndexer struct {
+	mu    sync.Mutex
+	mints map[solana.PublicKey]*indexedMint
+}
+
+type indexedMint struct {
+	balances map[solana.PublicKey]uint64 // by token account
+	holders  int
+}
+
+func NewHolderIndexer() *HolderIndexer {
+	return &HolderIndexer{mints: make(map[solana.PublicKey]*indexedMint)}
+}
+
+// Seed starts indexing a mint from a snapshot, such as the TokenAccounts of
+// a scan or DAS provider, replacing anything indexed before. A mint indexed
+// from its creation is seeded with no balances.
+func (i *HolderIndexer) Seed(mint solana.PublicKey, balances []TokenAccountBalance) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	indexed := &indexedMint{balances: make(map[solana.PublicKey]uint64, len(balances))}
+	i.mints[mint] = indexed
+	for _, balance := range balances {
+		indexed.set(balance.Account, balance.Amount)
+	}
+}
+
+// Apply records token account balances as of a transaction. Balances of
+// mints that were not seeded are ignored.
+func (i *HolderIndexer) Apply(balances []TokenAccountBalance) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, balance := range balances {
+		if indexed, ok := i.mints[balance.Mint]; ok {
+			indexed.set(balance.Account, balance.Amount)
+		}
+	}
+}
+
+// ApplyTransaction applies the token balances a transaction left behind
+func (i *HolderIndexer) ApplyTransaction(tx *rpc.GetTransactionResult) error {
+	balances, err := BalancesFromTransaction(tx)
+	if err != nil {
+		return err
+	}
+	i.Apply(balances)
+	return nil
+}
+
+func (i *HolderIndexer) HolderCount(ctx context.Context, mint solana.PublicKey) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	indexed, ok := i.mints[mint]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMintNotIndexed, mint)
+	}
+	return indexed.holders, nil
+}
+
+func (m *indexedMint) set(account solana.PublicKey, amount uint64) {
+	previous := m.balances[account]
+	switch {
+	case previous == 0 && amount > 0:
+		m.holders++
+	case previous > 0 && amount == 0:
+		m.holders--
+	}
+
+	if amount == 0 {
+		delete(m.balances, account)
+		return
+	}
+	m.balances[account] = amount
+}
+
+// BalancesFromTransaction returns the balance of every token account a
+// successful transaction touched. Accounts it closed have a zero balance.
+func BalancesFromTransaction(tx *rpc.GetTransactionResult) ([]TokenAccountBalance, error) {
+	if tx.Meta == nil || tx.Meta.Err != nil {
+		return nil, nil
+	}
+
+	parsed, err := tx.Transaction.GetTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	accountKeys := append(parsed.Message.AccountKeys, tx.Meta.LoadedAddresses.Writable...)
+	accountKeys = append(accountKeys, tx.Meta.LoadedAddresses.ReadOnly...)
+
+	balanceOf := func(balance rpc.TokenBalance) (TokenAccountBalance, error) {
+		if int(balance.AccountIndex) >= len(accountKeys) {
+			return TokenAccountBalance{}, fmt.Errorf("token balance account index %d out of range", balance.AccountIndex)
+		}
+		out := TokenAccountBalance{Mint: balance.Mint, Account: accountKeys[balance.AccountIndex]}
+		if balance.Owner != nil {
+			out.Owner = *balance.Owner
+		}
+		return out, nil
+	}
+
+	post := make(map[uint16]bool, len(tx.Meta.PostTokenBalances))
+	balances := make([]TokenAccountBalance, 0, len(tx.Meta.PostTokenBalances))
+	for _, balance := range tx.Meta.PostTokenBalances {
+		out, err := balanceOf(balance)
+		if err != nil {
+			return nil, err
+		}
+		if balance.UiTokenAmount != nil {
+			if out.Amount, err = strconv.ParseUint(balance.UiTokenAmount.Amount, 10, 64); err != nil {
+				return nil, fmt.Errorf("failed to parse token balance: %w", err)
+			}
+		}
+		post[balance.AccountIndex] = true
+		balances = append(balances, out)
+	}
+
+	// Closed accounts only appear before the transaction
+	for _, balance := range tx.Meta.PreTokenBalances {
+		if post[balance.AccountIndex] {
+			continue
+		}
+		out, err := balanceOf(balance)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, out)
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,147 @@
+package token_information
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
+)
+
+// HolderProvider counts the holders of a mint: token accounts with a
+// non-zero balance
+type HolderProvider interface {
+	HolderCount(ctx context.Context, mint solana.PublicKey) (int, error)
+}
+
+type Option func(*TokenInformationTool)
+
+// WithHolderProvider counts holders with provider instead of a getProgramAccounts scan
+func WithHolderProvider(provider HolderProvider) Option {
+	return func(t *TokenInformationTool) {
+		t.holderProvider = provider
+	}
+}
+
+// WithMetadataResolver resolves metadata URIs with resolver instead of one
+// with the default gateways and limits
+func WithMetadataResolver(resolver *MetadataResolver) Option {
+	return func(t *TokenInformationTool) {
+		t.metadataResolver = resolver
+	}
+}
+
+// WithPriceOracle prices tokens with oracle instead of the default one
+func WithPriceOracle(oracle *price_oracle.Oracle) Option {
+	return func(t *TokenInformationTool) {
+		t.priceOracle = oracle
+	}
+}
+
+// WithKnownWallets labels holders owned by the given wallets, on top of the
+// exchange wallets. The labels are copied.
+func WithKnownWallets(wallets map[solana.PublicKey]string) Option {
+	return func(t *TokenInformationTool) {
+		for wallet, label := range wallets {
+			t.knownWallets[wallet] = label
+		}
+	}
+}
+
+// TokenAccountBalance is the balance of one token account of a mint
+type TokenAccountBalance struct {
+	Mint    solana.PublicKey
+	Account solana.PublicKey
+	Owner   solana.PublicKey
+	Amount  uint64
+}
+
+// countHolders counts the balances above zero
+func countHolders(balances []TokenAccountBalance) int {
+	holders := 0
+	for _, balance := range balances {
+		if balance.Amount > 0 {
+			holders++
+		}
+	}
+	return holders
+}
+
+// ScanHolderProvider scans every token account of the mint with
+// getProgramAccounts. It works against any RPC that allows the scan, but is
+// slow for popular mints and many providers block it.
+type ScanHolderProvider struct {
+	rpcClient *rpc.Client
+}
+
+func NewScanHolderProvider(rpcClient *rpc.Client) *ScanHolderProvider {
+	return &ScanHolderProvider{rpcClient: rpcClient}
+}
+
+func (p *ScanHolderProvider) HolderCount(ctx context.Context, mint solana.PublicKey) (int, error) {
+	balances, err := p.TokenAccounts(ctx, mint)
+	if err != nil {
+		return 0, err
+	}
+	return countHolders(balances), nil
+}
+
+// TokenAccounts returns every token account of the mint, under the Token or
+// the Token-2022 program depending on which owns the mint
+func (p *ScanHolderProvider) TokenAccounts(ctx context.Context, mint solana.PublicKey) ([]TokenAccountBalance, error) {
+	mintAccount, err := p.rpcClient.GetAccountInfo(ctx, mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint account: %w", err)
+	}
+	programID := mintAccount.Value.Owner
+
+	filters := []rpc.RPCFilter{
+		{
+			Memcmp: &rpc.RPCFilterMemcmp{
+				Offset: 0,
+				Bytes:  mint.Bytes(),
+			},
+		},
+	}
+
+	// Token-2022 accounts grow with their extensions
+	if !programID.Equals(solana.Token2022ProgramID) {
+		programID = solana.TokenProgramID
+		filters = append(filters, rpc.RPCFilter{
+			DataSize: 165, // Token account size
+		})
+	}
+
+	// Only the owner and amount are needed
+	offset := uint64(32)
+	length := uint64(40)
+
+	opts := &rpc.GetProgramAccountsOpts{
+		Filters:   filters,
+		Encoding:  solana.EncodingBase64,
+		DataSlice: &rpc.DataSlice{Offset: &offset, Length: &length},
+	}
+
+	accounts, err := p.rpcClient.GetProgramAccountsWithOpts(ctx, programID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program accounts: %w", err)
+	}
+
+	balances := make([]TokenAccountBalance, 0, len(accounts))
+	for _, account := range accounts {
+		data := account.Account.Data.GetBinary()
+		if len(data) < 40 {
+			continue
+		}
+		balances = append(balances, TokenAccountBalance{
+			Mint:    mint,
+			Account: account.Pubkey,
+			Owner:   solana.PublicKeyFromBytes(data[0:32]),
+			Amount:  binary.LittleEndian.Uint64(data[32:40]),
+		})
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,228 @@
+package token_information
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_simulator"
+)
+
+func TestScanHolderProviderSimulated(t *testing.T) {
+	ctx := context.Background()
+
+	sim := pumpfun_simulator.New()
+	creator, mint, err := sim.CreateToken(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewScanHolderProvider(sim.Client())
+
+	balances, err := provider.TokenAccounts(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to get token accounts: %v", err)
+	}
+	var creatorBalance uint64
+	for _, balance := range balances {
+		if balance.Owner.Equals(creator.PublicKey()) {
+			creatorBalance = balance.Amount
+		}
+	}
+	if want := sim.TokenBalance(creator.PublicKey(), mint); creatorBalance != want {
+		t.Errorf("creator balance = %d, want %d", creatorBalance, want)
+	}
+
+	// The bonding curve and the creator
+	if count, err := provider.HolderCount(ctx, mint); err != nil || count != 2 {
+		t.Errorf("holder count = %d, %v, want 2", count, err)
+	}
+}
+
+// dasStandIn serves getTokenAccounts over accounts, one page at a time
+func dasStandIn(t *testing.T, accounts []TokenAccountBalance) (*httptest.Server, *int) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []struct {
+			ID     json.RawMessage        `json:"id"`
+			Method string                 `json:"method"`
+			Params dasTokenAccountsParams `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil || len(requests) != 1 || requests[0].Method != "getTokenAccounts" {
+			t.Errorf("unexpected request: %+v, %v", requests, err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		request := requests[0]
+		pages++
+
+		start := min((request.Params.Page-1)*request.Params.Limit, len(accounts))
+		end := min(start+request.Params.Limit, len(accounts))
+		page := make([]map[string]interface{}, 0, end-start)
+		for _, account := range accounts[start:end] {
+			page = append(page, map[string]interface{}{
+				"address": account.Account.String(),
+				"mint":    account.Mint.String(),
+				"owner":   account.Owner.String(),
+				"amount":  account.Amount,
+			})
+		}
+
+		json.NewEncoder(w).Encode([]map[string]interface{}{{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result": map[string]interface{}{
+				"total":          len(page),
+				"limit":          request.Params.Limit,
+				"page":           request.Params.Page,
+				"token_accounts": page,
+			},
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return server, &pages
+}
+
+func TestDASHolderProvider(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+
+	var accounts []TokenAccountBalance
+	for _, amount := range []uint64{500, 0, 300, 200, 100} {
+		accounts = append(accounts, TokenAccountBalance{
+			Mint:    mint,
+			Account: solana.NewWallet().PublicKey(),
+			Owner:   solana.NewWallet().PublicKey(),
+			Amount:  amount,
+		})
+	}
+
+	server, pages := dasStandIn(t, accounts)
+	provider := NewDASHolderProvider(rpc.New(server.URL), 2)
+
+	count, err := provider.HolderCount(context.Background(), mint)
+	if err != nil {
+		t.Fatalf("failed to count holders: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("holder count = %d, want 4", count)
+	}
+	if *pages != 3 {
+		t.Errorf("pages requested = %d, want 3", *pages)
+	}
+}
+
+// transactionResult builds a getTransaction result that touches accounts
+func transactionResult(t *testing.T, accounts []solana.PublicKey, pre, post []rpc.TokenBalance) *rpc.GetTransactionResult {
+	t.Helper()
+
+	payer := solana.NewWallet().PublicKey()
+	instructions := make([]solana.Instruction, 0, len(accounts))
+	for _, account := range accounts {
+		instructions = append(instructions, system.NewTransferInstruction(1, payer, account).Build())
+	}
+	tx, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(payer))
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+
+	// Token balances refer to accounts by their index in the message
+	index := func(balances []rpc.TokenBalance) []rpc.TokenBalance {
+		for i := range balances {
+			account := accounts[balances[i].AccountIndex]
+			for j, key := range tx.Message.AccountKeys {
+				if key.Equals(account) {
+					balances[i].AccountIndex = uint16(j)
+				}
+			}
+		}
+		return balances
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{
+		"slot":        1,
+		"transaction": []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"meta": map[string]interface{}{
+			"err":               nil,
+			"preTokenBalances":  index(pre),
+			"postTokenBalances": index(post),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode transaction result: %v", err)
+	}
+
+	var result rpc.GetTransactionResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("failed to decode transaction result: %v", err)
+	}
+	return &result
+}
+
+func tokenBalance(accountIndex uint16, mint solana.PublicKey, amount uint64) rpc.TokenBalance {
+	return rpc.TokenBalance{
+		AccountIndex:  accountIndex,
+		Mint:          mint,
+		UiTokenAmount: &rpc.UiTokenAmount{Amount: fmt.Sprint(amount)},
+	}
+}
+
+func TestHolderIndexer(t *testing.T) {
+	ctx := context.Background()
+	mint := solana.NewWallet().PublicKey()
+	other := solana.NewWallet().PublicKey()
+	alice, bob, carol := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+
+	indexer := NewHolderIndexer()
+	if _, err := indexer.HolderCount(ctx, mint); !errors.Is(err, ErrMintNotIndexed) {
+		t.Fatalf("count of an unseeded mint = %v, want ErrMintNotIndexed", err)
+	}
+
+	indexer.Seed(mint, []TokenAccountBalance{
+		{Mint: mint, Account: alice, Amount: 1_000},
+		{Mint: mint, Account: bob, Amount: 0},
+	})
+	if count, _ := indexer.HolderCount(ctx, mint); count != 1 {
+		t.Fatalf("seeded count = %d, want 1", count)
+	}
+
+	// Alice sends everything to Bob and closes her account, Carol receives
+	// another mint that isn't indexed
+	tx := transactionResult(t, []solana.PublicKey{alice, bob, carol},
+		[]rpc.TokenBalance{tokenBalance(0, mint, 1_000), tokenBalance(1, mint, 0)},
+		[]rpc.TokenBalance{tokenBalance(1, mint, 1_000), tokenBalance(2, other, 5)},
+	)
+	if err := indexer.ApplyTransaction(tx); err != nil {
+		t.Fatalf("failed to apply transaction: %v", err)
+	}
+	if count, _ := indexer.HolderCount(ctx, mint); count != 1 {
+		t.Errorf("count after Alice sent to Bob = %d, want 1", count)
+	}
+
+	// Bob sends half to Carol
+	tx = transactionResult(t, []solana.PublicKey{bob, carol},
+		[]rpc.TokenBalance{tokenBalance(0, mint, 1_000)},
+		[]rpc.TokenBalance{tokenBalance(0, mint, 500), tokenBalance(1, mint, 500)},
+	)
+	if err := indexer.ApplyTransaction(tx); err != nil {
+		t.Fatalf("failed to apply transaction: %v", err)
+	}
+	if count, _ := indexer.HolderCount(ctx, mint); count != 2 {
+		t.Errorf("count after Bob sent to Carol = %d, want 2", count)
+	}
+
+	if _, err := indexer.HolderCount(ctx, other); !errors.Is(err, ErrMintNotIndexed) {
+		t.Errorf("count of a mint only seen in transfers = %v, want ErrMintNotIndexed", err)
+	}
+}
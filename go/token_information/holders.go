@@ -2,54 +2,113 @@ package token_information
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"sort"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 )
 
-func (t *TokenInformationTool) getHolderCount(ctx context.Context, tokenAddress solana.PublicKey) (int, error) {
-	filters := []rpc.RPCFilter{
-		{
-			DataSize: 165, // Token account size
-		},
-		{
-			Memcmp: &rpc.RPCFilterMemcmp{
-				Offset: 0,
-				Bytes:  tokenAddress.Bytes(),
-			},
-		},
+// tokenHolder is a token account and the wallet or program that owns it
+type tokenHolder struct {
+	Address solana.PublicKey
+	Owner   solana.PublicKey
+	Amount  uint64
+
+	// Label and Locked are set by labelHolders. Locked tokens are curve or
+	// pool liquidity, or burned, rather than a holder's balance.
+	Label  string
+	Locked bool
+}
+
+// getLargestHolders returns the largest token accounts of a mint, largest
+// first. The RPC returns at most 20.
+func (t *TokenInformationTool) getLargestHolders(ctx context.Context, mint solana.PublicKey) ([]tokenHolder, error) {
+	largest, err := t.rpcClient.GetTokenLargestAccounts(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get largest token accounts: %w", err)
+	}
+	if len(largest.Value) == 0 {
+		return nil, nil
 	}
 
-	offset := uint64(64)
-	length := uint64(8)
+	addresses := make([]solana.PublicKey, len(largest.Value))
+	for i, account := range largest.Value {
+		addresses[i] = account.Address
+	}
 
-	opts := &rpc.GetProgramAccountsOpts{
-		Filters:   filters,
-		Encoding:  solana.EncodingBase64,
-		DataSlice: &rpc.DataSlice{Offset: &offset, Length: &length},
+	accounts, err := t.rpcClient.GetMultipleAccounts(ctx, addresses...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts: %w", err)
 	}
 
-	accounts, err := t.rpcClient.GetProgramAccountsWithOpts(ctx, solana.TokenProgramID, opts)
+	holders := make([]tokenHolder, 0, len(addresses))
+	for i, account := range accounts.Value {
+		if account == nil {
+			continue
+		}
+		data := account.Data.GetBinary()
+		if len(data) < 72 {
+			continue
+		}
+		holders = append(holders, tokenHolder{
+			Address: addresses[i],
+			Owner:   solana.PublicKeyFromBytes(data[32:64]),
+			Amount:  binary.LittleEndian.Uint64(data[64:72]),
+		})
+	}
+
+	return holders, nil
+}
+
+// getOwnerBalance sums the balance of every account of a mint owned by owner
+func (t *TokenInformationTool) getOwnerBalance(ctx context.Context, owner, mint solana.PublicKey) (uint64, error) {
+	accounts, err := t.rpcClient.GetTokenAccountsByOwner(ctx, owner,
+		&rpc.GetTokenAccountsConfig{Mint: &mint},
+		&rpc.GetTokenAccountsOpts{Encoding: solana.EncodingBase64},
+	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get program accounts: %w", err)
+		return 0, fmt.Errorf("failed to get token accounts of %s: %w", owner, err)
 	}
 
-	isZeroBytes := func(data []byte) bool {
-		for _, b := range data {
-			if b != 0 {
-				return false
-			}
+	var balance uint64
+	for _, account := range accounts.Value {
+		data := account.Account.Data.GetBinary()
+		if len(data) >= 72 {
+			balance += binary.LittleEndian.Uint64(data[64:72])
 		}
-		return true
 	}
 
-	activeHolders := 0
-	for _, account := range accounts {
-		if !isZeroBytes(account.Account.Data.GetBinary()) {
-			activeHolders++
+	return balance, nil
+}
+
+// topHolders groups the labelled token accounts by owner and returns the
+// largest n with their share of supply
+func topHolders(holders []tokenHolder, mint *Mint, n int) []Holder {
+	var out []Holder
+	index := make(map[solana.PublicKey]int, len(holders))
+	for _, holder := range holders {
+		i, ok := index[holder.Owner]
+		if !ok {
+			i = len(out)
+			index[holder.Owner] = i
+			out = append(out, Holder{Owner: holder.Owner.String(), Label: holder.Label})
 		}
+		out[i].Balance += holder.Amount
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Balance > out[j].Balance
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+
+	for i := range out {
+		out[i].UIBalance = uiAmount(out[i].Balance, mint.Decimals)
+		out[i].Percentage = percentOf(out[i].Balance, mint.Supply)
 	}
 
-	return activeHolders, nil
+	return out
 }
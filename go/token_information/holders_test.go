@@ -0,0 +1,76 @@
+package token_information
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_simulator"
+)
+
+func TestTopHolders(t *testing.T) {
+	wallet := solana.NewWallet().PublicKey()
+	mint := &Mint{Supply: 1_000_000_000, Decimals: 6}
+
+	holders := []tokenHolder{
+		{Owner: BurnAddress, Amount: 100_000_000, Label: LabelBurn, Locked: true},
+		{Owner: wallet, Amount: 300_000_000},
+		{Owner: wallet, Amount: 50_000_000},
+		{Owner: solana.NewWallet().PublicKey(), Amount: 10_000_000},
+	}
+
+	top := topHolders(holders, mint, 2)
+	if len(top) != 2 {
+		t.Fatalf("top holders = %+v, want 2", top)
+	}
+
+	// Accounts of the same owner are summed
+	if top[0].Owner != wallet.String() || top[0].Balance != 350_000_000 || top[0].UIBalance != 350 || top[0].Percentage != 35 {
+		t.Errorf("largest holder = %+v, want %s with 35%%", top[0], wallet)
+	}
+	if top[1].Label != LabelBurn || top[1].Percentage != 10 {
+		t.Errorf("second holder = %+v, want the burn address with 10%%", top[1])
+	}
+}
+
+func TestLabelHoldersSimulated(t *testing.T) {
+	ctx := context.Background()
+
+	sim := pumpfun_simulator.New()
+	creator, mint, err := sim.CreateToken(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool, _ := NewTokenInformationTool(sim.Client())
+
+	holders, err := tool.getLargestHolders(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to get holders: %v", err)
+	}
+	creatorKey := creator.PublicKey()
+	if err := tool.labelHolders(ctx, mint, holders, &creatorKey, nil); err != nil {
+		t.Fatalf("failed to label holders: %v", err)
+	}
+
+	if len(holders) != 2 {
+		t.Fatalf("holders = %+v, want the curve and the creator", holders)
+	}
+	if holders[0].Label != LabelBondingCurve || !holders[0].Locked {
+		t.Errorf("largest holder = %+v, want the locked bonding curve", holders[0])
+	}
+	if holders[1].Label != LabelCreator || holders[1].Locked || !holders[1].Owner.Equals(creatorKey) {
+		t.Errorf("second holder = %+v, want the creator", holders[1])
+	}
+	// Known wallets are copied into the tool that was given them
+	labelled, _ := NewTokenInformationTool(sim.Client(), WithKnownWallets(map[solana.PublicKey]string{creatorKey: "Treasury"}))
+	if err := labelled.labelHolders(ctx, mint, holders, nil, nil); err != nil {
+		t.Fatalf("failed to label holders: %v", err)
+	}
+	if holders[1].Label != "Treasury" {
+		t.Errorf("second holder = %+v, want the known wallet label", holders[1])
+	}
+	if _, ok := tool.knownWallets[creatorKey]; ok {
+		t.Error("known wallet leaked into another tool")
+	}
+}
@@ -0,0 +1,128 @@
+package token_information
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/dexscreener"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+// Labels of holders that are not ordinary wallets
+const (
+	LabelBondingCurve = "pump.fun bonding curve"
+	LabelBurn         = "burn address"
+	LabelCreator      = "creator"
+)
+
+// exchangeWallets labels exchange wallets. Tools start with a copy, which
+// WithKnownWallets adds to.
+var exchangeWallets = map[solana.PublicKey]string{
+	solana.MustPublicKeyFromBase58("5tzFkiKscXHK5ZXCGbXZxdw7gTjjD1mBwuoFbhUvuAi9"): "Binance",
+	solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"): "Binance",
+	solana.MustPublicKeyFromBase58("H8sMJSCQxfKiFTCfDR3DUMLPwcRbM61LGFJ8N4dK3WjS"): "Coinbase",
+	solana.MustPublicKeyFromBase58("GJRs4FwHtemZ5ZE9x3FNvJ8TMwitKTh21yxdRPqn7npE"): "Coinbase",
+	solana.MustPublicKeyFromBase58("5VCwKtCXgCJ6kit5FybXjvriW3xELsFDhYrPSqtJNmcD"): "OKX",
+	solana.MustPublicKeyFromBase58("AC5RDfQFmDS1deWZos921JfqscXdByf8BKHs5ACWjtW2"): "Bybit",
+	solana.MustPublicKeyFromBase58("FWznbcNXWQuHTawe9RxvQ2LdCENssh12dsznf4RiouN5"): "Kraken",
+	solana.MustPublicKeyFromBase58("ASTyfSima4LLAdDgoFGkgqoKowG1LZFDr9fAQrg7iaJZ"): "MEXC",
+	solana.MustPublicKeyFromBase58("u6PJ8DtQuPFnfmwHbGFULQ4u4EgjDiyYKjVEsynXq2w"):  "Gate.io",
+	solana.MustPublicKeyFromBase58("BmFdpraQhkiDQE6SnfG5omcA1VwzqfXrwtNYBwWTymy6"): "KuCoin",
+}
+
+// poolPrograms label token accounts owned by a pool account of the program
+var poolPrograms = map[solana.PublicKey]string{
+	pumpfun.ProgramID: LabelBondingCurve,
+	solana.MustPublicKeyFromBase58("whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc"):  "Orca Whirlpool pool",
+	solana.MustPublicKeyFromBase58("CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK"): "Raydium CLMM pool",
+	solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo"):  "Meteora DLMM pool",
+	solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB"): "Meteora pool",
+	solana.MustPublicKeyFromBase58("pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA"):  "pump.fun AMM pool",
+}
+
+var dexNames = map[string]string{
+	"raydium":  "Raydium",
+	"orca":     "Orca",
+	"meteora":  "Meteora",
+	"pumpswap": "pump.fun AMM",
+}
+
+// labelHolders labels the holders it recognizes and marks the ones whose
+// tokens are liquidity or burned rather than a holder's balance
+func (t *TokenInformationTool) labelHolders(
+	ctx context.Context,
+	mint solana.PublicKey,
+	holders []tokenHolder,
+	creator *solana.PublicKey,
+	pairs []dexscreener.PairInformation,
+) error {
+	cpmmAuthority, err := raydium.DeriveCpmmAuthority()
+	if err != nil {
+		return err
+	}
+	bondingCurve, _, err := pumpfun.DeriveBondingCurveAddresses(mint)
+	if err != nil {
+		return fmt.Errorf("failed to derive bonding curve address: %w", err)
+	}
+
+	pools := map[solana.PublicKey]string{
+		bondingCurve:           LabelBondingCurve,
+		raydium.AmmV4Authority: "Raydium AMM v4 pool",
+		cpmmAuthority:          "Raydium CPMM pool",
+	}
+	for _, pair := range pairs {
+		address, err := solana.PublicKeyFromBase58(pair.PairAddress)
+		if err != nil {
+			continue
+		}
+		name, ok := dexNames[pair.DexID]
+		if !ok {
+			name = pair.DexID
+		}
+		pools[address] = name + " pool"
+	}
+
+	var unknown []solana.PublicKey
+	for i := range holders {
+		holder := &holders[i]
+		switch label, isPool := pools[holder.Owner]; {
+		case holder.Owner.Equals(BurnAddress):
+			holder.Label, holder.Locked = LabelBurn, true
+		case isPool:
+			holder.Label, holder.Locked = label, true
+		case creator != nil && holder.Owner.Equals(*creator):
+			holder.Label = LabelCreator
+		case t.knownWallets[holder.Owner] != "":
+			holder.Label = t.knownWallets[holder.Owner]
+		default:
+			unknown = append(unknown, holder.Owner)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	// Pools not listed by Dexscreener are recognized by the program owning them
+	accounts, err := t.rpcClient.GetMultipleAccounts(ctx, unknown...)
+	if err != nil {
+		return fmt.Errorf("failed to get holder accounts: %w", err)
+	}
+	programLabels := make(map[solana.PublicKey]string, len(unknown))
+	for i, account := range accounts.Value {
+		if account == nil {
+			continue
+		}
+		if label, ok := poolPrograms[account.Owner]; ok {
+			programLabels[unknown[i]] = label
+		}
+	}
+	for i := range holders {
+		if label, ok := programLabels[holders[i].Owner]; ok {
+			holders[i].Label, holders[i].Locked = label, true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package token_information
+
+import (
+	"fmt"
+	"math"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// decodeMint decodes the base mint shared by the token and Token-2022 programs
+func decodeMint(account *rpc.Account) (*Mint, error) {
+	if !account.Owner.Equals(solana.TokenProgramID) && !account.Owner.Equals(solana.Token2022ProgramID) {
+		return nil, fmt.Errorf("account is owned by %s, not a token program", account.Owner)
+	}
+
+	var mint token.Mint
+	if err := mint.UnmarshalWithDecoder(ag_binary.NewBinDecoder(account.Data.GetBinary())); err != nil {
+		return nil, fmt.Errorf("failed to decode mint: %w", err)
+	}
+	if !mint.IsInitialized {
+		return nil, fmt.Errorf("mint is not initialized")
+	}
+
+	return &Mint{
+		Supply:          mint.Supply,
+		UISupply:        uiAmount(mint.Supply, mint.Decimals),
+		Decimals:        mint.Decimals,
+		MintAuthority:   optionalAddress(mint.MintAuthority),
+		FreezeAuthority: optionalAddress(mint.FreezeAuthority),
+		TokenProgram:    account.Owner.String(),
+	}, nil
+}
+
+// uiAmount converts raw token units to whole tokens
+func uiAmount(amount uint64, decimals uint8) float64 {
+	return float64(amount) / math.Pow10(int(decimals))
+}
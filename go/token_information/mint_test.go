@@ -0,0 +1,54 @@
+package token_information
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestDecodeMint(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+
+	// COption<Pubkey> mint authority, supply, decimals, initialized, COption<Pubkey> freeze authority
+	data := make([]byte, 82)
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	copy(data[4:36], authority[:])
+	binary.LittleEndian.PutUint64(data[36:44], 1_000_000_000_000_000)
+	data[44] = 6
+	data[45] = 1
+
+	mint, err := decodeMint(&rpc.Account{
+		Owner: solana.TokenProgramID,
+		Data:  rpc.DataBytesOrJSONFromBytes(data),
+	})
+	if err != nil {
+		t.Fatalf("failed to decode mint: %v", err)
+	}
+
+	if mint.Supply != 1_000_000_000_000_000 || mint.UISupply != 1_000_000_000 || mint.Decimals != 6 {
+		t.Errorf("mint = %+v, want 1B tokens with 6 decimals", mint)
+	}
+	if mint.MintAuthority == nil || *mint.MintAuthority != authority.String() {
+		t.Errorf("mint authority = %v, want %s", mint.MintAuthority, authority)
+	}
+	if mint.FreezeAuthority != nil {
+		t.Errorf("freeze authority = %v, want revoked", *mint.FreezeAuthority)
+	}
+	if mint.TokenProgram != solana.TokenProgramID.String() {
+		t.Errorf("token program = %s", mint.TokenProgram)
+	}
+
+	// Token-2022 mints share the base layout
+	if _, err := decodeMint(token2022Mint()); err != nil {
+		t.Errorf("failed to decode token-2022 mint: %v", err)
+	}
+
+	if _, err := decodeMint(&rpc.Account{
+		Owner: solana.SystemProgramID,
+		Data:  rpc.DataBytesOrJSONFromBytes(data),
+	}); err == nil {
+		t.Error("expected an error for an account not owned by a token program")
+	}
+}
@@ -0,0 +1,277 @@
+package token_information
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Defaults of MetadataResolverConfig
+const (
+	DefaultIPFSGateway     = "https://ipfs.io/ipfs/"
+	DefaultArweaveGateway  = "https://arweave.net/"
+	DefaultMetadataMaxSize = 1 << 20
+	DefaultMetadataTimeout = 10 * time.Second
+	DefaultMetadataTTL     = time.Hour
+	DefaultMetadataFailTTL = time.Minute
+
+	maxCachedMetadata = 1_000
+)
+
+// MetadataResolverConfig configures how metadata URIs are fetched. Zero
+// values take the defaults.
+type MetadataResolverConfig struct {
+	// IPFSGateway and ArweaveGateway replace the ipfs:// and ar:// schemes
+	IPFSGateway    string
+	ArweaveGateway string
+	// MaxSize is the largest metadata document in bytes
+	MaxSize int
+	Timeout time.Duration
+	// CacheTTL is how long resolved metadata is reused
+	CacheTTL time.Duration
+	// FailureTTL is how long a failed fetch is reused before trying again,
+	// so dead URIs don't cost a timeout on every lookup
+	FailureTTL time.Duration
+	// AllowPrivateHosts allows URIs on loopback and private networks, and
+	// connecting through the proxy from the environment, which would reach
+	// them on the resolver's behalf. URIs come from token creators, so this
+	// is off outside of tests.
+	AllowPrivateHosts bool
+}
+
+// MetadataResolver fetches and parses the off-chain JSON a metadata URI points to
+type MetadataResolver struct {
+	config MetadataResolverConfig
+	client *resty.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedMetadata
+}
+
+// cachedMetadata is a resolved document, or the error fetching it failed with
+type cachedMetadata struct {
+	metadata *OffchainMetadata
+	err      error
+	expires  time.Time
+}
+
+// offchainMetadataDocument is the Metaplex token standard JSON, with the
+// social links pump.fun and other launchpads put at the top level
+type offchainMetadataDocument struct {
+	Name        string                 `json:"name"`
+	Symbol      string                 `json:"symbol"`
+	Description string                 `json:"description"`
+	Image       string                 `json:"image"`
+	ExternalURL string                 `json:"external_url"`
+	Website     string                 `json:"website"`
+	Twitter     string                 `json:"twitter"`
+	Telegram    string                 `json:"telegram"`
+	Discord     string                 `json:"discord"`
+	Extensions  map[string]interface{} `json:"extensions"`
+	Attributes  json.RawMessage        `json:"attributes"`
+	Properties  json.RawMessage        `json:"properties"`
+}
+
+func NewMetadataResolver(config MetadataResolverConfig) *MetadataResolver {
+	if config.IPFSGateway == "" {
+		config.IPFSGateway = DefaultIPFSGateway
+	}
+	if config.ArweaveGateway == "" {
+		config.ArweaveGateway = DefaultArweaveGateway
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultMetadataMaxSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultMetadataTimeout
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultMetadataTTL
+	}
+	if config.FailureTTL <= 0 {
+		config.FailureTTL = DefaultMetadataFailTTL
+	}
+
+	// The dialer checks the address it connects to, which is the proxy's
+	// rather than the URI's when there is one
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: config.Timeout,
+	}
+	if config.AllowPrivateHosts {
+		transport.Proxy = http.ProxyFromEnvironment
+	} else {
+		dialer.Control = denyPrivateHosts
+	}
+
+	client := resty.New().
+		SetTransport(transport).
+		SetTimeout(config.Timeout).
+		SetResponseBodyLimit(config.MaxSize)
+
+	return &MetadataResolver{
+		config: config,
+		client: client,
+		cache:  make(map[string]cachedMetadata),
+	}
+}
+
+// Resolve fetches the metadata a URI points to, from the cache when the same
+// document was resolved within the cache TTL or failed within the failure TTL
+func (r *MetadataResolver) Resolve(ctx context.Context, uri string) (*OffchainMetadata, error) {
+	// On-chain URIs are padded with NUL bytes
+	gatewayURL, err := r.GatewayURL(strings.TrimSpace(strings.TrimRight(uri, "\x00")))
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[gatewayURL]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.metadata, cached.err
+	}
+
+	metadata, err := r.fetch(ctx, gatewayURL)
+	// A canceled caller says nothing about the URI
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+
+	cached = cachedMetadata{metadata: metadata, err: err, expires: time.Now().Add(r.config.CacheTTL)}
+	if err != nil {
+		cached.expires = time.Now().Add(r.config.FailureTTL)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.cache) >= maxCachedMetadata {
+		r.evictExpired()
+	}
+	r.cache[gatewayURL] = cached
+
+	return metadata, err
+}
+
+// GatewayURL rewrites ipfs:// and ar:// URIs to the configured gateways
+func (r *MetadataResolver) GatewayURL(uri string) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse metadata uri: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+		return uri, nil
+	case "ipfs":
+		// Both ipfs://<cid>/path and ipfs://ipfs/<cid>/path are in use
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return withTrailingSlash(r.config.IPFSGateway) + path, nil
+	case "ar":
+		return withTrailingSlash(r.config.ArweaveGateway) + strings.TrimPrefix(uri, "ar://"), nil
+	default:
+		return "", fmt.Errorf("unsupported metadata uri scheme %q", parsed.Scheme)
+	}
+}
+
+func (r *MetadataResolver) fetch(ctx context.Context, gatewayURL string) (*OffchainMetadata, error) {
+	response, err := r.client.R().SetContext(ctx).Get(gatewayURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("failed to fetch metadata: %s", response.Status())
+	}
+
+	var document offchainMetadataDocument
+	if err := json.Unmarshal(response.Body(), &document); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	metadata := &OffchainMetadata{
+		Uri:         gatewayURL,
+		Name:        document.Name,
+		Symbol:      document.Symbol,
+		Description: document.Description,
+		Image:       document.Image,
+		ExternalURL: document.ExternalURL,
+		Extensions:  make(map[string]string),
+		Socials:     make([]Social, 0),
+		Attributes:  document.Attributes,
+		Properties:  document.Properties,
+	}
+
+	// Images are often on IPFS or Arweave too
+	if image, err := r.GatewayURL(document.Image); err == nil {
+		metadata.Image = image
+	}
+
+	for key, value := range document.Extensions {
+		if s, ok := value.(string); ok && s != "" {
+			metadata.Extensions[key] = s
+		}
+	}
+
+	seen := make(map[string]bool)
+	addSocial := func(socialType, link string) {
+		if link == "" || seen[socialType] {
+			return
+		}
+		seen[socialType] = true
+		metadata.Socials = append(metadata.Socials, Social{Type: socialType, URL: link})
+	}
+	addSocial("website", document.Website)
+	addSocial("twitter", document.Twitter)
+	addSocial("telegram", document.Telegram)
+	addSocial("discord", document.Discord)
+	for _, socialType := range []string{"website", "twitter", "telegram", "discord"} {
+		addSocial(socialType, metadata.Extensions[socialType])
+	}
+
+	return metadata, nil
+}
+
+// evictExpired drops expired entries, or the whole cache if none expired.
+// Callers hold r.mu.
+func (r *MetadataResolver) evictExpired() {
+	now := time.Now()
+	for uri, cached := range r.cache {
+		if now.After(cached.expires) {
+			delete(r.cache, uri)
+		}
+	}
+	if len(r.cache) >= maxCachedMetadata {
+		r.cache = make(map[string]cachedMetadata)
+	}
+}
+
+func withTrailingSlash(s string) string {
+	if strings.HasSuffix(s, "/") {
+		return s
+	}
+	return s + "/"
+}
+
+// denyPrivateHosts refuses connections to loopback, private and link-local
+// addresses, so metadata URIs can't reach internal services
+func denyPrivateHosts(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+		return fmt.Errorf("metadata host %s is not public", host)
+	}
+	return nil
+}
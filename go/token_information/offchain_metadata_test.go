@@ -0,0 +1,100 @@
+package token_information
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetadataResolver(t *testing.T) {
+	ctx := context.Background()
+
+	requests := make(map[string]int)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/ipfs/QmToken", "/ar/tx", "/token.json":
+			w.Write([]byte(`{
+				"name": "Test",
+				"symbol": "TEST",
+				"description": "A test token",
+				"image": "ipfs://QmImage",
+				"twitter": "https://x.com/test",
+				"extensions": {"website": "https://test.example", "telegram": "https://t.me/test", "decimals": 6},
+				"properties": {"files": [{"uri": "ipfs://QmImage", "type": "image/png"}]}
+			}`))
+		case "/large.json":
+			w.Write([]byte(`{"description": "` + strings.Repeat("a", 2048) + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gateway.Close()
+
+	resolver := NewMetadataResolver(MetadataResolverConfig{
+		IPFSGateway:       gateway.URL + "/ipfs",
+		ArweaveGateway:    gateway.URL + "/ar/",
+		MaxSize:           1024,
+		AllowPrivateHosts: true,
+	})
+
+	for _, uri := range []string{"ipfs://QmToken", "ipfs://ipfs/QmToken", "ar://tx", gateway.URL + "/token.json\x00\x00"} {
+		metadata, err := resolver.Resolve(ctx, uri)
+		if err != nil {
+			t.Fatalf("failed to resolve %q: %v", uri, err)
+		}
+		if metadata.Description != "A test token" {
+			t.Errorf("%q: description = %q", uri, metadata.Description)
+		}
+		if metadata.Image != gateway.URL+"/ipfs/QmImage" {
+			t.Errorf("%q: image = %q, want the IPFS gateway URL", uri, metadata.Image)
+		}
+		if len(metadata.Socials) != 3 {
+			t.Errorf("%q: socials = %+v, want twitter, website and telegram", uri, metadata.Socials)
+		}
+		if _, ok := metadata.Extensions["decimals"]; ok {
+			t.Errorf("%q: non-string extension kept", uri)
+		}
+		if len(metadata.Properties) == 0 {
+			t.Errorf("%q: properties missing", uri)
+		}
+	}
+
+	// ipfs://<cid> and ipfs://ipfs/<cid> are the same document
+	if requests["/ipfs/QmToken"] != 1 {
+		t.Errorf("gateway requests for the IPFS document = %d, want 1 cached", requests["/ipfs/QmToken"])
+	}
+
+	for _, uri := range []string{gateway.URL + "/large.json", gateway.URL + "/missing.json", "file:///etc/passwd", ""} {
+		if _, err := resolver.Resolve(ctx, uri); err == nil {
+			t.Errorf("resolved %q, want an error", uri)
+		}
+	}
+
+	// Failures are cached too
+	if _, err := resolver.Resolve(ctx, gateway.URL+"/missing.json"); err == nil {
+		t.Error("resolved a missing document from the cache")
+	}
+	if requests["/missing.json"] != 1 {
+		t.Errorf("gateway requests for the missing document = %d, want 1 cached", requests["/missing.json"])
+	}
+}
+
+func TestMetadataResolverPrivateHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	resolver := NewMetadataResolver(MetadataResolverConfig{})
+	if _, err := resolver.Resolve(context.Background(), server.URL); err == nil {
+		t.Error("resolved a loopback URI")
+	}
+
+	// A proxy would connect to the URI's host on the resolver's behalf
+	if transport := resolver.client.GetClient().Transport.(*http.Transport); transport.Proxy != nil {
+		t.Error("private hosts are denied but requests go through the environment proxy")
+	}
+}
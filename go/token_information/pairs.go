@@ -0,0 +1,38 @@
+package token_information
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/dexscreener"
+)
+
+// pairMarketCap is the market cap of the mint priced in a pair. Dexscreener's
+// market cap is the base token's, so pairs where the mint is the quote are
+// valued from its price and supply.
+func pairMarketCap(pair dexscreener.PairInformation, mint solana.PublicKey, mintInfo *Mint) float64 {
+	if pair.BaseToken.Address == mint.String() {
+		return pair.MarketCap
+	}
+	usd, _ := pair.TokenPrice(mint.String())
+	return usd * mintInfo.UISupply
+}
+
+// pools lists the pairs with the mint's price in each
+func pools(pairs []dexscreener.PairInformation, mint solana.PublicKey) []Pool {
+	out := make([]Pool, len(pairs))
+	for i, pair := range pairs {
+		pairedToken, pairedSymbol := pair.PairedToken(mint.String())
+		priceUsd, priceNative := pair.TokenPrice(mint.String())
+
+		out[i] = Pool{
+			DexID:        pair.DexID,
+			PairAddress:  pair.PairAddress,
+			URL:          pair.URL,
+			PairedToken:  pairedToken,
+			PairedSymbol: pairedSymbol,
+			LiquidityUSD: pair.Liquidity.Usd,
+			PriceUSD:     priceUsd,
+			PriceNative:  priceNative,
+		}
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package token_information
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/dexscreener"
+)
+
+func testPair(chain, address, base, quote string, liquidity float64, priceUsd, priceNative string, marketCap float64) dexscreener.PairInformation {
+	var pair dexscreener.PairInformation
+	pair.ChainID = chain
+	pair.PairAddress = address
+	pair.BaseToken.Address = base
+	pair.QuoteToken.Address = quote
+	pair.Liquidity.Usd = liquidity
+	pair.PriceUsd = priceUsd
+	pair.PriceNative = priceNative
+	pair.MarketCap = marketCap
+	return pair
+}
+
+func TestTokenPairs(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	other := solana.NewWallet().PublicKey().String()
+	sol := solana.SolMint.String()
+
+	pairs := dexscreener.TokenPairs([]dexscreener.PairInformation{
+		testPair("solana", "dust", mint.String(), sol, 10, "1", "0.01", 1_000),
+		testPair("ethereum", "bridged", mint.String(), "0xusdc", 1_000_000, "1", "1", 1_000),
+		testPair("solana", "unrelated", other, sol, 500_000, "5", "0.05", 5_000),
+		testPair("solana", "quoted", other, mint.String(), 50_000, "2", "4", 2_000),
+		testPair("solana", "deep", mint.String(), sol, 20_000, "1", "0.01", 1_000),
+	}, mint.String())
+
+	var addresses []string
+	for _, pair := range pairs {
+		addresses = append(addresses, pair.PairAddress)
+	}
+	if want := []string{"quoted", "deep", "dust"}; len(addresses) != len(want) || addresses[0] != want[0] || addresses[1] != want[1] || addresses[2] != want[2] {
+		t.Fatalf("pairs = %v, want %v", addresses, want)
+	}
+
+	// The canonical pair quotes in the mint: 1 base costs 4 of the mint at
+	// $2, so the mint is worth $0.50
+	mintInfo := &Mint{UISupply: 1_000}
+	if marketCap := pairMarketCap(pairs[0], mint, mintInfo); math.Abs(marketCap-500) > 1e-9 {
+		t.Errorf("market cap from a quote pair = %f, want 500", marketCap)
+	}
+	if marketCap := pairMarketCap(pairs[1], mint, mintInfo); marketCap != 1_000 {
+		t.Errorf("market cap from a base pair = %f, want 1000", marketCap)
+	}
+
+	listed := pools(pairs, mint)
+	if listed[0].PairedToken != other || math.Abs(listed[0].PriceUSD-0.5) > 1e-9 || math.Abs(listed[0].PriceNative-0.25) > 1e-9 {
+		t.Errorf("quote pool = %+v, want priced at $0.50 and 0.25 of the base", listed[0])
+	}
+	if listed[1].PairedToken != sol || listed[1].PriceUSD != 1 || listed[1].LiquidityUSD != 20_000 {
+		t.Errorf("base pool = %+v", listed[1])
+	}
+}
@@ -2,6 +2,7 @@ package token_information
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gagliardetto/solana-go"
@@ -29,3 +30,32 @@ func (t *TokenInformationTool) IsPumpFunToken(mint solana.PublicKey) (bool, erro
 	// If account data exists, it's a pump fun token
 	return account != nil && len(account.Value.Data.GetBinary()) > 0, nil
 }
+
+// getBondingCurve reads the pump.fun bonding curve of a mint, returning nil
+// when the mint has none. decimals convert token reserves to whole tokens.
+func (t *TokenInformationTool) getBondingCurve(ctx context.Context, mint solana.PublicKey, decimals uint8) (*BondingCurve, error) {
+	curve, err := pumpfun.GetBondingCurve(ctx, t.rpcClient, mint)
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	out := &BondingCurve{
+		VirtualSolReserves:   curve.VirtualSolReserves,
+		VirtualTokenReserves: curve.VirtualTokenReserves,
+		RealSolReserves:      curve.RealSolReserves,
+		RealTokenReserves:    curve.RealTokenReserves,
+		RealTokenReservesUI:  uiAmount(curve.RealTokenReserves, decimals),
+		PriceSol:             curve.PriceSol(),
+		MarketCapSol:         curve.MarketCapSol(),
+		Progress:             curve.Progress(),
+		Complete:             curve.Complete,
+	}
+	if !curve.Creator.IsZero() {
+		out.Creator = curve.Creator.String()
+	}
+
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package token_information
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_simulator"
+)
+
+func TestBondingCurveSimulated(t *testing.T) {
+	ctx := context.Background()
+
+	sim := pumpfun_simulator.New()
+	_, mint, err := sim.CreateToken(ctx, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool, err := NewTokenInformationTool(sim.Client())
+	if err != nil {
+		t.Fatalf("failed to create token information tool: %v", err)
+	}
+
+	isPumpFun, err := tool.IsPumpFunToken(mint)
+	if err != nil || !isPumpFun {
+		t.Fatalf("IsPumpFunToken = %v, %v, want true", isPumpFun, err)
+	}
+
+	curve, err := tool.getBondingCurve(ctx, mint, 6)
+	if err != nil {
+		t.Fatalf("failed to get bonding curve: %v", err)
+	}
+	if curve == nil || curve.Complete {
+		t.Fatalf("curve = %+v, want an active curve", curve)
+	}
+	if curve.Progress <= 0 || curve.Progress >= 100 {
+		t.Errorf("progress = %f, want between 0 and 100", curve.Progress)
+	}
+	if curve.MarketCapSol <= 28 {
+		t.Errorf("market cap = %f SOL, want above the initial ~28 SOL", curve.MarketCapSol)
+	}
+
+	other := solana.NewWallet().PublicKey()
+	if curve, err := tool.getBondingCurve(ctx, other, 6); err != nil || curve != nil {
+		t.Errorf("curve of a non pump.fun mint = %+v, %v, want nil", curve, err)
+	}
+	if isPumpFun, err := tool.IsPumpFunToken(other); err != nil || isPumpFun {
+		t.Errorf("IsPumpFunToken of a non pump.fun mint = %v, %v, want false", isPumpFun, err)
+	}
+}
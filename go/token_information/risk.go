@@ -0,0 +1,440 @@
+package token_information
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/dexscreener"
+	"github.com/soralabs/solana-toolkit/go/internal/raydium"
+)
+
+// Names of the risk checks
+const (
+	RiskCheckAuthorities         = "authorities"
+	RiskCheckMetadata            = "metadata"
+	RiskCheckHolderConcentration = "holder_concentration"
+	RiskCheckLiquidity           = "liquidity"
+	RiskCheckCreatorHolding      = "creator_holding"
+	RiskCheckToken2022           = "token_2022"
+)
+
+// Risk levels, from the overall score
+const (
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+var (
+	// BurnAddress is the incinerator, a wallet nobody holds the key of
+	BurnAddress = solana.MustPublicKeyFromBase58("1nc1nerator11111111111111111111111111111111")
+
+	raydiumLockProgramID = solana.MustPublicKeyFromBase58("LockrWmn6K5twhz3y9w1dQERbmgSaRkfnTeTKbpofwE")
+
+	// lockerAuthorities are the PDAs that own the LP tokens a locker program
+	// holds until an unlock date. They hold no data, so they are recognized
+	// by address rather than by the program owning them.
+	lockerAuthorities = map[solana.PublicKey]string{
+		lockerAuthority(raydiumLockProgramID, "lock_cp_authority_seed"): "Raydium LP lock",
+	}
+
+	// lockerPrograms own the data accounts some lockers hold LP tokens with,
+	// such as one escrow per stream
+	lockerPrograms = map[solana.PublicKey]string{
+		raydiumLockProgramID: "Raydium LP lock",
+		solana.MustPublicKeyFromBase58("strmRqUCoQUgGUan5YhzUZa6KqdzwX5L6FpUxfmKg5m"): "Streamflow",
+	}
+)
+
+func lockerAuthority(programID solana.PublicKey, seed string) solana.PublicKey {
+	authority, _, err := solana.FindProgramAddress([][]byte{[]byte(seed)}, programID)
+	if err != nil {
+		panic(err)
+	}
+	return authority
+}
+
+// lockerOf names the locker holding the tokens of owner, whose account is
+// nil when it holds no data, or returns "" when owner is not a locker
+func lockerOf(owner solana.PublicKey, account *rpc.Account) string {
+	if name, ok := lockerAuthorities[owner]; ok {
+		return name
+	}
+	if account != nil {
+		return lockerPrograms[account.Owner]
+	}
+	return ""
+}
+
+// riskInputs is the on-chain state the risk checks score
+type riskInputs struct {
+	Mint         *Mint
+	Metadata     Metadata
+	Token2022    *Token2022
+	BondingCurve *BondingCurve
+
+	// Holders are the largest token accounts, excluding locked ones
+	Holders []tokenHolder
+
+	// Creator is nil when the creator is unknown
+	Creator        *solana.PublicKey
+	CreatorBalance uint64
+
+	// Liquidity is nil when there's no pool, or Pool is nil when the deepest
+	// pool is not one whose LP tokens can be checked
+	Liquidity *liquidityInputs
+
+	// Unavailable holds why the state of a check could not be read, by name
+	Unavailable map[string]error
+}
+
+type liquidityInputs struct {
+	Dex         string
+	PairAddress string
+	Pool        *lpStatus
+}
+
+// lpStatus is how much of a pool's LP supply can no longer be withdrawn
+type lpStatus struct {
+	// Supply is the LP supply the pool minted, burned tokens included
+	Supply uint64
+	Burned uint64
+	Locked uint64
+}
+
+// getRisk reads the on-chain state behind the risk checks and scores it.
+// holders are the largest holders, labelled by labelHolders. The assessment is
+// best effort: a check whose state can't be read is marked unavailable.
+func (t *TokenInformationTool) getRisk(
+	ctx context.Context,
+	tokenAddress solana.PublicKey,
+	mint *Mint,
+	metadata Metadata,
+	token2022 *Token2022,
+	bondingCurve *BondingCurve,
+	pairs []dexscreener.PairInformation,
+	holders []tokenHolder,
+) *Risk {
+	inputs := riskInputs{
+		Mint:         mint,
+		Metadata:     metadata,
+		Token2022:    token2022,
+		BondingCurve: bondingCurve,
+		Unavailable:  make(map[string]error),
+	}
+
+	for _, holder := range holders {
+		if !holder.Locked {
+			inputs.Holders = append(inputs.Holders, holder)
+		}
+	}
+
+	if creator := tokenCreator(metadata, bondingCurve); creator != nil {
+		balance, err := t.getOwnerBalance(ctx, *creator, tokenAddress)
+		if err != nil {
+			inputs.Unavailable[RiskCheckCreatorHolding] = err
+		}
+		inputs.Creator = creator
+		inputs.CreatorBalance = balance
+	}
+
+	if len(pairs) > 0 {
+		// The deepest pool is the one a rug would drain
+		pair := pairs[0]
+		inputs.Liquidity = &liquidityInputs{Dex: pair.DexID, PairAddress: pair.PairAddress}
+		if address, err := solana.PublicKeyFromBase58(pair.PairAddress); err == nil {
+			if inputs.Liquidity.Pool, err = t.getLPStatus(ctx, address); err != nil {
+				inputs.Unavailable[RiskCheckLiquidity] = err
+			}
+		}
+	}
+
+	return assessRisk(inputs)
+}
+
+// tokenCreator returns the pump.fun curve creator, or else the first
+// verified Metaplex creator
+func tokenCreator(metadata Metadata, bondingCurve *BondingCurve) *solana.PublicKey {
+	if bondingCurve != nil && bondingCurve.Creator != "" {
+		if creator, err := solana.PublicKeyFromBase58(bondingCurve.Creator); err == nil {
+			return &creator
+		}
+	}
+	for _, creator := range metadata.Data.Creators {
+		if creator.Verified {
+			address := creator.Address
+			return &address
+		}
+	}
+	return nil
+}
+
+// getLPStatus reads how much of the LP supply of a Raydium AMM v4 or CPMM
+// pool is burned or locked. It returns nil for other pools, whose liquidity
+// is not held as LP tokens.
+func (t *TokenInformationTool) getLPStatus(ctx context.Context, address solana.PublicKey) (*lpStatus, error) {
+	account, err := t.rpcClient.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool account: %w", err)
+	}
+
+	var lpMint solana.PublicKey
+	var status lpStatus
+	data := account.Value.Data.GetBinary()
+	switch {
+	case account.Value.Owner.Equals(raydium.AmmV4ProgramID):
+		var pool raydium.AmmV4Pool
+		if err := pool.FromBuffer(data); err != nil {
+			return nil, fmt.Errorf("failed to parse AMM v4 pool: %w", err)
+		}
+		lpMint, status.Supply = pool.LpMint, pool.LpAmount
+	case account.Value.Owner.Equals(raydium.CpmmProgramID):
+		var pool raydium.CpmmPool
+		if err := pool.FromBuffer(data); err != nil {
+			return nil, fmt.Errorf("failed to parse CPMM pool: %w", err)
+		}
+		lpMint, status.Supply = pool.LpMint, pool.LpSupply
+	default:
+		return nil, nil
+	}
+
+	mintAccount, err := t.rpcClient.GetAccountInfo(ctx, lpMint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LP mint: %w", err)
+	}
+	mint, err := decodeMint(mintAccount.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode LP mint: %w", err)
+	}
+
+	// Burning LP tokens lowers the mint supply but not the pool's count
+	if status.Supply > mint.Supply {
+		status.Burned = status.Supply - mint.Supply
+	}
+
+	holders, err := t.getLargestHolders(ctx, lpMint)
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []solana.PublicKey
+	for _, holder := range holders {
+		if holder.Owner.Equals(BurnAddress) {
+			status.Burned += holder.Amount
+			continue
+		}
+		owners = append(owners, holder.Owner)
+	}
+	if len(owners) == 0 {
+		return &status, nil
+	}
+
+	// Locked LP tokens sit in accounts owned by a locker program's PDAs
+	ownerAccounts, err := t.rpcClient.GetMultipleAccounts(ctx, owners...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LP holders: %w", err)
+	}
+	locked := make(map[solana.PublicKey]bool, len(owners))
+	for i, account := range ownerAccounts.Value {
+		locked[owners[i]] = lockerOf(owners[i], account) != ""
+	}
+	for _, holder := range holders {
+		if locked[holder.Owner] {
+			status.Locked += holder.Amount
+		}
+	}
+
+	return &status, nil
+}
+
+// assessRisk scores every check. The overall score is the highest check
+// score, since a single unsafe check is enough to lose funds.
+func assessRisk(inputs riskInputs) *Risk {
+	checks := []RiskCheck{
+		authorityRisk(inputs.Mint),
+		metadataRisk(inputs.Metadata),
+		concentrationRisk(inputs.Holders, inputs.Mint),
+		liquidityRisk(inputs.Liquidity, inputs.BondingCurve),
+		creatorRisk(inputs.Creator, inputs.CreatorBalance, inputs.Mint),
+		token2022Risk(inputs.Token2022),
+	}
+
+	for i := range checks {
+		if err, ok := inputs.Unavailable[checks[i].Name]; ok {
+			checks[i] = newRiskCheck(checks[i].Name)
+			checks[i].Unavailable = true
+			checks[i].Flags = append(checks[i].Flags, fmt.Sprintf("%s check unavailable: %v", checks[i].Name, err))
+		}
+	}
+
+	risk := &Risk{Flags: make([]string, 0), Checks: checks}
+	for _, check := range checks {
+		risk.Score = max(risk.Score, check.Score)
+		risk.Flags = append(risk.Flags, check.Flags...)
+	}
+
+	switch {
+	case risk.Score >= 60:
+		risk.Level = RiskLevelHigh
+	case risk.Score >= 30:
+		risk.Level = RiskLevelMedium
+	default:
+		risk.Level = RiskLevelLow
+	}
+
+	return risk
+}
+
+func newRiskCheck(name string) RiskCheck {
+	return RiskCheck{Name: name, Flags: make([]string, 0)}
+}
+
+func (c *RiskCheck) flag(score int, format string, args ...interface{}) {
+	c.Score = min(c.Score+score, 100)
+	c.Flags = append(c.Flags, fmt.Sprintf(format, args...))
+}
+
+func authorityRisk(mint *Mint) RiskCheck {
+	check := newRiskCheck(RiskCheckAuthorities)
+	if mint.MintAuthority != nil {
+		check.flag(60, "mint authority %s can mint more supply", *mint.MintAuthority)
+	}
+	if mint.FreezeAuthority != nil {
+		check.flag(60, "freeze authority %s can freeze holder accounts", *mint.FreezeAuthority)
+	}
+	return check
+}
+
+func metadataRisk(metadata Metadata) RiskCheck {
+	check := newRiskCheck(RiskCheckMetadata)
+	if metadata.IsMutable {
+		check.flag(20, "metadata can be changed by update authority %s", metadata.UpdateAuthority)
+	}
+	return check
+}
+
+func concentrationRisk(holders []tokenHolder, mint *Mint) RiskCheck {
+	check := newRiskCheck(RiskCheckHolderConcentration)
+	if mint.Supply == 0 || len(holders) == 0 {
+		return check
+	}
+
+	top := holders
+	if len(top) > 10 {
+		top = top[:10]
+	}
+	var total uint64
+	for _, holder := range top {
+		total += holder.Amount
+	}
+
+	topPct := percentOf(total, mint.Supply)
+	switch {
+	case topPct >= 50:
+		check.flag(80, "top %d holders own %.1f%% of supply", len(top), topPct)
+	case topPct >= 30:
+		check.flag(50, "top %d holders own %.1f%% of supply", len(top), topPct)
+	case topPct >= 15:
+		check.flag(20, "top %d holders own %.1f%% of supply", len(top), topPct)
+	}
+
+	if largestPct := percentOf(holders[0].Amount, mint.Supply); largestPct >= 10 {
+		check.Score = max(check.Score, 60)
+		check.Flags = append(check.Flags, fmt.Sprintf("%s holds %.1f%% of supply", holders[0].Owner, largestPct))
+	}
+
+	return check
+}
+
+func liquidityRisk(liquidity *liquidityInputs, bondingCurve *BondingCurve) RiskCheck {
+	check := newRiskCheck(RiskCheckLiquidity)
+
+	// Liquidity on an active curve belongs to the program and can't be pulled
+	if bondingCurve != nil && !bondingCurve.Complete {
+		return check
+	}
+
+	switch {
+	case liquidity == nil:
+		check.flag(50, "no liquidity pool found")
+	case liquidity.Pool == nil:
+		check.flag(20, "liquidity of the %s pool %s cannot be checked for burned or locked LP tokens", liquidity.Dex, liquidity.PairAddress)
+	default:
+		pool := liquidity.Pool
+		burnedPct := percentOf(pool.Burned, pool.Supply)
+		safePct := percentOf(pool.Burned+pool.Locked, pool.Supply)
+		switch {
+		case safePct < 50:
+			check.flag(80, "only %.1f%% of LP tokens are burned or locked (%.1f%% burned)", safePct, burnedPct)
+		case safePct < 95:
+			check.flag(40, "%.1f%% of LP tokens can still be withdrawn", 100-safePct)
+		}
+		if pool.Locked > 0 {
+			check.Flags = append(check.Flags, fmt.Sprintf("%.1f%% of LP tokens are locked and can be withdrawn after unlocking", percentOf(pool.Locked, pool.Supply)))
+		}
+	}
+
+	return check
+}
+
+func creatorRisk(creator *solana.PublicKey, balance uint64, mint *Mint) RiskCheck {
+	check := newRiskCheck(RiskCheckCreatorHolding)
+	if creator == nil || mint.Supply == 0 {
+		return check
+	}
+
+	pct := percentOf(balance, mint.Supply)
+	switch {
+	case pct >= 20:
+		check.flag(80, "creator %s holds %.1f%% of supply", creator, pct)
+	case pct >= 10:
+		check.flag(50, "creator %s holds %.1f%% of supply", creator, pct)
+	case pct >= 5:
+		check.flag(25, "creator %s holds %.1f%% of supply", creator, pct)
+	}
+
+	return check
+}
+
+func token2022Risk(token2022 *Token2022) RiskCheck {
+	check := newRiskCheck(RiskCheckToken2022)
+	if token2022 == nil {
+		return check
+	}
+
+	score := 0
+	if token2022.PermanentDelegate != nil || token2022.NonTransferable {
+		score += 100
+	}
+	if token2022.TransferHook != nil && token2022.TransferHook.ProgramID != nil {
+		score += 60
+	}
+	if token2022.DefaultAccountState == "frozen" {
+		score += 60
+	}
+	if fee := token2022.TransferFee; fee != nil {
+		switch {
+		case fee.BasisPoints >= 1_000:
+			score += 80
+		case fee.BasisPoints > 0:
+			score += 30
+		}
+		if fee.ConfigAuthority != nil {
+			score += 20
+		}
+	}
+
+	// The extension decoder already explains each dangerous extension
+	check.Score = min(score, 100)
+	check.Flags = append(check.Flags, token2022.Warnings...)
+	return check
+}
+
+func percentOf(amount, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(amount) / float64(total) * 100
+}
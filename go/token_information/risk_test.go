@@ -0,0 +1,168 @@
+package token_information
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun_simulator"
+)
+
+func riskCheck(t *testing.T, risk *Risk, name string) RiskCheck {
+	t.Helper()
+	for _, check := range risk.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("risk has no %s check", name)
+	return RiskCheck{}
+}
+
+func TestAssessRisk(t *testing.T) {
+	authority := solana.NewWallet().PublicKey().String()
+	supply := uint64(1_000_000_000)
+
+	safe := assessRisk(riskInputs{
+		Mint:      &Mint{Supply: supply, Decimals: 6},
+		Holders:   []tokenHolder{{Amount: supply / 50}, {Amount: supply / 50}},
+		Liquidity: &liquidityInputs{Dex: "raydium", Pool: &lpStatus{Supply: 1_000, Burned: 1_000}},
+	})
+	if safe.Score != 0 || safe.Level != RiskLevelLow || len(safe.Flags) != 0 {
+		t.Errorf("safe token risk = %+v, want a score of 0 without flags", safe)
+	}
+
+	risky := assessRisk(riskInputs{
+		Mint:      &Mint{Supply: supply, Decimals: 6, MintAuthority: &authority, FreezeAuthority: &authority},
+		Metadata:  Metadata{IsMutable: true},
+		Holders:   []tokenHolder{{Amount: supply / 2}},
+		Liquidity: &liquidityInputs{Dex: "raydium", Pool: &lpStatus{Supply: 1_000, Burned: 100, Locked: 100}},
+		Token2022: &Token2022{PermanentDelegate: &authority, Warnings: []string{"permanent delegate"}},
+	})
+	if risky.Score != 100 || risky.Level != RiskLevelHigh {
+		t.Errorf("risky token score = %d %s, want 100 high", risky.Score, risky.Level)
+	}
+
+	for name, want := range map[string]int{
+		RiskCheckAuthorities:         100,
+		RiskCheckMetadata:            20,
+		RiskCheckHolderConcentration: 80,
+		RiskCheckLiquidity:           80,
+		RiskCheckToken2022:           100,
+	} {
+		if check := riskCheck(t, risky, name); check.Score != want || len(check.Flags) == 0 {
+			t.Errorf("%s check = %+v, want score %d with flags", name, check, want)
+		}
+	}
+}
+
+func TestLiquidityRisk(t *testing.T) {
+	if check := liquidityRisk(nil, &BondingCurve{}); check.Score != 0 {
+		t.Errorf("active curve = %+v, want no risk", check)
+	}
+	if check := liquidityRisk(nil, nil); check.Score != 50 {
+		t.Errorf("no pool = %+v, want 50", check)
+	}
+	if check := liquidityRisk(&liquidityInputs{Dex: "orca"}, nil); check.Score != 20 {
+		t.Errorf("unverifiable pool = %+v, want 20", check)
+	}
+
+	// 90% burned and locked leaves 10% withdrawable
+	check := liquidityRisk(&liquidityInputs{Dex: "raydium", Pool: &lpStatus{Supply: 1_000, Burned: 500, Locked: 400}}, nil)
+	if check.Score != 40 || len(check.Flags) != 2 {
+		t.Errorf("partially burned pool = %+v, want 40 with a withdrawable and a locked flag", check)
+	}
+}
+
+func TestUnavailableRisk(t *testing.T) {
+	supply := uint64(1_000_000_000)
+	creator := solana.NewWallet().PublicKey()
+
+	risk := assessRisk(riskInputs{
+		Mint:        &Mint{Supply: supply, Decimals: 6},
+		Creator:     &creator,
+		Liquidity:   &liquidityInputs{Dex: "raydium"},
+		Unavailable: map[string]error{RiskCheckLiquidity: errors.New("rpc timeout")},
+	})
+
+	check := riskCheck(t, risk, RiskCheckLiquidity)
+	if !check.Unavailable || check.Score != 0 || len(check.Flags) != 1 {
+		t.Errorf("liquidity check = %+v, want unavailable without a score", check)
+	}
+	if check := riskCheck(t, risk, RiskCheckCreatorHolding); check.Unavailable {
+		t.Errorf("creator check = %+v, want available", check)
+	}
+}
+
+func TestLockerOf(t *testing.T) {
+	authority := lockerAuthority(raydiumLockProgramID, "lock_cp_authority_seed")
+
+	// The lock authority is a PDA without data
+	if locker := lockerOf(authority, nil); locker != "Raydium LP lock" {
+		t.Errorf("locker of the Raydium lock authority = %q", locker)
+	}
+	if locker := lockerOf(solana.NewWallet().PublicKey(), &rpc.Account{Owner: raydiumLockProgramID}); locker != "Raydium LP lock" {
+		t.Errorf("locker of a lock program account = %q", locker)
+	}
+	if locker := lockerOf(solana.NewWallet().PublicKey(), &rpc.Account{Owner: solana.SystemProgramID}); locker != "" {
+		t.Errorf("locker of a wallet = %q, want none", locker)
+	}
+	if locker := lockerOf(solana.NewWallet().PublicKey(), nil); locker != "" {
+		t.Errorf("locker of an empty address = %q, want none", locker)
+	}
+}
+
+func TestRiskSimulated(t *testing.T) {
+	ctx := context.Background()
+
+	sim := pumpfun_simulator.New()
+	creator, mint, err := sim.CreateToken(ctx, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool, _ := NewTokenInformationTool(sim.Client())
+
+	mintAccount, err := sim.Client().GetAccountInfo(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to get mint account: %v", err)
+	}
+	mintInfo, err := decodeMint(mintAccount.Value)
+	if err != nil {
+		t.Fatalf("failed to decode mint: %v", err)
+	}
+	curve, err := tool.getBondingCurve(ctx, mint, mintInfo.Decimals)
+	if err != nil {
+		t.Fatalf("failed to get bonding curve: %v", err)
+	}
+
+	holders, err := tool.getLargestHolders(ctx, mint)
+	if err != nil {
+		t.Fatalf("failed to get holders: %v", err)
+	}
+	if err := tool.labelHolders(ctx, mint, holders, tokenCreator(Metadata{}, curve), nil); err != nil {
+		t.Fatalf("failed to label holders: %v", err)
+	}
+
+	risk := tool.getRisk(ctx, mint, mintInfo, Metadata{}, nil, curve, nil, holders)
+
+	// The creator bought a large share and is the only holder besides the curve
+	creatorBalance := sim.TokenBalance(creator.PublicKey(), mint)
+	concentration := riskCheck(t, risk, RiskCheckHolderConcentration)
+	if concentration.Score == 0 {
+		t.Errorf("concentration = %+v, want the creator's %d tokens flagged", concentration, creatorBalance)
+	}
+	if holding := riskCheck(t, risk, RiskCheckCreatorHolding); holding.Score == 0 {
+		t.Errorf("creator holding = %+v, want the creator flagged", holding)
+	}
+
+	// Pump.fun revokes both authorities and liquidity is held by the curve
+	if check := riskCheck(t, risk, RiskCheckAuthorities); check.Score != 0 {
+		t.Errorf("authorities = %+v, want none", check)
+	}
+	if check := riskCheck(t, risk, RiskCheckLiquidity); check.Score != 0 {
+		t.Errorf("liquidity = %+v, want none on an active curve", check)
+	}
+}
@@ -0,0 +1,151 @@
+package token_information
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/token2022"
+)
+
+// getToken2022 decodes the extensions of a Token-2022 mint, returning nil for
+// legacy mints. decimals convert raw fee amounts to whole tokens.
+func (t *TokenInformationTool) getToken2022(ctx context.Context, mintAccount *rpc.Account, decimals uint8) (*Token2022, error) {
+	if !mintAccount.Owner.Equals(token2022.ProgramID) {
+		return nil, nil
+	}
+
+	extensions, err := token2022.DecodeMintExtensions(mintAccount.Data.GetBinary())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token-2022 extensions: %w", err)
+	}
+
+	out := &Token2022{
+		Extensions:      make([]string, 0, len(extensions.Types)),
+		NonTransferable: extensions.NonTransferable,
+		Warnings:        make([]string, 0),
+	}
+	for _, extensionType := range extensions.Types {
+		out.Extensions = append(out.Extensions, extensionType.String())
+	}
+
+	if config := extensions.TransferFeeConfig; config != nil {
+		epoch, err := t.rpcClient.GetEpochInfo(ctx, rpc.CommitmentConfirmed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get epoch info: %w", err)
+		}
+
+		current := config.FeeAt(epoch.Epoch)
+		out.TransferFee = &TransferFee{
+			BasisPoints:       current.BasisPoints,
+			MaximumFee:        current.MaximumFee,
+			MaximumFeeUI:      uiAmount(current.MaximumFee, decimals),
+			ConfigAuthority:   optionalAddress(config.ConfigAuthority),
+			WithdrawAuthority: optionalAddress(config.WithdrawAuthority),
+		}
+		if config.NewerTransferFee.Epoch > epoch.Epoch {
+			out.TransferFee.Upcoming = &UpcomingTransferFee{
+				Epoch:        config.NewerTransferFee.Epoch,
+				BasisPoints:  config.NewerTransferFee.BasisPoints,
+				MaximumFee:   config.NewerTransferFee.MaximumFee,
+				MaximumFeeUI: uiAmount(config.NewerTransferFee.MaximumFee, decimals),
+			}
+		}
+
+		if current.BasisPoints > 0 {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("transfer fee of %d bps is taken on every transfer", current.BasisPoints))
+		}
+		if config.ConfigAuthority != nil {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("transfer fee authority %s can raise the fee", config.ConfigAuthority))
+		}
+	}
+
+	if delegate := extensions.PermanentDelegate; delegate != nil {
+		out.PermanentDelegate = optionalAddress(delegate)
+		out.Warnings = append(out.Warnings, fmt.Sprintf("permanent delegate %s can transfer or burn tokens from any holder", delegate))
+	}
+
+	if hook := extensions.TransferHook; hook != nil {
+		out.TransferHook = &TransferHook{
+			Authority: optionalAddress(hook.Authority),
+			ProgramID: optionalAddress(hook.ProgramID),
+		}
+		if hook.ProgramID != nil {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("transfer hook program %s runs on every transfer and can block sells", hook.ProgramID))
+		}
+		if hook.Authority != nil {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("transfer hook authority %s can change the hook program", hook.Authority))
+		}
+	}
+
+	if extensions.NonTransferable {
+		out.Warnings = append(out.Warnings, "token is non-transferable and cannot be sold")
+	}
+
+	if state := extensions.DefaultAccountState; state != nil {
+		out.DefaultAccountState = state.String()
+		if *state == token2022.AccountStateFrozen {
+			out.Warnings = append(out.Warnings, "new token accounts start frozen until the freeze authority thaws them")
+		}
+	}
+
+	if config := extensions.InterestBearingConfig; config != nil {
+		out.InterestBearing = &InterestBearing{
+			RateAuthority: optionalAddress(config.RateAuthority),
+			CurrentRate:   config.CurrentRate,
+		}
+	}
+
+	if authority := extensions.MintCloseAuthority; authority != nil {
+		out.MintCloseAuthority = optionalAddress(authority)
+	}
+
+	if pointer := extensions.MetadataPointer; pointer != nil {
+		out.MetadataPointer = &MetadataPointer{
+			Authority:       optionalAddress(pointer.Authority),
+			MetadataAddress: optionalAddress(pointer.MetadataAddress),
+		}
+	}
+
+	if metadata := extensions.TokenMetadata; metadata != nil {
+		out.TokenMetadata = &TokenMetadata{
+			UpdateAuthority:    optionalAddress(metadata.UpdateAuthority),
+			Name:               metadata.Name,
+			Symbol:             metadata.Symbol,
+			Uri:                metadata.Uri,
+			AdditionalMetadata: make(map[string]string, len(metadata.AdditionalMetadata)),
+		}
+		for _, entry := range metadata.AdditionalMetadata {
+			out.TokenMetadata.AdditionalMetadata[entry[0]] = entry[1]
+		}
+	}
+
+	return out, nil
+}
+
+// metadataFromToken2022 fills Metadata from metadata embedded in the mint,
+// for Token-2022 mints without a Metaplex metadata account
+func metadataFromToken2022(mint solana.PublicKey, metadata *TokenMetadata) Metadata {
+	out := Metadata{
+		Mint: mint,
+		Data: MetadataData{
+			Name:   metadata.Name,
+			Symbol: metadata.Symbol,
+			Uri:    metadata.Uri,
+		},
+		IsMutable: metadata.UpdateAuthority != nil,
+	}
+	if metadata.UpdateAuthority != nil {
+		out.UpdateAuthority = solana.MustPublicKeyFromBase58(*metadata.UpdateAuthority)
+	}
+	return out
+}
+
+func optionalAddress(key *solana.PublicKey) *string {
+	if key == nil {
+		return nil
+	}
+	address := key.String()
+	return &address
+}
@@ -0,0 +1,96 @@
+package token_information
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/internal/token2022"
+)
+
+func token2022Mint(extensions ...token2022.Extension) *rpc.Account {
+	data := make([]byte, token2022.AccountSize+1)
+	data[45] = 1 // initialized
+	data[token2022.AccountSize] = byte(token2022.AccountTypeMint)
+	for _, extension := range extensions {
+		data = binary.LittleEndian.AppendUint16(data, uint16(extension.Type))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(extension.Data)))
+		data = append(data, extension.Data...)
+	}
+
+	return &rpc.Account{
+		Owner: token2022.ProgramID,
+		Data:  rpc.DataBytesOrJSONFromBytes(data),
+	}
+}
+
+func TestGetToken2022(t *testing.T) {
+	tool, _ := NewTokenInformationTool(nil)
+	mint := solana.NewWallet().PublicKey()
+	delegate := solana.NewWallet().PublicKey()
+	hookProgram := solana.NewWallet().PublicKey()
+
+	hook := make([]byte, 64)
+	copy(hook[32:], hookProgram[:])
+
+	metadata := make([]byte, 32) // no update authority
+	metadata = append(metadata, mint[:]...)
+	for _, s := range []string{"Token", "TKN", "https://example.com/token.json"} {
+		metadata = binary.LittleEndian.AppendUint32(metadata, uint32(len(s)))
+		metadata = append(metadata, s...)
+	}
+	metadata = binary.LittleEndian.AppendUint32(metadata, 0)
+
+	out, err := tool.getToken2022(context.Background(), token2022Mint(
+		token2022.Extension{Type: token2022.ExtensionPermanentDelegate, Data: delegate[:]},
+		token2022.Extension{Type: token2022.ExtensionTransferHook, Data: hook},
+		token2022.Extension{Type: token2022.ExtensionDefaultAccountState, Data: []byte{byte(token2022.AccountStateFrozen)}},
+		token2022.Extension{Type: token2022.ExtensionTokenMetadata, Data: metadata},
+	), 6)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	want := []string{"permanent_delegate", "transfer_hook", "default_account_state", "token_metadata"}
+	if len(out.Extensions) != len(want) {
+		t.Fatalf("extensions = %v, want %v", out.Extensions, want)
+	}
+	for i := range want {
+		if out.Extensions[i] != want[i] {
+			t.Errorf("extension %d = %s, want %s", i, out.Extensions[i], want[i])
+		}
+	}
+
+	if out.PermanentDelegate == nil || *out.PermanentDelegate != delegate.String() {
+		t.Errorf("permanent delegate = %v, want %s", out.PermanentDelegate, delegate)
+	}
+	if out.TransferHook == nil || *out.TransferHook.ProgramID != hookProgram.String() || out.TransferHook.Authority != nil {
+		t.Errorf("transfer hook = %+v", out.TransferHook)
+	}
+	if out.DefaultAccountState != "frozen" {
+		t.Errorf("default account state = %q, want frozen", out.DefaultAccountState)
+	}
+	// Delegate, hook program and frozen accounts each warn
+	if len(out.Warnings) != 3 {
+		t.Errorf("warnings = %v, want 3", out.Warnings)
+	}
+
+	md := metadataFromToken2022(mint, out.TokenMetadata)
+	if md.Data.Name != "Token" || md.Data.Symbol != "TKN" || md.IsMutable || !md.Mint.Equals(mint) {
+		t.Errorf("metadata = %+v", md)
+	}
+}
+
+func TestGetToken2022LegacyMint(t *testing.T) {
+	tool, _ := NewTokenInformationTool(nil)
+
+	out, err := tool.getToken2022(context.Background(), &rpc.Account{
+		Owner: solana.TokenProgramID,
+		Data:  rpc.DataBytesOrJSONFromBytes(make([]byte, 82)),
+	}, 6)
+	if err != nil || out != nil {
+		t.Errorf("legacy mint = %+v, %v, want nil", out, err)
+	}
+}
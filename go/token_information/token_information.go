@@ -4,31 +4,55 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
+	"maps"
 	"sync"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 
 	"github.com/soralabs/solana-toolkit/go/internal/dexscreener"
-	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
 	toolkit "github.com/soralabs/toolkit/go"
 )
 
+const (
+	// DefaultTopHolders is how many holders are listed when the input doesn't say
+	DefaultTopHolders = 10
+	// MaxTopHolders is the most holders getTokenLargestAccounts returns
+	MaxTopHolders = 20
+)
+
 type TokenInformationTool struct {
 	toolkit.Tool
 
 	mu sync.Mutex
 
 	rpcClient *rpc.Client
+
+	holderProvider   HolderProvider
+	metadataResolver *MetadataResolver
+	priceOracle      *price_oracle.Oracle
+	knownWallets     map[solana.PublicKey]string
 }
 
 // NewTokenInformationTool creates a new instance of TokenInformationTool with the given RPC client.
 // This tool is responsible for fetching information about Solana tokens.
-func NewTokenInformationTool(rpcClient *rpc.Client) (*TokenInformationTool, error) {
-	return &TokenInformationTool{
-		rpcClient: rpcClient,
-	}, nil
+// Holders are counted with a getProgramAccounts scan unless WithHolderProvider says otherwise,
+// and prices come from the default price oracle unless WithPriceOracle says otherwise.
+func NewTokenInformationTool(rpcClient *rpc.Client, opts ...Option) (*TokenInformationTool, error) {
+	t := &TokenInformationTool{
+		rpcClient:        rpcClient,
+		holderProvider:   NewScanHolderProvider(rpcClient),
+		metadataResolver: NewMetadataResolver(MetadataResolverConfig{}),
+		priceOracle:      price_oracle.NewDefault(rpcClient),
+		knownWallets:     maps.Clone(exchangeWallets),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
 }
 
 // GetName returns the name of the tool.
@@ -54,6 +78,10 @@ func (t *TokenInformationTool) GetSchema() toolkit.Schema {
 				"token_address": {
 					"type": "string",
 					"description": "The address of the token"
+				},
+				"top_holders": {
+					"type": "integer",
+					"description": "How many of the largest holders to list, 10 by default and at most 20"
 				}
 			}
 		}`),
@@ -64,9 +92,12 @@ func (t *TokenInformationTool) GetSchema() toolkit.Schema {
 // It handles the following steps:
 // 1. Parses the input parameters.
 // 2. Validates and converts the token address.
-// 3. Fetches metadata, holder count, and pair information for the token.
-// 4. Checks whether the token is associated with the PumpFun platform.
-// 5. Consolidates and formats the data into a JSON response.
+// 3. Reads the mint: supply, decimals, authorities and Token-2022 extensions.
+// 4. Fetches metadata, holder count, labelled top holders, and the Solana pairs trading the token, deepest first.
+// 5. Reads the pump.fun bonding curve, if the token has one.
+// 6. Scores the rug risk from authorities, holders, liquidity and extensions.
+// 7. Prices the token in USD and SOL with the price oracle.
+// 8. Consolidates and formats the data into a JSON response.
 func (t *TokenInformationTool) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -81,17 +112,49 @@ func (t *TokenInformationTool) Execute(ctx context.Context, params json.RawMessa
 		return nil, fmt.Errorf("failed to parse token address: %w", err)
 	}
 
+	topHolderCount := input.TopHolders
+	if topHolderCount <= 0 {
+		topHolderCount = DefaultTopHolders
+	}
+	if topHolderCount > MaxTopHolders {
+		return nil, fmt.Errorf("top_holders must be at most %d", MaxTopHolders)
+	}
+
+	mintAccount, err := t.rpcClient.GetAccountInfo(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint account: %w", err)
+	}
+
+	mint, err := decodeMint(mintAccount.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	token2022, err := t.getToken2022(ctx, mintAccount.Value, mint.Decimals)
+	if err != nil {
+		return nil, err
+	}
+
 	metadata, err := t.getMetadata(ctx, tokenAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get metadata: %w", err)
+		// Token-2022 mints may carry their metadata instead of using Metaplex
+		if token2022 == nil || token2022.TokenMetadata == nil {
+			return nil, fmt.Errorf("failed to get metadata: %w", err)
+		}
+		metadata = metadataFromToken2022(tokenAddress, token2022.TokenMetadata)
 	}
 
-	isPumpFunToken, err := t.IsPumpFunToken(tokenAddress)
+	// Off-chain metadata is best effort: URIs are often dead or slow
+	offchainMetadata, _ := t.metadataResolver.Resolve(ctx, metadata.Data.Uri)
+
+	bondingCurve, err := t.getBondingCurve(ctx, tokenAddress, mint.Decimals)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if token is pump fun: %w", err)
+		return nil, fmt.Errorf("failed to get bonding curve: %w", err)
 	}
+	isPumpFunToken := bondingCurve != nil
 
-	pairs, err := dexscreener.GetPairInformation(ctx, input.TokenAddress)
+	// The first pair is the canonical one
+	pairs, err := dexscreener.GetPairInformation(ctx, tokenAddress.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pair information: %w", err)
 	}
@@ -100,23 +163,53 @@ func (t *TokenInformationTool) Execute(ctx context.Context, params json.RawMessa
 		return nil, fmt.Errorf("not a valid tradeable token")
 	}
 
-	holderCount, err := t.getHolderCount(ctx, tokenAddress)
+	holders, err := t.getLargestHolders(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.labelHolders(ctx, tokenAddress, holders, tokenCreator(metadata, bondingCurve), pairs); err != nil {
+		return nil, fmt.Errorf("failed to label holders: %w", err)
+	}
+
+	risk := t.getRisk(ctx, tokenAddress, mint, metadata, token2022, bondingCurve, pairs, holders)
+
+	holderCount, err := t.holderProvider.HolderCount(ctx, tokenAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get holder count: %w", err)
 	}
 
+	// A missing price only leaves the price fields empty, the flags of a
+	// found price say how far to trust it
+	price, _ := t.priceOracle.Price(ctx, tokenAddress)
+
 	if isPumpFunToken && len(pairs) == 0 {
-		pfInfo, err := pumpfun.GetTokenInformation(ctx, input.TokenAddress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get PumpFun token information: %w", err)
+		// Without a pair the USD market cap is valued at the oracle price.
+		// Market data in SOL is always available from the bonding curve.
+		var usdMarketCap string
+		if price != nil && price.USD > 0 {
+			usdMarketCap = fmt.Sprintf("%f", price.USD*mint.UISupply)
+		}
+
+		// Without a DEX listing, socials come from the metadata JSON
+		socials := make([]Social, 0)
+		if offchainMetadata != nil {
+			socials = offchainMetadata.Socials
 		}
 
 		return json.Marshal(TokenInformationOutput{
-			Metadata:       metadata,
-			IsPumpFunToken: true,
-			USDMarketCap:   fmt.Sprintf("%f", pfInfo.UsdMarketCap),
-			Socials:        make([]Social, 0),
-			HolderCount:    holderCount,
+			Mint:             mint,
+			Metadata:         metadata,
+			OffchainMetadata: offchainMetadata,
+			IsPumpFunToken:   true,
+			Price:            price,
+			USDMarketCap:     usdMarketCap,
+			Socials:          socials,
+			HolderCount:      holderCount,
+			TopHolders:       topHolders(holders, mint, topHolderCount),
+			Pools:            make([]Pool, 0),
+			BondingCurve:     bondingCurve,
+			Token2022:        token2022,
+			Risk:             risk,
 		})
 	}
 
@@ -130,18 +223,37 @@ func (t *TokenInformationTool) Execute(ctx context.Context, params json.RawMessa
 		}
 	}
 
-	return json.Marshal(TokenInformationOutput{
-		Metadata:       metadata,
-		IsPumpFunToken: isPumpFunToken,
-		USDMarketCap:   fmt.Sprintf("%f", mainPair.MarketCap),
-		Socials:        socials,
-		HolderCount:    holderCount,
-		PriceChange: &PriceChange{
+	if len(socials) == 0 && offchainMetadata != nil {
+		socials = offchainMetadata.Socials
+	}
+
+	// Dexscreener tracks the price of the base token, which is another token
+	// when the canonical pair quotes in this one
+	var priceChange *PriceChange
+	if mainPair.BaseToken.Address == tokenAddress.String() {
+		priceChange = &PriceChange{
 			H24: mainPair.PriceChange.H24,
 			H6:  mainPair.PriceChange.H6,
 			H1:  mainPair.PriceChange.H1,
 			M5:  mainPair.PriceChange.M5,
-		},
+		}
+	}
+
+	return json.Marshal(TokenInformationOutput{
+		Mint:             mint,
+		Metadata:         metadata,
+		OffchainMetadata: offchainMetadata,
+		IsPumpFunToken:   isPumpFunToken,
+		Price:            price,
+		USDMarketCap:     fmt.Sprintf("%f", pairMarketCap(mainPair, tokenAddress, mint)),
+		Socials:          socials,
+		HolderCount:      holderCount,
+		TopHolders:       topHolders(holders, mint, topHolderCount),
+		Pools:            pools(pairs, tokenAddress),
+		BondingCurve:     bondingCurve,
+		Token2022:        token2022,
+		Risk:             risk,
+		PriceChange:      priceChange,
 		Volume: &Volume{
 			USD24H: mainPair.Volume.H24,
 			USD6H:  mainPair.Volume.H6,
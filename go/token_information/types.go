@@ -1,20 +1,160 @@
 package token_information
 
-import "github.com/gagliardetto/solana-go"
+import (
+	"encoding/json"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
+)
 
 type TokenInformationInput struct {
 	TokenAddress string `json:"token_address"`
+	// TopHolders is how many of the largest holders to list, up to MaxTopHolders
+	TopHolders int `json:"top_holders,omitempty"`
 }
 
 type TokenInformationOutput struct {
-	Metadata       Metadata `json:"metadata"`
-	USDMarketCap   string   `json:"usd_market_cap"`
-	IsPumpFunToken bool     `json:"is_pump_fun_token"`
-	Socials        []Social `json:"socials"`
-	HolderCount    int      `json:"holder_count"`
+	Mint             *Mint             `json:"mint"`
+	Metadata         Metadata          `json:"metadata"`
+	OffchainMetadata *OffchainMetadata `json:"offchain_metadata,omitempty"`
+	// Price is the oracle price, nil when no source could price the token
+	Price          *price_oracle.Price `json:"price"`
+	USDMarketCap   string              `json:"usd_market_cap"`
+	IsPumpFunToken bool                `json:"is_pump_fun_token"`
+	Socials        []Social            `json:"socials"`
+	HolderCount    int                 `json:"holder_count"`
+	TopHolders     []Holder            `json:"top_holders"`
+	// Pools are the Solana pairs trading the token, deepest first. Market
+	// cap, price change and volume come from the first, the canonical pair.
+	Pools []Pool `json:"pools"`
 
 	PriceChange *PriceChange `json:"price_change"`
 	Volume      *Volume      `json:"volume"`
+
+	// BondingCurve is read on-chain for pump.fun tokens
+	BondingCurve *BondingCurve `json:"bonding_curve,omitempty"`
+
+	// Token2022 holds the extensions of Token-2022 mints
+	Token2022 *Token2022 `json:"token_2022,omitempty"`
+
+	Risk *Risk `json:"risk"`
+}
+
+// Risk is a rug-risk assessment computed from on-chain data. Scores run from
+// 0, nothing found, to 100. Flags collects the flags of every check.
+type Risk struct {
+	Score  int         `json:"score"`
+	Level  string      `json:"level"`
+	Flags  []string    `json:"flags"`
+	Checks []RiskCheck `json:"checks"`
+}
+
+// RiskCheck is the score of a single risk check with the reasons for it.
+// Unavailable checks could not read their on-chain state and score 0.
+type RiskCheck struct {
+	Name        string   `json:"name"`
+	Score       int      `json:"score"`
+	Flags       []string `json:"flags"`
+	Unavailable bool     `json:"unavailable,omitempty"`
+}
+
+// Token2022 reports the extensions of a Token-2022 mint. Warnings explain
+// extensions that let someone other than the holder move, block or tax tokens.
+type Token2022 struct {
+	Extensions []string `json:"extensions"`
+	Warnings   []string `json:"warnings"`
+
+	TransferFee         *TransferFee     `json:"transfer_fee,omitempty"`
+	PermanentDelegate   *string          `json:"permanent_delegate,omitempty"`
+	TransferHook        *TransferHook    `json:"transfer_hook,omitempty"`
+	NonTransferable     bool             `json:"non_transferable"`
+	DefaultAccountState string           `json:"default_account_state,omitempty"`
+	InterestBearing     *InterestBearing `json:"interest_bearing,omitempty"`
+	MintCloseAuthority  *string          `json:"mint_close_authority,omitempty"`
+	MetadataPointer     *MetadataPointer `json:"metadata_pointer,omitempty"`
+	TokenMetadata       *TokenMetadata   `json:"token_metadata,omitempty"`
+}
+
+// TransferFee is the fee in effect for the current epoch. MaximumFee is in
+// raw token units and MaximumFeeUI in whole tokens.
+type TransferFee struct {
+	BasisPoints       uint16               `json:"basis_points"`
+	MaximumFee        uint64               `json:"maximum_fee"`
+	MaximumFeeUI      float64              `json:"maximum_fee_ui"`
+	ConfigAuthority   *string              `json:"config_authority"`
+	WithdrawAuthority *string              `json:"withdraw_authority"`
+	Upcoming          *UpcomingTransferFee `json:"upcoming,omitempty"`
+}
+
+// UpcomingTransferFee is a fee change that takes effect at Epoch
+type UpcomingTransferFee struct {
+	Epoch        uint64  `json:"epoch"`
+	BasisPoints  uint16  `json:"basis_points"`
+	MaximumFee   uint64  `json:"maximum_fee"`
+	MaximumFeeUI float64 `json:"maximum_fee_ui"`
+}
+
+type TransferHook struct {
+	Authority *string `json:"authority"`
+	ProgramID *string `json:"program_id"`
+}
+
+// InterestBearing accrues interest on UI amounts at CurrentRate basis points a year
+type InterestBearing struct {
+	RateAuthority *string `json:"rate_authority"`
+	CurrentRate   int16   `json:"current_rate"`
+}
+
+type MetadataPointer struct {
+	Authority       *string `json:"authority"`
+	MetadataAddress *string `json:"metadata_address"`
+}
+
+// TokenMetadata is metadata embedded in the mint by the token metadata extension
+type TokenMetadata struct {
+	UpdateAuthority    *string           `json:"update_authority"`
+	Name               string            `json:"name"`
+	Symbol             string            `json:"symbol"`
+	Uri                string            `json:"uri"`
+	AdditionalMetadata map[string]string `json:"additional_metadata"`
+}
+
+// Mint is the on-chain mint account. Supply is in raw token units and
+// UISupply in whole tokens. A nil authority has been revoked.
+type Mint struct {
+	Supply          uint64  `json:"supply"`
+	UISupply        float64 `json:"ui_supply"`
+	Decimals        uint8   `json:"decimals"`
+	MintAuthority   *string `json:"mint_authority"`
+	FreezeAuthority *string `json:"freeze_authority"`
+	TokenProgram    string  `json:"token_program"`
+}
+
+// BondingCurve is the live state of a pump.fun bonding curve. Reserves are in
+// lamports and raw token units, Progress is the percentage sold towards graduation.
+type BondingCurve struct {
+	VirtualSolReserves   uint64  `json:"virtual_sol_reserves"`
+	VirtualTokenReserves uint64  `json:"virtual_token_reserves"`
+	RealSolReserves      uint64  `json:"real_sol_reserves"`
+	RealTokenReserves    uint64  `json:"real_token_reserves"`
+	RealTokenReservesUI  float64 `json:"real_token_reserves_ui"`
+	PriceSol             float64 `json:"price_sol"`
+	MarketCapSol         float64 `json:"market_cap_sol"`
+	Progress             float64 `json:"progress"`
+	Complete             bool    `json:"complete"`
+	// Creator is empty for curves created before creator fees existed
+	Creator string `json:"creator,omitempty"`
+}
+
+// Holder is a wallet or program holding the token. Balance is in raw token
+// units and UIBalance in whole tokens. Label names known holders such as
+// pools, the bonding curve, burn addresses, the creator and exchanges.
+type Holder struct {
+	Owner      string  `json:"owner"`
+	Balance    uint64  `json:"balance"`
+	UIBalance  float64 `json:"ui_balance"`
+	Percentage float64 `json:"percentage"`
+	Label      string  `json:"label,omitempty"`
 }
 
 type Metadata struct {
@@ -41,11 +181,39 @@ type MetadataCreator struct {
 	Share    uint8            `json:"share"`
 }
 
+// OffchainMetadata is the JSON document a token's metadata URI points to.
+// Uri and Image are rewritten to the configured IPFS and Arweave gateways.
+type OffchainMetadata struct {
+	Uri         string            `json:"uri"`
+	Name        string            `json:"name"`
+	Symbol      string            `json:"symbol"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	ExternalURL string            `json:"external_url"`
+	Socials     []Social          `json:"socials"`
+	Extensions  map[string]string `json:"extensions"`
+	Attributes  json.RawMessage   `json:"attributes,omitempty"`
+	Properties  json.RawMessage   `json:"properties,omitempty"`
+}
+
 type Social struct {
 	Type string `json:"type"`
 	URL  string `json:"url"`
 }
 
+// Pool is a DEX pair trading the token. Prices are of the token, in USD
+// and in the token it is paired with.
+type Pool struct {
+	DexID        string  `json:"dex_id"`
+	PairAddress  string  `json:"pair_address"`
+	URL          string  `json:"url"`
+	PairedToken  string  `json:"paired_token"`
+	PairedSymbol string  `json:"paired_symbol"`
+	LiquidityUSD float64 `json:"liquidity_usd"`
+	PriceUSD     float64 `json:"price_usd"`
+	PriceNative  float64 `json:"price_native"`
+}
+
 type PriceChange struct {
 	H24 float64 `json:"h24"`
 	H6  float64 `json:"h6"`
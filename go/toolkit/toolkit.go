@@ -4,6 +4,8 @@ import (
 	"github.com/gagliardetto/solana-go/rpc"
 
 	"github.com/soralabs/solana-toolkit/go/onchain_actions"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
+	"github.com/soralabs/solana-toolkit/go/rpc_pool"
 	"github.com/soralabs/solana-toolkit/go/token_information"
 	"github.com/soralabs/solana-toolkit/go/transaction_information"
 	"github.com/soralabs/solana-toolkit/go/wallet_information"
@@ -11,27 +13,41 @@ import (
 )
 
 func New(rpcUrl string) (*toolkit.Toolkit, error) {
-	rpcClient := rpc.New(rpcUrl)
+	return NewWithClient(rpc.New(rpcUrl))
+}
+
+// NewWithPool creates the toolkit with every tool sending its calls through the pool
+func NewWithPool(pool *rpc_pool.Pool) (*toolkit.Toolkit, error) {
+	return NewWithClient(pool.Client())
+}
 
+// NewWithClient creates the toolkit with every tool sharing rpcClient
+func NewWithClient(rpcClient *rpc.Client) (*toolkit.Toolkit, error) {
 	tk := toolkit.NewToolkit(
 		toolkit.WithToolkitName("solana-toolkit"),
 		toolkit.WithToolkitDescription("A toolkit for Solana operations"),
 	)
 
+	// The tools share one price oracle and its cache
+	oracle := price_oracle.NewDefault(rpcClient)
+
 	// Initialize and add all tools
 	transactionTool, err := transaction_information.NewTransactionInformationTool(rpcClient)
 	if err != nil {
 		return nil, err
 	}
-	walletTool, err := wallet_information.NewWalletInformationTool(rpcClient)
+	walletTool, err := wallet_information.NewWalletInformationTool(rpcClient, wallet_information.WithPriceOracle(oracle))
+	if err != nil {
+		return nil, err
+	}
+	onchainTool, err := onchain_actions.NewOnchainActionsTool(rpcClient, onchain_actions.WithPriceOracle(oracle))
 	if err != nil {
 		return nil, err
 	}
-	onchainTool, err := onchain_actions.NewOnchainActionsTool(rpcClient)
+	tokenTool, err := token_information.NewTokenInformationTool(rpcClient, token_information.WithPriceOracle(oracle))
 	if err != nil {
 		return nil, err
 	}
-	tokenTool, err := token_information.NewTokenInformationTool(rpcClient)
 
 	// Register tools
 	if err := tk.RegisterTool(transactionTool); err != nil {
@@ -50,6 +50,21 @@ type Holding struct {
 	EndHoldingAt        *int64   `json:"end_holding_at"`
 	Liquidity           string   `json:"liquidity"`
 	WalletTokenTags     []string `json:"wallet_token_tags"`
+	// OraclePriceUSD and OracleValueUSD are the price oracle's valuation,
+	// zero when the oracle has no price
+	OraclePriceUSD float64 `json:"oracle_price_usd,omitempty"`
+	OracleValueUSD float64 `json:"oracle_value_usd,omitempty"`
+}
+
+// Valuation values the on-chain SOL balance and the holdings at price oracle
+// prices. Unpriced lists the holdings the oracle could not price.
+type Valuation struct {
+	SolBalance    float64  `json:"sol_balance"`
+	SolPriceUSD   float64  `json:"sol_price_usd"`
+	SolValueUSD   float64  `json:"sol_value_usd"`
+	TokenValueUSD float64  `json:"token_value_usd"`
+	TotalValueUSD float64  `json:"total_value_usd"`
+	Unpriced      []string `json:"unpriced"`
 }
 
 type WalletInformationOutput struct {
@@ -98,4 +113,7 @@ type WalletInformationOutput struct {
 
 	// Holdings
 	Holdings []Holding `json:"holdings"`
+
+	// Valuation is nil when the SOL balance could not be read
+	Valuation *Valuation `json:"valuation,omitempty"`
 }
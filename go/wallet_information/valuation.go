@@ -0,0 +1,69 @@
+package wallet_information
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
+)
+
+type Option func(*WalletInformationTool)
+
+// WithPriceOracle values wallets with oracle instead of the default one
+func WithPriceOracle(oracle *price_oracle.Oracle) Option {
+	return func(t *WalletInformationTool) {
+		t.priceOracle = oracle
+	}
+}
+
+// value prices the on-chain SOL balance and the holdings with the price
+// oracle, filling in the oracle price and value of each holding
+func (t *WalletInformationTool) value(ctx context.Context, wallet solana.PublicKey, holdings []Holding) (*Valuation, error) {
+	lamports, err := t.rpcClient.GetBalance(ctx, wallet, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SOL balance: %w", err)
+	}
+
+	mints := []solana.PublicKey{solana.SolMint}
+	holdingMints := make([]solana.PublicKey, len(holdings))
+	for i, holding := range holdings {
+		address := holding.Token.TokenAddress
+		if address == "" {
+			address = holding.Token.Address
+		}
+		if mint, err := solana.PublicKeyFromBase58(address); err == nil {
+			holdingMints[i] = mint
+			mints = append(mints, mint)
+		}
+	}
+	prices := t.priceOracle.Prices(ctx, mints)
+
+	valuation := &Valuation{
+		SolBalance: float64(lamports.Value) / float64(solana.LAMPORTS_PER_SOL),
+		Unpriced:   make([]string, 0),
+	}
+	if price, ok := prices[solana.SolMint]; ok {
+		valuation.SolPriceUSD = price.USD
+		valuation.SolValueUSD = valuation.SolBalance * price.USD
+	}
+
+	for i := range holdings {
+		holding := &holdings[i]
+		price, ok := prices[holdingMints[i]]
+		if !ok {
+			valuation.Unpriced = append(valuation.Unpriced, holding.Token.Address)
+			continue
+		}
+
+		balance, _ := strconv.ParseFloat(holding.Balance, 64)
+		holding.OraclePriceUSD = price.USD
+		holding.OracleValueUSD = balance * price.USD
+		valuation.TokenValueUSD += holding.OracleValueUSD
+	}
+	valuation.TotalValueUSD = valuation.SolValueUSD + valuation.TokenValueUSD
+
+	return valuation, nil
+}
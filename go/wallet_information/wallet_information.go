@@ -12,6 +12,7 @@ import (
 	"github.com/gagliardetto/solana-go/rpc"
 
 	"github.com/soralabs/solana-toolkit/go/internal/gmgn"
+	"github.com/soralabs/solana-toolkit/go/price_oracle"
 	toolkit "github.com/soralabs/toolkit/go"
 
 	tls_client "github.com/bogdanfinn/tls-client"
@@ -25,9 +26,11 @@ type WalletInformationTool struct {
 	rpcClient *rpc.Client
 
 	gmgnClient *gmgn.GMGN
+
+	priceOracle *price_oracle.Oracle
 }
 
-func NewWalletInformationTool(rpcClient *rpc.Client) (*WalletInformationTool, error) {
+func NewWalletInformationTool(rpcClient *rpc.Client, opts ...Option) (*WalletInformationTool, error) {
 	jar := tls_client.NewCookieJar()
 	options := []tls_client.HttpClientOption{
 		tls_client.WithTimeoutSeconds(30),
@@ -41,10 +44,17 @@ func NewWalletInformationTool(rpcClient *rpc.Client) (*WalletInformationTool, er
 		return nil, err
 	}
 
-	return &WalletInformationTool{
-		rpcClient:  rpcClient,
-		gmgnClient: gmgn.New(client),
-	}, nil
+	t := &WalletInformationTool{
+		rpcClient:   rpcClient,
+		gmgnClient:  gmgn.New(client),
+		priceOracle: price_oracle.NewDefault(rpcClient),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
 }
 
 func (t *WalletInformationTool) GetName() string {
@@ -103,6 +113,9 @@ func (t *WalletInformationTool) Execute(ctx context.Context, params json.RawMess
 		}
 	}
 
+	// The valuation is on top of gmgn's own figures, so it may fail alone
+	valuation, _ := t.value(ctx, wallet, convertedHoldings)
+
 	output := WalletInformationOutput{
 		// Wallet Address
 		Wallet: wallet.String(),
@@ -156,6 +169,9 @@ func (t *WalletInformationTool) Execute(ctx context.Context, params json.RawMess
 
 		// Add holdings information
 		Holdings: convertedHoldings,
+
+		// Price oracle valuation
+		Valuation: valuation,
 	}
 
 	return json.Marshal(output)